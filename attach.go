@@ -0,0 +1,157 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Attach associates the qlm instance other with db under the name alias. This
+// allows data in the other database, for example an archive file, to be used
+// in reports together with data in db. See AttachLoad() and RetrieveMerged().
+// The caller remains responsible for closing other.
+func (db *DbType) Attach(other *DbType, alias string) {
+	if db.err != nil {
+		return
+	}
+	if other == nil || other.Hnd == nil {
+		db.SetErrorf("database attached as %s is not open", alias)
+	} else if _, ok := db.attachMap[alias]; ok {
+		db.SetErrorf("alias %s is already attached", alias)
+	} else {
+		db.attachMap[alias] = other
+	}
+}
+
+// Detach drops the tables that were loaded with AttachLoad() for the database
+// attached as alias and removes the association.
+func (db *DbType) Detach(alias string) {
+	if db.err != nil {
+		return
+	}
+	if _, ok := db.attachMap[alias]; ok {
+		tblList := db.attachTblMap[alias]
+		if len(tblList) > 0 {
+			db.TransactBegin()
+			for _, tblStr := range tblList {
+				_, _ = db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s;", tblStr))
+			}
+			db.transactEnd(db.err == nil)
+		}
+		if db.err == nil {
+			delete(db.attachMap, alias)
+			delete(db.attachTblMap, alias)
+		}
+	} else {
+		db.SetErrorf("alias %s is not attached", alias)
+	}
+}
+
+// AttachLoad copies the records of the type pointed to by recPtr that satisfy
+// tailStr and its parameters from the database attached as alias into a
+// table in db. The copy is a snapshot taken at the time of the call, not a
+// live view of the attached database. The table is named by joining the
+// reserved prefix "qlm_attach_", the alias and the table name with
+// underscores, for example "qlm_attach_arc_rec", preceded by any prefix
+// established with SetTablePrefix(). It has the same columns as the source
+// table plus the int64 column RemoteID that holds the identifier of the
+// record in the attached database. The table is replaced by subsequent calls
+// with the same alias and record type, and it is dropped by Detach(). An
+// error is set, and nothing is dropped, if a table of that name exists in db
+// that was not loaded by an earlier call. The table is an ordinary table of
+// db, so it remains in the file if Detach() is not called, for example after
+// a crash, and must then be dropped by the application. After loading, the
+// table can be used in statements passed to Exec(), for example in a join
+// with local tables.
+func (db *DbType) AttachLoad(alias string, recPtr interface{}, tailStr string, prms ...interface{}) {
+	if db.err != nil {
+		return
+	}
+	other, ok := db.attachMap[alias]
+	if !ok {
+		db.SetErrorf("alias %s is not attached", alias)
+		return
+	}
+	dsc := db.dscFromPtr(recPtr)
+	if db.err == nil {
+		if len(dsc.soft.nameStr) > 0 {
+			tailStr = whereAnd(tailStr, dsc.soft.nameStr+" IS NULL")
+		}
+		tblStr := db.tblPrefix + "qlm_attach_" + alias + "_" + strings.TrimPrefix(dsc.tblStr, db.tblPrefix)
+		selStr := fmt.Sprintf("SELECT id(), %s FROM %s%s;",
+			dsc.insert.nameStr, dsc.fromStr(), prePad(tailStr))
+		insStr := fmt.Sprintf("INSERT INTO %s (RemoteID, %s) VALUES (?%d, %s);",
+			tblStr, dsc.insert.nameStr, len(dsc.insert.nameList)+1, dsc.insert.qmStr)
+		loaded := false
+		for _, str := range db.attachTblMap[alias] {
+			loaded = loaded || str == tblStr
+		}
+		db.TransactBegin()
+		if loaded {
+			_, _ = db.Exec(fmt.Sprintf("DROP TABLE %s;", tblStr))
+		} else if db.tableExists(tblStr) {
+			db.SetErrorf("table %s exists and was not loaded by AttachLoad", tblStr)
+		}
+		_, _ = db.Exec(fmt.Sprintf("CREATE TABLE %s (RemoteID int64, %s);",
+			tblStr, dsc.create.nameTypeStr))
+		if db.err == nil {
+			rs, _ := other.Exec(selStr, prms...)
+			if other.err == nil {
+				load := func(data []interface{}) (more bool, err error) {
+					// Move the remote identifier to the end to match insStr
+					_, _ = db.Exec(insStr, append(data[1:], data[0])...)
+					return db.err == nil, nil
+				}
+				for _, res := range rs {
+					if db.err == nil && other.err == nil {
+						other.err = res.Do(false, load)
+					}
+				}
+			}
+			db.SetError(other.err)
+		}
+		db.transactEnd(db.err == nil)
+		if db.err == nil {
+			db.attachTblMap[alias] = strListMerge(db.attachTblMap[alias], []string{tblStr})
+		}
+	}
+}
+
+// RetrieveMerged calls Retrieve() on db and then on each attached database in
+// alias order, appending all of the results to the slice pointed to by
+// slicePtr. The order of records within the merged slice follows any ORDER BY
+// clause in tailStr only within the portion that comes from each database.
+func (db *DbType) RetrieveMerged(slicePtr interface{}, tailStr string, prms ...interface{}) {
+	if db.err != nil {
+		return
+	}
+	db.Retrieve(slicePtr, tailStr, prms...)
+	var aliasList []string
+	for alias := range db.attachMap {
+		aliasList = append(aliasList, alias)
+	}
+	sort.Strings(aliasList)
+	for _, alias := range aliasList {
+		if db.err == nil {
+			other := db.attachMap[alias]
+			other.Retrieve(slicePtr, tailStr, prms...)
+			db.SetError(other.err)
+		}
+	}
+}
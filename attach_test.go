@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates reporting across a live database and an archive
+// database. RetrieveMerged() gathers records from both files. AttachLoad()
+// copies selected archive records into a table in the live database so that
+// they can be joined with local tables. It does not replace a table that it
+// did not create.
+func ExampleDbType_Attach() {
+	type orderType struct {
+		ID   int64  `ql_table:"orders"`
+		Cust string `ql:"*"`
+		Amt  int64  `ql:"*"`
+	}
+	type custType struct {
+		ID   int64  `ql_table:"cust"`
+		Cust string `ql:"*"`
+		City string `ql:"*"`
+	}
+	arc := qlm.DbCreate("data/archive.ql")
+	arc.TableCreate(&orderType{})
+	arc.Insert([]orderType{{0, "Athos", 10}, {0, "Porthos", 20}})
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&orderType{})
+	db.TableCreate(&custType{})
+	db.Insert([]orderType{{0, "Aramis", 30}})
+	db.Insert([]custType{{0, "Athos", "Paris"}, {0, "Porthos", "Pierrefonds"}})
+	db.Attach(arc, "arc")
+	var list []orderType
+	db.RetrieveMerged(&list, "ORDER BY Cust")
+	for _, r := range list {
+		fmt.Printf("%s %d\n", r.Cust, r.Amt)
+	}
+	db.AttachLoad("arc", &orderType{}, "WHERE Amt > ?1", int64(15))
+	rs, _ := db.Exec("SELECT a.Cust, cust.City, a.Amt " +
+		"FROM qlm_attach_arc_orders AS a, cust WHERE a.Cust == cust.Cust;")
+	if db.OK() {
+		rs[0].Do(false, func(data []interface{}) (bool, error) {
+			fmt.Println(data...)
+			return true, nil
+		})
+	}
+	db.AttachLoad("arc", &orderType{}, "")
+	fmt.Println(db.Error())
+	db.Detach("arc")
+	db.TransactBegin()
+	db.Exec("CREATE TABLE qlm_attach_arc_orders (Note string);")
+	db.TransactCommit()
+	db.Attach(arc, "arc")
+	db.AttachLoad("arc", &orderType{}, "")
+	fmt.Println(db.Error())
+	db.ClearError()
+	db.Close()
+	arc.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// Aramis 30
+	// Athos 10
+	// Porthos 20
+	// Porthos Pierrefonds 20
+	// <nil>
+	// table qlm_attach_arc_orders exists and was not loaded by AttachLoad
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// AuditType is an entry of the audit trail that is kept for the tables named
+// with AuditEnable(). The entries can be retrieved like any other records,
+// for example
+//
+//	db.Retrieve(&list, "WHERE Tbl == ?1 && RecID == ?2 ORDER BY id()", "acct", id)
+type AuditType struct {
+	ID    int64     `ql_table:"qlm_audit"`
+	Tm    time.Time `ql:"*"`
+	Table string    `ql:"Tbl" ql_index:"*"`
+	Op    string    `ql:"*"` // ChangeInsert, ChangeUpdate, ChangeDelete or ChangeTruncate
+	RecID int64     `ql:"*"` // Identifier of the affected record; zero for ChangeTruncate
+	Data  string    `ql:"*"` // JSON object of the columns that were stored, keyed by column name
+}
+
+// AuditEnable begins keeping an audit trail of the table of the record type
+// pointed to by recPtr. Each record that is subsequently inserted, updated or
+// deleted by qlm results in an entry in the table qlm_audit, made in the same
+// transaction as the change itself. The Data field of an entry holds the
+// values of the columns that were stored: all columns for an insertion and
+// the columns named in the call to Update(), including those maintained by
+// qlm, for an update. Changes made in other ways, for example by Increment(),
+// record all columns. Data is empty for deletions. Values are encoded as they
+// are in the change journal, so complex and non-finite values do not prevent
+// the change from being recorded. Changes made with Exec or ExecScript are not
+// recorded.
+func (db *DbType) AuditEnable(recPtr interface{}) {
+	dsc := db.dscFromPtr(recPtr)
+	if db.writable(dsc) {
+		if dsc.recTp == reflect.TypeOf(AuditType{}) {
+			db.SetErrorf("audit table cannot itself be audited")
+			return
+		}
+		db.TableEnsure(&AuditType{})
+		if db.err == nil {
+			db.auditMap[dsc.tblStr] = true
+		}
+	}
+}
+
+// audited returns true if changes to the records described by dsc are to be
+// recorded in the audit trail.
+func (db *DbType) audited(dsc qlDscType) bool {
+	return db.err == nil && db.auditMap[dsc.tblStr]
+}
+
+// auditRec records the operation opStr on the record recVl that has the
+// identifier id. The values of the columns in nameList, or all columns if
+// nameList is empty, are recorded if recVl is valid. This method must be
+// called within a transaction.
+func (db *DbType) auditRec(dsc qlDscType, opStr string, id int64, recVl reflect.Value, nameList []string) {
+	rec := AuditType{Tm: time.Now(), Table: dsc.tblStr, Op: opStr, RecID: id}
+	if recVl.IsValid() {
+		if len(nameList) == 0 {
+			nameList = dsc.insert.nameList
+		}
+		rec.Data = colDataEncode(dsc, recVl, nameList)
+	}
+	if db.err == nil {
+		adsc := db.dscFromType(reflect.TypeOf(rec))
+		if db.err == nil {
+			_, _ = db.Exec(fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s);", adsc.tblStr,
+				adsc.insert.nameStr, adsc.insert.qmStr), valList(reflect.ValueOf(&rec).Elem(), adsc.insert.sfList)...)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+	"math"
+)
+
+// This example demonstrates the audit trail. A non-finite value, which JSON
+// cannot represent as a number, is recorded as a string.
+func ExampleDbType_AuditEnable() {
+	type acctType struct {
+		ID      int64   `ql_table:"acct"`
+		Name    string  `ql:"*"`
+		Balance int64   `ql:"*"`
+		Rate    float64 `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&acctType{})
+	db.AuditEnable(&acctType{})
+	list := []acctType{{0, "Dana", 100, 0.5}}
+	db.Insert(list)
+	list[0].Balance = 250
+	list[0].Rate = math.Inf(1)
+	db.Update(&list[0], "Balance", "Rate")
+	db.Delete(&acctType{}, "WHERE id() == ?1", list[0].ID)
+	var trail []qlm.AuditType
+	db.Retrieve(&trail, "WHERE Tbl == ?1 ORDER BY id()", "acct")
+	for _, rec := range trail {
+		fmt.Printf("%s %-6s %v %s\n", rec.Table, rec.Op, rec.RecID == list[0].ID, rec.Data)
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// acct insert true {"Balance":100,"Name":"Dana","Rate":0.5}
+	// acct update true {"Balance":250,"Rate":"+Inf"}
+	// acct delete true
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"reflect"
+)
+
+// AutoMap determines whether the exported fields of a record type that do not
+// have a "ql" tag are stored. If on is true, each such field whose type ql
+// supports directly is stored as though it had the tag `ql:"*"`, so its
+// column name is derived by the naming strategy established with SetNamer().
+// A field can be excluded with the tag `ql:"-"`. The "ql_table" tag is still
+// required to name the table. For example, after
+//
+//	db.AutoMap(true)
+//
+// the following type is stored in the table person with the columns Name
+// and Age:
+//
+//	type personType struct {
+//		ID    int64 `ql_table:"person"`
+//		Name  string
+//		Age   int64
+//		Notes []string `ql:"-"`
+//	}
+//
+// AutoMap should be called before any record types are used with db. It does
+// not affect sessions made from db, nor does a session that calls it affect
+// db. By default, only fields with a "ql" tag are stored.
+func (db *DbType) AutoMap(on bool) {
+	if db.err == nil {
+		db.autoMap = on
+		db.dscCache = dscCacheNew() // Rebuild descriptors for db alone
+	}
+}
+
+// autoName returns the column name of the field sf of the record type recTp
+// given nameStr, the name in its "ql" tag. An empty string is returned if the
+// field is not stored. The name "*" is returned for an untagged field that is
+// stored because of AutoMap().
+func (db *DbType) autoName(recTp reflect.Type, sf reflect.StructField, nameStr string) string {
+	switch {
+	case nameStr == "-":
+		return ""
+	case len(nameStr) > 0 || !db.autoMap:
+		return nameStr
+	case len(sf.PkgPath) > 0 || len(sf.Tag.Get("ql_table")) > 0 || recTp.PkgPath() == qlmPkgStr:
+		return ""
+	case typeMap[qlTypeStr(sf.Type)]:
+		return "*"
+	}
+	return ""
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+	"strings"
+)
+
+// This example demonstrates the storage of untagged fields.
+func ExampleDbType_AutoMap() {
+	type personType struct {
+		ID       int64 `ql_table:"person"`
+		Name     string
+		GroupNum int64
+		Notes    []string `ql:"-"`
+		Tags     map[string]string
+		Secret   string `ql:"-"`
+		note     string
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.AutoMap(true)
+	db.SetNamer(qlm.SnakeCase)
+	db.TableCreate(&personType{})
+	db.Insert([]personType{{Name: "Ann", GroupNum: 3, Notes: []string{"x"}, Secret: "s", note: "n"}})
+	var strList []string
+	for _, col := range db.Columns("person") {
+		strList = append(strList, col.Name)
+	}
+	fmt.Println(strings.Join(strList, " "))
+	var list []personType
+	db.Retrieve(&list, "WHERE group_num == ?1", int64(3))
+	fmt.Printf("%d %s %d %v [%s] [%s]\n", len(list), list[0].Name, list[0].GroupNum,
+		list[0].Notes, list[0].Secret, list[0].note)
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// name group_num
+	// 1 Ann 3 [] [] []
+}
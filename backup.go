@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"io"
+	"os"
+)
+
+// Backup writes a consistent copy of the database to the new file destStr.
+// The directory path to the file will be created if needed; an existing file
+// at destStr is not overwritten. The copy is made within a transaction, so
+// other writers are held off while the file is copied and resume afterward
+// without the database having to be closed. Only committed changes are
+// included in the copy; changes made in a transaction that is pending when
+// Backup is called are not. The copy is an ordinary ql database that can be
+// opened with DbOpen(). Backup requires a database stored in a file.
+func (db *DbType) Backup(destStr string) {
+	if db.err != nil {
+		return
+	}
+	srcStr := db.Hnd.Name()
+	if _, err := os.Stat(srcStr); err != nil {
+		db.SetErrorf("backup requires a database stored in a file")
+		return
+	}
+	if _, err := os.Stat(destStr); err == nil {
+		db.SetErrorf("backup file %s already exists", destStr)
+		return
+	}
+	db.TransactBegin()
+	if db.err == nil {
+		db.err = fileCopy(srcStr, destStr)
+		db.transactEnd(db.err == nil)
+	}
+}
+
+// fileCopy copies the file srcStr to the new file destStr. The copy is made
+// to a temporary file that is renamed when complete, so that an incomplete
+// copy is never left at destStr.
+func fileCopy(srcStr, destStr string) (err error) {
+	var src, dst *os.File
+	if err = dirEnsure(destStr); err != nil {
+		return
+	}
+	if src, err = os.Open(srcStr); err != nil {
+		return
+	}
+	defer src.Close()
+	tmpStr := destStr + ".tmp"
+	if dst, err = os.OpenFile(tmpStr, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644); err != nil {
+		return
+	}
+	_, err = io.Copy(dst, src)
+	if err == nil {
+		err = dst.Sync()
+	}
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmpStr, destStr)
+	}
+	if err != nil {
+		os.Remove(tmpStr)
+	}
+	return
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+	"os"
+)
+
+// This example demonstrates a backup of an open database.
+func ExampleDbType_Backup() {
+	type recType struct {
+		ID   int64  `ql_table:"rec"`
+		Name string `ql:"*"`
+	}
+	os.RemoveAll("data/backup")
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&recType{})
+	db.Insert([]recType{{0, "alpha"}, {0, "beta"}})
+	db.Backup("data/backup/example.ql")
+	// Changes made after the backup are not included in it
+	db.Insert([]recType{{0, "gamma"}})
+	db.Backup("data/backup/example.ql")
+	fmt.Println(db.Error())
+	db.ClearError()
+	db.Close()
+	bk := qlm.DbOpen("data/backup/example.ql")
+	var list []recType
+	bk.Retrieve(&list, "ORDER BY Name")
+	for _, rec := range list {
+		fmt.Println(rec.Name)
+	}
+	bk.Close()
+	if bk.Err() {
+		fmt.Println(bk.Error())
+	}
+	// Output:
+	// backup file data/backup/example.ql already exists
+	// alpha
+	// beta
+}
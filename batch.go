@@ -0,0 +1,126 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// batchTail returns the tail clause that selects, in order of their
+// identifiers, at most batchSize records that satisfy the WHERE clause
+// tailStr and that follow the record whose identifier is passed as parameter
+// prmCount+1. fncStr names the calling function in error messages.
+func (db *DbType) batchTail(fncStr, tailStr string, prmCount, batchSize int) (str string) {
+	whereStr, restStr := tailSplit(tailStr)
+	if len(restStr) > 0 {
+		db.SetErrorf("function %s accepts only a WHERE clause, got %s", fncStr, restStr)
+		return
+	}
+	if batchSize < 1 {
+		db.SetErrorf("batch size must be positive, got %d", batchSize)
+		return
+	}
+	condStr := fmt.Sprintf("id() > ?%d", prmCount+1)
+	if len(whereStr) > 0 {
+		condStr = fmt.Sprintf("%s && (%s)", condStr, whereStr)
+	}
+	return fmt.Sprintf("WHERE %s ORDER BY id() LIMIT %d", condStr, batchSize)
+}
+
+// RetrieveBatches retrieves the records of the type pointed to by recPtr that
+// satisfy tailStr and its parameters, and passes them to fn in slices of at
+// most batchSize records. The batch argument of fn is a slice of the record
+// type, for example []recType, that may be retained by fn. Records are
+// delivered in order of their identifiers, and each batch is retrieved with a
+// separate query that resumes after the last identifier of the previous
+// batch. Consequently, memory use is bounded by the batch size, and changes
+// that fn makes to the database, each in its own transaction, are committed
+// before the next batch is read. tailStr may contain only a WHERE clause.
+// If fn returns an error, no further batches are retrieved and the error is
+// assigned to the database.
+func (db *DbType) RetrieveBatches(recPtr interface{}, batchSize int,
+	fn func(batch interface{}) error, tailStr string, prms ...interface{}) {
+	if db.err != nil {
+		return
+	}
+	dsc := db.dscFromPtr(recPtr)
+	if db.err != nil {
+		return
+	}
+	tailStr = db.batchTail("RetrieveBatches", tailStr, len(prms), batchSize)
+	if db.err != nil {
+		return
+	}
+	sliceTp := reflect.SliceOf(dsc.recTp)
+	var lastID int64
+	for more := true; more && db.err == nil; {
+		slicePtrVl := reflect.New(sliceTp)
+		db.Retrieve(slicePtrVl.Interface(), tailStr, append(prms[:len(prms):len(prms)], lastID)...)
+		sliceVl := slicePtrVl.Elem()
+		count := sliceVl.Len()
+		if db.err == nil && count > 0 {
+			recVl := sliceVl.Index(count - 1)
+			lastID = fieldValue(recVl, dsc.idSf).Int()
+			db.err = fn(sliceVl.Interface())
+		}
+		more = count == batchSize
+	}
+}
+
+// UpdateAll updates every record in slice, a slice of records such as those
+// obtained with Retrieve(). Each record is identified by its ID field and
+// fldNames are handled as they are in Update(). All records are updated
+// within a single transaction and, because each record is updated with the
+// same statement, the statement is compiled only once. If an error occurs,
+// the transaction is rolled back and no records are changed.
+func (db *DbType) UpdateAll(slice interface{}, fldNames ...string) {
+	if db.err != nil {
+		return
+	}
+	sliceVl := reflect.ValueOf(slice)
+	if sliceVl.Kind() != reflect.Slice {
+		db.SetErrorf("function UpdateAll requires slice as first argument")
+		return
+	}
+	if len(fldNames) == 0 {
+		db.SetErrorf("at least one field name expected in function UpdateAll")
+		return
+	}
+	dsc := db.dscFromType(sliceVl.Type().Elem())
+	if !db.writable(dsc) {
+		return
+	}
+	count := sliceVl.Len()
+	restoreList := make([]func(), 0, count)
+	trk := db.progressStart("UpdateAll", int64(count))
+	db.TransactBegin()
+	for j := 0; j < count && db.err == nil; j++ {
+		restoreList = append(restoreList, db.updateRec(dsc, sliceVl.Index(j), fldNames))
+		if db.err == nil {
+			trk.add(1)
+		}
+	}
+	db.transactEnd(db.err == nil)
+	if db.err == nil {
+		trk.end()
+	} else {
+		for _, restore := range restoreList {
+			restore()
+		}
+	}
+}
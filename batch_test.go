@@ -0,0 +1,107 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the processing of a table in batches. Each batch
+// of pending orders is marked as shipped before the next batch is read.
+func ExampleDbType_RetrieveBatches() {
+	type orderType struct {
+		ID      int64 `ql_table:"order_tbl"`
+		Num     int32 `ql:"*"`
+		Shipped bool  `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&orderType{})
+	var list []orderType
+	for j := 0; j < 25; j++ {
+		list = append(list, orderType{0, int32(j), j%5 == 0})
+	}
+	db.Insert(list)
+	db.RetrieveBatches(&orderType{}, 8, func(batch interface{}) error {
+		orders := batch.([]orderType)
+		fmt.Printf("batch of %d, first %d, last %d\n", len(orders), orders[0].Num, orders[len(orders)-1].Num)
+		for j := range orders {
+			orders[j].Shipped = true
+			db.Update(&orders[j], "Shipped")
+		}
+		return db.Error()
+	}, "WHERE !Shipped")
+	var count int64
+	db.RetrieveBatches(&orderType{}, 10, func(batch interface{}) error {
+		count += int64(len(batch.([]orderType)))
+		return nil
+	}, "WHERE Shipped")
+	fmt.Printf("%d shipped\n", count)
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// batch of 8, first 1, last 9
+	// batch of 8, first 11, last 19
+	// batch of 4, first 21, last 24
+	// 25 shipped
+}
+
+// This example demonstrates the update of a slice of records in one
+// transaction. The second update fails because one of the records is stale,
+// so none of the records are changed.
+func ExampleDbType_UpdateAll() {
+	type itemType struct {
+		ID    int64   `ql_table:"item"`
+		Name  string  `ql:"*"`
+		Price float64 `ql:"*"`
+		Ver   int64   `ql:"*,version"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&itemType{})
+	db.Insert([]itemType{{Name: "bolt", Price: 0.25}, {Name: "nut", Price: 0.10}, {Name: "gear", Price: 4}})
+	var list []itemType
+	db.Retrieve(&list, "ORDER BY id()")
+	for j := range list {
+		list[j].Price *= 2
+	}
+	db.UpdateAll(list, "Price")
+	var stale []itemType
+	db.Retrieve(&stale, "ORDER BY id()")
+	stale[1].Ver--
+	for j := range stale {
+		stale[j].Price = 0
+	}
+	db.UpdateAll(stale, "Price")
+	fmt.Println(db.Error(), stale[0].Ver, stale[1].Ver)
+	db.ClearError()
+	list = nil
+	db.Retrieve(&list, "ORDER BY id()")
+	for _, rec := range list {
+		fmt.Println(rec.Name, rec.Price, rec.Ver)
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// record has been modified since it was retrieved 1 0
+	// bolt 0.5 1
+	// nut 0.2 1
+	// gear 8 1
+}
@@ -0,0 +1,44 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+// RetrieveByID fills the record pointed to by recPtr with the record of its
+// type whose id() is id. True is returned if the record is found. Otherwise,
+// the record is left unchanged and false is returned; unlike RetrieveOne(),
+// the absence of the record is not treated as an error. An error already set,
+// including ErrNotFound from an earlier call, is retained.
+func (db *DbType) RetrieveByID(recPtr interface{}, id int64) (found bool) {
+	if db.err != nil {
+		return
+	}
+	found = db.RetrieveOne(recPtr, "WHERE id() == ?1", id)
+	if db.err == ErrNotFound { // Set by this call, since db.err was nil
+		db.err = nil
+	}
+	return
+}
+
+// DeleteByID removes the records of the type pointed to by recPtr whose id()
+// is one of ids. It is otherwise like Delete(); in particular, records of a
+// type that embeds SoftModel are marked as deleted rather than removed.
+// Nothing is done if ids is empty.
+func (db *DbType) DeleteByID(recPtr interface{}, ids ...int64) {
+	if len(ids) > 0 {
+		condStr, prms := inCond("id()", ids, 0)
+		db.Delete(recPtr, "WHERE "+condStr, prms...)
+	}
+}
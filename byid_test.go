@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the retrieval and removal of records by their
+// identifiers. The identifiers are passed as parameters, so deletions of the
+// same number of records share one statement. RetrieveByID() leaves an
+// earlier error in place.
+func ExampleDbType_RetrieveByID() {
+	type recType struct {
+		ID   int64  `ql_table:"rec"`
+		Name string `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&recType{})
+	list := []recType{{0, "alpha"}, {0, "beta"}, {0, "gamma"}, {0, "delta"}}
+	db.Insert(list)
+	var rec recType
+	fmt.Println(db.RetrieveByID(&rec, list[1].ID), rec.Name)
+	db.DeleteByID(&rec, list[0].ID, list[1].ID, list[3].ID)
+	fmt.Println(db.RetrieveByID(&rec, list[1].ID), rec.Name, db.Error())
+	var all []recType
+	db.Retrieve(&all, "")
+	for _, rec = range all {
+		fmt.Println(rec.Name)
+	}
+	db.DeleteByID(&rec, 100)
+	n := len(db.Stats().List)
+	db.DeleteByID(&rec, 200)
+	fmt.Println(len(db.Stats().List) == n)
+	db.RetrieveOne(&rec, "WHERE Name == ?1", "beta")
+	fmt.Println(db.RetrieveByID(&rec, list[2].ID), db.Error() == qlm.ErrNotFound)
+	db.ClearError()
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// true beta
+	// false beta <nil>
+	// gamma
+	// true
+	// false true
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"container/list"
+	"github.com/cznic/ql"
+	"strings"
+)
+
+// stmtCacheType holds compiled statements keyed by their text. If limit is
+// greater than zero, the least recently used statement is discarded when the
+// cache would otherwise exceed limit statements.
+type stmtCacheType struct {
+	limit   int
+	lru     *list.List // Most recently used at front
+	elemMap map[string]*list.Element
+}
+
+type stmtEntryType struct {
+	cmdStr string
+	list   ql.List
+}
+
+func (c *stmtCacheType) init() {
+	c.lru = list.New()
+	c.elemMap = make(map[string]*list.Element)
+}
+
+// get returns the compiled statement for cmdStr if it is cached.
+func (c *stmtCacheType) get(cmdStr string) (l ql.List, ok bool) {
+	var elem *list.Element
+	elem, ok = c.elemMap[cmdStr]
+	if ok {
+		c.lru.MoveToFront(elem)
+		l = elem.Value.(stmtEntryType).list
+	}
+	return
+}
+
+// put caches the compiled statement l for cmdStr.
+func (c *stmtCacheType) put(cmdStr string, l ql.List) {
+	c.elemMap[cmdStr] = c.lru.PushFront(stmtEntryType{cmdStr, l})
+	c.trim()
+}
+
+// trim discards the least recently used statements in excess of the limit.
+func (c *stmtCacheType) trim() {
+	for c.limit > 0 && c.lru.Len() > c.limit {
+		elem := c.lru.Back()
+		delete(c.elemMap, elem.Value.(stmtEntryType).cmdStr)
+		c.lru.Remove(elem)
+	}
+}
+
+// schemaStmt returns true if l contains a statement that creates, alters or
+// drops a table or index. Such statements are not cached, and their successful
+// execution clears the cache, because the compiled form of other statements
+// may depend on the schema they change.
+func schemaStmt(l ql.List) bool {
+	for _, line := range strings.Split(l.String(), "\n") {
+		line = strings.TrimSpace(line)
+		for _, prefix := range []string{"CREATE ", "ALTER ", "DROP "} {
+			if strings.HasPrefix(line, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CacheLen returns the number of compiled statements that are currently
+// cached by db. Every statement submitted to ql for execution, whether by
+// Exec() or by another qlm method, is compiled once and reused when the same
+// text is submitted again. Statements that change the schema, such as those
+// executed by TableCreate() and SchemaSync(), are not cached, and the cache is
+// cleared when one of them succeeds.
+func (db *DbType) CacheLen() (n int) {
+	if db.shr != nil {
+		db.shr.mu.Lock()
+		n = db.shr.cache.lru.Len()
+		db.shr.mu.Unlock()
+	}
+	return
+}
+
+// CacheClear discards all compiled statements cached by db. This is not
+// normally needed, but it may be useful after the schema has been changed by
+// another process or to release memory.
+func (db *DbType) CacheClear() {
+	if db.shr != nil {
+		db.shr.stmtClear()
+	}
+}
+
+// CacheLimit bounds the number of compiled statements cached by db to n. When
+// the limit is reached, the least recently used statement is discarded. This
+// bounds the memory used by applications that generate many distinct
+// statements, for example from variable tail clauses. A value of zero for n,
+// the default, does not limit the cache.
+func (db *DbType) CacheLimit(n int) {
+	if db.err == nil {
+		if n < 0 {
+			n = 0
+		}
+		db.shr.mu.Lock()
+		db.shr.cache.limit = n
+		db.shr.cache.trim()
+		db.shr.mu.Unlock()
+	}
+}
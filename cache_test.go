@@ -0,0 +1,47 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the management of the statement cache.
+func ExampleDbType_CacheLimit() {
+	type noteType struct {
+		ID   int64  `ql_table:"note"`
+		Text string `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&noteType{})
+	db.CacheLimit(2)
+	var list []noteType
+	for _, tailStr := range []string{"", "ORDER BY Text", "ORDER BY id()", ""} {
+		db.Retrieve(&list, tailStr)
+	}
+	fmt.Println(db.CacheLen())
+	db.CacheClear()
+	fmt.Println(db.CacheLen())
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// 2
+	// 0
+}
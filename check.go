@@ -0,0 +1,39 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+// CheckType validates the qlm tags of the structure type of rec without
+// opening a database. rec may be a record, a pointer to a record, a slice of
+// records or a pointer to a slice of records. The error that would be set by
+// the first qlm operation involving the type is returned; nil is returned if
+// the tags are valid. Problems that are detected include unknown tag options,
+// unsupported field types, unexported managed fields, duplicate column names,
+// a missing or repeated "ql_table" tag, an ID field that is not of type
+// int64, and a rec, such as nil, that is not a structure. This function is well suited to unit tests, where it allows tag
+// mistakes to be caught before the application runs:
+//
+//	func TestTags(t *testing.T) {
+//		if err := qlm.CheckType(&recType{}); err != nil {
+//			t.Fatal(err)
+//		}
+//	}
+func CheckType(rec interface{}) error {
+	db := new(DbType)
+	db.init()
+	db.dscFromType(recTypeOf(rec))
+	return db.err
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+	"time"
+)
+
+// This example demonstrates the validation of record types without a
+// database.
+func ExampleCheckType() {
+	type goodType struct {
+		ID   int64     `ql_table:"good"`
+		Name string    `ql:"*"`
+		Tm   time.Time `ql:"*,ttl=24h"`
+	}
+	type optType struct {
+		ID   int64  `ql_table:"opt"`
+		Name string `ql:"*,unique-ish"`
+	}
+	type dupType struct {
+		ID    int64  `ql_table:"dup"`
+		Name  string `ql:"name"`
+		Alias string `ql:"name"`
+	}
+	type privType struct {
+		ID   int64  `ql_table:"priv"`
+		name string `ql:"*"`
+	}
+	type mapType struct {
+		ID  int64          `ql_table:"map"`
+		Val map[string]int `ql:"*"`
+	}
+	for _, rec := range []interface{}{&goodType{}, []optType{}, dupType{},
+		&[]privType{}, &mapType{}, nil, 42} {
+		fmt.Println(qlm.CheckType(rec))
+	}
+	// Output:
+	// <nil>
+	// unknown option unique-ish in ql tag of field Name
+	// duplicate column name name
+	// field name must be exported
+	// database does not support fields of type map[string]int
+	// specified address must be of structure with one or more fields that have a "ql" tag
+	// specified address must be of structure with one or more fields that have a "ql" tag
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// sumSet records in dsc that the column sqlStr holds the checksum of the
+// record's other columns.
+func (db *DbType) sumSet(dsc *qlDscType, sf reflect.StructField, sqlStr string) {
+	switch {
+	case sf.Type.Kind() != reflect.String:
+		db.SetErrorf("checksum option requires field %s to be of type string", sf.Name)
+	case len(dsc.sum.nameStr) > 0:
+		db.SetErrorf("multiple fields have checksum option")
+	default:
+		dsc.sum.nameStr, dsc.sum.sf = sqlStr, sf
+	}
+}
+
+// sumCompute returns the checksum of the record recVl. The checksum is the
+// hexadecimal SHA-256 digest of a canonical representation of the covered
+// fields.
+func (dsc qlDscType) sumCompute(recVl reflect.Value) string {
+	hash := sha256.New()
+	for j, val := range valList(recVl, dsc.sum.sfList) {
+		switch v := val.(type) {
+		case time.Time:
+			val = v.UTC().Format(time.RFC3339Nano)
+		case big.Int:
+			val = v.String()
+		case big.Rat:
+			val = v.String()
+		}
+		fmt.Fprintf(hash, "%d:%v;", j, val)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// sumAssign sets the checksum field of the record recVl if the record type
+// has one.
+func (dsc qlDscType) sumAssign(recVl reflect.Value) {
+	if len(dsc.sum.nameStr) > 0 {
+		valueList(recVl, []reflect.StructField{dsc.sum.sf})[0].SetString(dsc.sumCompute(recVl))
+	}
+}
+
+// sumVerify returns an error if the record type has a checksum field and its
+// value in recVl does not match the record's content.
+func (dsc qlDscType) sumVerify(recVl reflect.Value) (err error) {
+	if len(dsc.sum.nameStr) > 0 {
+		if valList(recVl, []reflect.StructField{dsc.sum.sf})[0].(string) != dsc.sumCompute(recVl) {
+			id := valList(recVl, []reflect.StructField{dsc.idSf})[0]
+			err = fmt.Errorf("checksum mismatch in record %d of table %s", id, dsc.tblStr)
+		}
+	}
+	return
+}
+
+// derivedRepair recomputes and stores the checksums and collation keys of the
+// records in the table described by dsc that have the identifiers in idList.
+// It is used after statements that modify records without loading them, such
+// as the one issued by Increment. This method must be called within a
+// transaction.
+func (db *DbType) derivedRepair(dsc qlDscType, idList []int64) {
+	if db.err != nil || len(dsc.sum.nameStr) == 0 && len(dsc.collate) == 0 || len(idList) == 0 {
+		return
+	}
+	slicePtrVl := reflect.New(reflect.SliceOf(dsc.recTp))
+	condStr, prms := inCond("id()", idList, 0)
+	db.retrieveResult(slicePtrVl.Interface(), dsc.sel.sfList, 0, nil, fmt.Sprintf(
+		"SELECT %s FROM %s WHERE %s;", dsc.sel.nameStr, dsc.tblStr, condStr), prms...)
+	sliceVl := slicePtrVl.Elem()
+	var fldNames []string
+	for _, col := range dsc.collate {
+		fldNames = append(fldNames, col.nameStr)
+	}
+	for j := 0; j < sliceVl.Len() && db.err == nil; j++ {
+		recVl := sliceVl.Index(j)
+		var eqList []string
+		var args []interface{}
+		if len(dsc.sum.nameStr) > 0 {
+			args = append(args, dsc.sumCompute(recVl))
+			strListAppend(&eqList, "%s = ?1", dsc.sum.nameStr)
+		}
+		eqList, args = dsc.collateUpdate(recVl, fldNames, eqList, args)
+		args = append(args, valList(recVl, []reflect.StructField{dsc.idSf})[0])
+		_, _ = db.Exec(fmt.Sprintf("UPDATE %s %s WHERE id() == ?%d;", dsc.tblStr,
+			strings.Join(eqList, ", "), len(args)), args...)
+	}
+}
+
+// ChecksumVerify examines the records of the type pointed to by recPtr that
+// satisfy tailStr and its parameters, and returns the identifiers of those
+// whose checksum does not match their content. The record type must have a
+// string field with the "checksum" option in its "ql" tag, for example
+//
+//	Sum string `ql:"*,checksum"`
+//
+// qlm maintains the checksum, computed over all other columns except id(),
+// whenever a record is inserted or updated. Retrieve sets an error when it
+// encounters a record with an invalid checksum, which indicates that the
+// record was altered outside of qlm or that the database file is corrupt.
+// Unlike Retrieve, this function examines every record and includes records
+// marked as deleted.
+func (db *DbType) ChecksumVerify(recPtr interface{}, tailStr string, prms ...interface{}) (badList []int64) {
+	if db.err != nil {
+		return
+	}
+	dsc := db.dscFromPtr(recPtr)
+	if db.err == nil && len(dsc.sum.nameStr) == 0 {
+		db.SetErrorf("no field of table %s has checksum option", dsc.tblStr)
+	}
+	if db.err != nil {
+		return
+	}
+	slicePtrVl := reflect.New(reflect.SliceOf(dsc.recTp))
+	db.retrieveResult(slicePtrVl.Interface(), dsc.sel.sfList, 0, func(recVl reflect.Value, data []interface{}) {
+		if dsc.sumVerify(recVl) != nil {
+			badList = append(badList, valList(recVl, []reflect.StructField{dsc.idSf})[0].(int64))
+		}
+	}, fmt.Sprintf("SELECT %s FROM %s%s;", dsc.sel.nameStr, dsc.fromStr(), prePad(tailStr)), prms...)
+	return
+}
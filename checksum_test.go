@@ -0,0 +1,58 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the detection of records that have been altered
+// outside of qlm.
+func ExampleDbType_ChecksumVerify() {
+	type acctType struct {
+		ID      int64  `ql_table:"acct"`
+		Owner   string `ql:"*"`
+		Balance int64  `ql:"*"`
+		Sum     string `ql:"*,checksum"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&acctType{})
+	list := []acctType{{Owner: "ann", Balance: 100}, {Owner: "bob", Balance: 50}}
+	db.Insert(list)
+	list[1].Balance = 75
+	db.Update(&list[1], "Balance")
+	db.Increment(&acctType{}, "Balance", 10, "WHERE Owner == ?1", "ann")
+	fmt.Printf("bad records: %v\n", db.ChecksumVerify(&acctType{}, ""))
+	// Tamper with a record directly
+	db.TransactBegin()
+	db.Exec("UPDATE acct Balance = 1000000 WHERE Owner == \"bob\";")
+	db.TransactCommit()
+	fmt.Printf("bad records: %v\n", db.ChecksumVerify(&acctType{}, ""))
+	list = nil
+	db.Retrieve(&list, "")
+	fmt.Println(db.Error())
+	db.ClearError()
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// bad records: []
+	// bad records: [2]
+	// checksum mismatch in record 2 of table acct
+}
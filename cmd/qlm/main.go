@@ -0,0 +1,412 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+// Command qlm inspects and maintains ql database files. Usage:
+//
+//	qlm tables FILE
+//	qlm schema FILE [TABLE ...]
+//	qlm dump FILE [TABLE ...]
+//	qlm load FILE [SCRIPT]
+//	qlm export-csv FILE TABLE
+//	qlm import-csv FILE TABLE [CSV]
+//	qlm sql FILE
+//
+// tables lists the tables of the database with their columns and indexes.
+// schema writes the statements that create the named tables, or all tables,
+// and their indexes. dump writes a script that reproduces the named tables,
+// or all tables, including their records; load executes such a script, read
+// from SCRIPT or standard input, in a single transaction. export-csv writes
+// the records of a table to standard output in CSV format with a header of
+// column names; import-csv inserts the records of a CSV file, or standard
+// input, with such a header into a table in a single transaction. Values are
+// converted to the types of the columns; empty values other than strings are
+// stored as NULL. sql reads ql statements from standard input and writes
+// their results; a statement may span lines and ends with a semicolon. Each
+// input is executed in a transaction of its own. load, import-csv and sql
+// create the database file if it does not exist.
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"github.com/cznic/ql"
+	"github.com/jung-kurt/qlm"
+	"io"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// cmdType describes a subcommand.
+type cmdType struct {
+	usageStr string // Arguments that follow the subcommand name
+	argMin   int    // Minimum number of arguments, including the file name
+	argMax   int    // Maximum number of arguments; -1 for no limit
+	create   bool   // True if the database is created when missing
+	fn       func(db *qlm.DbType, args []string, r io.Reader, w io.Writer)
+}
+
+var cmdMap = map[string]cmdType{
+	"tables":     {"FILE", 1, 1, false, tablesCmd},
+	"schema":     {"FILE [TABLE ...]", 1, -1, false, schemaCmd},
+	"dump":       {"FILE [TABLE ...]", 1, -1, false, dumpCmd},
+	"load":       {"FILE [SCRIPT]", 1, 2, true, loadCmd},
+	"export-csv": {"FILE TABLE", 2, 2, false, exportCmd},
+	"import-csv": {"FILE TABLE [CSV]", 2, 3, true, importCmd},
+	"sql":        {"FILE", 1, 1, true, sqlCmd},
+}
+
+var cmdList = []string{"tables", "schema", "dump", "load", "export-csv", "import-csv", "sql"}
+
+// fileReader returns the file named by args[pos] opened for reading, or r if
+// args has no such element. The returned function closes the file.
+func fileReader(db *qlm.DbType, args []string, pos int, r io.Reader) (io.Reader, func()) {
+	if pos < len(args) && db.OK() {
+		f, err := os.Open(args[pos])
+		if err != nil {
+			db.SetError(err)
+			return r, func() {}
+		}
+		return f, func() { f.Close() }
+	}
+	return r, func() {}
+}
+
+func tablesCmd(db *qlm.DbType, args []string, r io.Reader, w io.Writer) {
+	for _, tbl := range db.Tables() {
+		fmt.Fprintln(w, tbl.Name)
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		for _, col := range db.Columns(tbl.Name) {
+			conStr := strings.TrimSpace(strIf(col.NotNull, "NOT NULL ", "") +
+				strIf(len(col.Default) > 0, "DEFAULT "+col.Default, ""))
+			fmt.Fprintf(tw, "  %s\t%s%s\n", col.Name, col.Type, strIf(len(conStr) > 0, "\t"+conStr, ""))
+		}
+		tw.Flush()
+		for _, idx := range tbl.Indexes {
+			fmt.Fprintf(w, "  %sindex %s on %s\n", strIf(idx.Unique, "unique ", ""), idx.Name, idx.Column)
+		}
+	}
+}
+
+func schemaCmd(db *qlm.DbType, args []string, r io.Reader, w io.Writer) {
+	wantMap := make(map[string]bool)
+	for _, nameStr := range args[1:] {
+		wantMap[nameStr] = true
+	}
+	for _, tbl := range db.Tables() {
+		if len(wantMap) == 0 || wantMap[tbl.Name] {
+			delete(wantMap, tbl.Name)
+			fmt.Fprintln(w, tbl.Schema)
+			for _, idx := range tbl.Indexes {
+				fmt.Fprintf(w, "CREATE %sINDEX %s ON %s (%s);\n", strIf(idx.Unique, "UNIQUE ", ""),
+					idx.Name, tbl.Name, idx.Column)
+			}
+		}
+	}
+	for _, nameStr := range args[1:] {
+		if wantMap[nameStr] {
+			db.SetErrorf("table %s does not exist", nameStr)
+		}
+	}
+}
+
+func dumpCmd(db *qlm.DbType, args []string, r io.Reader, w io.Writer) {
+	db.Dump(w, args[1:]...)
+}
+
+func loadCmd(db *qlm.DbType, args []string, r io.Reader, w io.Writer) {
+	r, closeFn := fileReader(db, args, 1, r)
+	defer closeFn()
+	db.Load(r)
+}
+
+// valueStr returns the CSV representation of val, a value retrieved from ql.
+func valueStr(val interface{}) (str string) {
+	switch v := val.(type) {
+	case nil:
+	case string:
+		str = v
+	case []byte:
+		str = string(v)
+	case time.Time:
+		str = v.Format(time.RFC3339Nano)
+	default:
+		str = fmt.Sprint(v)
+	}
+	return
+}
+
+func exportCmd(db *qlm.DbType, args []string, r io.Reader, w io.Writer) {
+	db.Columns(args[1]) // Reports a missing table
+	rs, _ := db.Exec(fmt.Sprintf("SELECT * FROM %s ORDER BY id();", args[1]))
+	if db.Err() {
+		return
+	}
+	cw := csv.NewWriter(w)
+	rec, err := rs[0].Fields()
+	if err == nil {
+		err = cw.Write(rec)
+	}
+	if err == nil {
+		err = rs[0].Do(false, func(data []interface{}) (bool, error) {
+			for j, val := range data {
+				rec[j] = valueStr(val)
+			}
+			return true, cw.Write(rec)
+		})
+	}
+	if cw.Flush(); err == nil {
+		err = cw.Error()
+	}
+	db.SetError(err)
+}
+
+// parseValue returns the value of the ql type typeStr represented by str. An
+// empty string results in nil, that is, NULL, for types other than string.
+func parseValue(typeStr, str string) (val interface{}, err error) {
+	if len(str) == 0 && typeStr != "string" {
+		return
+	}
+	var n int64
+	var u uint64
+	var f float64
+	var c complex128
+	switch typeStr {
+	case "string":
+		val = str
+	case "blob":
+		val = []byte(str)
+	case "bool":
+		val, err = strconv.ParseBool(str)
+	case "int8":
+		if n, err = strconv.ParseInt(str, 10, 8); err == nil {
+			val = int8(n)
+		}
+	case "int16":
+		if n, err = strconv.ParseInt(str, 10, 16); err == nil {
+			val = int16(n)
+		}
+	case "int32":
+		if n, err = strconv.ParseInt(str, 10, 32); err == nil {
+			val = int32(n)
+		}
+	case "int64":
+		val, err = strconv.ParseInt(str, 10, 64)
+	case "uint8":
+		if u, err = strconv.ParseUint(str, 10, 8); err == nil {
+			val = uint8(u)
+		}
+	case "uint16":
+		if u, err = strconv.ParseUint(str, 10, 16); err == nil {
+			val = uint16(u)
+		}
+	case "uint32":
+		if u, err = strconv.ParseUint(str, 10, 32); err == nil {
+			val = uint32(u)
+		}
+	case "uint64":
+		val, err = strconv.ParseUint(str, 10, 64)
+	case "float32":
+		if f, err = strconv.ParseFloat(str, 32); err == nil {
+			val = float32(f)
+		}
+	case "float64":
+		val, err = strconv.ParseFloat(str, 64)
+	case "complex64":
+		if c, err = strconv.ParseComplex(str, 64); err == nil {
+			val = complex64(c)
+		}
+	case "complex128":
+		val, err = strconv.ParseComplex(str, 128)
+	case "bigint":
+		if bi, ok := new(big.Int).SetString(str, 10); ok {
+			val = bi
+		} else {
+			err = fmt.Errorf("invalid bigint %q", str)
+		}
+	case "bigrat":
+		if br, ok := new(big.Rat).SetString(str); ok {
+			val = br
+		} else {
+			err = fmt.Errorf("invalid bigrat %q", str)
+		}
+	case "duration":
+		val, err = time.ParseDuration(str)
+	case "time":
+		val, err = time.Parse(time.RFC3339Nano, str)
+	default:
+		err = fmt.Errorf("unsupported type %s", typeStr)
+	}
+	return
+}
+
+func importCmd(db *qlm.DbType, args []string, r io.Reader, w io.Writer) {
+	typeMap := make(map[string]string)
+	for _, col := range db.Columns(args[1]) {
+		typeMap[col.Name] = col.Type
+	}
+	r, closeFn := fileReader(db, args, 2, r)
+	defer closeFn()
+	if db.Err() {
+		return
+	}
+	cr := csv.NewReader(r)
+	hdrList, err := cr.Read()
+	if err != nil {
+		db.SetErrorf("reading CSV header: %s", err)
+		return
+	}
+	var qmList []string
+	for j, nameStr := range hdrList {
+		if _, ok := typeMap[nameStr]; !ok {
+			db.SetErrorf("table %s has no column %s", args[1], nameStr)
+			return
+		}
+		qmList = append(qmList, fmt.Sprintf("?%d", j+1))
+	}
+	cmdStr := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s);", args[1],
+		strings.Join(hdrList, ", "), strings.Join(qmList, ", "))
+	var count int
+	db.Transact(func(tx *qlm.DbType) error {
+		for line := 2; tx.OK(); line++ {
+			rec, err := cr.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			prms := make([]interface{}, len(rec))
+			for j, str := range rec {
+				if prms[j], err = parseValue(typeMap[hdrList[j]], str); err != nil {
+					return fmt.Errorf("CSV line %d, column %s: %s", line, hdrList[j], err)
+				}
+			}
+			tx.Exec(cmdStr, prms...)
+			count++
+		}
+		return nil
+	})
+	if db.OK() {
+		fmt.Fprintf(w, "%d record(s) imported\n", count)
+	}
+}
+
+// resultWrite writes the rows of rs to w in aligned columns.
+func resultWrite(w io.Writer, rs ql.Recordset) (err error) {
+	var nameList []string
+	if nameList, err = rs.Fields(); err == nil {
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(nameList, "\t"))
+		err = rs.Do(false, func(data []interface{}) (bool, error) {
+			strList := make([]string, len(data))
+			for j, val := range data {
+				strList[j] = strIf(val == nil, "NULL", valueStr(val))
+			}
+			fmt.Fprintln(tw, strings.Join(strList, "\t"))
+			return true, nil
+		})
+		tw.Flush()
+	}
+	return
+}
+
+// dmlStmt returns true if stmtStr inserts, updates or deletes records.
+func dmlStmt(stmtStr string) bool {
+	wordStr := strings.ToUpper(strings.Fields(stmtStr + " ")[0])
+	return wordStr == "INSERT" || wordStr == "UPDATE" || wordStr == "DELETE" || wordStr == "TRUNCATE"
+}
+
+func sqlCmd(db *qlm.DbType, args []string, r io.Reader, w io.Writer) {
+	sc := bufio.NewScanner(r)
+	var buf strings.Builder
+	for sc.Scan() {
+		buf.WriteString(sc.Text())
+		buf.WriteString("\n")
+		if !strings.HasSuffix(strings.TrimSpace(buf.String()), ";") {
+			continue
+		}
+		for _, res := range db.ExecScript(buf.String()) {
+			for _, rs := range res.Rs {
+				if err := resultWrite(w, rs); err != nil {
+					db.SetError(err)
+				}
+			}
+			if res.Err == nil && dmlStmt(res.Stmt) {
+				fmt.Fprintf(w, "%d row(s) affected\n", res.RowsAffected)
+			}
+		}
+		if db.Err() {
+			fmt.Fprintln(w, db.Error())
+			db.ClearError()
+		}
+		buf.Reset()
+	}
+	db.SetError(sc.Err())
+}
+
+func strIf(cond bool, aStr, bStr string) string {
+	if cond {
+		return aStr
+	}
+	return bStr
+}
+
+// run executes the subcommand described by args, reading input from r and
+// writing output to w.
+func run(args []string, r io.Reader, w io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("subcommand expected")
+	}
+	cmd, ok := cmdMap[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown subcommand %s", args[0])
+	}
+	nameStr := args[0]
+	args = args[1:]
+	if len(args) < cmd.argMin || (cmd.argMax >= 0 && len(args) > cmd.argMax) {
+		return fmt.Errorf("usage: qlm %s %s", nameStr, cmd.usageStr)
+	}
+	var db *qlm.DbType
+	if cmd.create {
+		db = qlm.DbCreateIfMissing(args[0])
+	} else {
+		db = qlm.DbOpen(args[0])
+	}
+	if db.OK() {
+		cmd.fn(db, args, r, w)
+	}
+	err := db.Error()
+	db.Close()
+	return err
+}
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "qlm: %s\n", err)
+		if _, ok := cmdMap[strings.Join(os.Args[1:2], "")]; !ok {
+			for _, nameStr := range cmdList {
+				fmt.Fprintf(os.Stderr, "  qlm %s %s\n", nameStr, cmdMap[nameStr].usageStr)
+			}
+		}
+		os.Exit(1)
+	}
+}
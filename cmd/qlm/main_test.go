@@ -0,0 +1,84 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// This example demonstrates several subcommands applied to a new database.
+func Example_run() {
+	dbStr := filepath.Join(os.TempDir(), "qlm_example.ql")
+	os.Remove(dbStr)
+	defer os.Remove(dbStr)
+	cmd := func(inStr string, args ...string) {
+		fmt.Printf("$ qlm %s\n", strings.Replace(strings.Join(args, " "), dbStr, "FILE", 1))
+		if err := run(args, strings.NewReader(inStr), os.Stdout); err != nil {
+			fmt.Println(err)
+		}
+	}
+	cmd("CREATE TABLE item (Name string, Qty int32 DEFAULT 1,\n  Price float64);\n"+
+		"CREATE UNIQUE INDEX itemName ON item (Name);\n"+
+		"INSERT INTO item (Name, Qty, Price) VALUES (\"bolt\", 12, 0.25);\n"+
+		"SELECT Name, Qty, Price FROM item; SELECT count(*) FROM item;\n"+
+		"SELECT Missing FROM item;\n", "sql", dbStr)
+	cmd("Name,Price\nnut,0.1\nwasher,\n", "import-csv", dbStr, "item")
+	cmd("", "tables", dbStr)
+	cmd("", "schema", dbStr, "item")
+	cmd("", "export-csv", dbStr, "item")
+	cmd("", "export-csv", dbStr)
+	cmd("", "dump", dbStr, "item")
+	cmd("", "export-csv", dbStr, "missing")
+	// Output:
+	// $ qlm sql FILE
+	// 1 row(s) affected
+	// Name  Qty  Price
+	// bolt  12   0.25
+	//
+	// 1
+	// statement 1 at line 1: unknown field Missing
+	// $ qlm import-csv FILE item
+	// 2 record(s) imported
+	// $ qlm tables FILE
+	// item
+	//   Name   string
+	//   Qty    int32  DEFAULT 1
+	//   Price  float64
+	//   unique index itemName on Name
+	// $ qlm schema FILE item
+	// CREATE TABLE item (Name string, Qty int32 DEFAULT 1, Price float64);
+	// CREATE UNIQUE INDEX itemName ON item (Name);
+	// $ qlm export-csv FILE item
+	// Name,Qty,Price
+	// bolt,12,0.25
+	// nut,1,0.1
+	// washer,1,
+	// $ qlm export-csv FILE
+	// usage: qlm export-csv FILE TABLE
+	// $ qlm dump FILE item
+	// // ql database dump written by qlm
+	// BEGIN TRANSACTION;
+	// CREATE TABLE item (Name string, Qty int32 DEFAULT 1, Price float64);
+	// CREATE UNIQUE INDEX itemName ON item (Name);
+	// INSERT INTO item (Name, Qty, Price) VALUES ("bolt", int32(12), float64(0.25)), ("nut", int32(1), float64(0.1)), ("washer", int32(1), NULL);
+	// COMMIT;
+	// $ qlm export-csv FILE missing
+	// table missing does not exist
+}
@@ -0,0 +1,434 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+// Command qlmgen generates functions that insert, retrieve and update the
+// records of qlm record types without the use of reflection. For services
+// that move many records, this avoids the cost of examining each field of
+// each record at run time.
+//
+// qlmgen is typically run by go generate. Place a directive such as
+//
+//	//go:generate qlmgen -type recType
+//
+// in the file that defines the record type. For each type, three functions
+// are written to a file named after the source file with the suffix
+// "_qlm.go". For a type named recType, these are
+//
+//	func InsertRecType(db *qlm.DbType, list []recType)
+//	func RetrieveRecType(db *qlm.DbType, listPtr *[]recType, tailStr string, prms ...interface{})
+//	func UpdateRecType(db *qlm.DbType, rec *recType)
+//
+// The functions begin with lower case letters if the type is unexported. They
+// behave like the corresponding methods of qlm.DbType, with the sticky error
+// of the database, but they do not perform the work that qlm associates with
+// tag options, hooks, journals, audit trails or subscriptions. For this
+// reason, qlmgen accepts only types whose fields are of the basic ql types,
+// time.Time, time.Duration or []byte, and whose tags have no options other
+// than "unique". Usage:
+//
+//	qlmgen [-type T1,T2] [-o file] [file.go ...]
+//
+// If no files are named, the file in $GOFILE is read. If -type is omitted,
+// functions are generated for every structure type in the files that has a
+// field with the "ql_table" tag.
+//
+// With the -db option, qlmgen instead writes record type definitions for the
+// tables of an existing ql database, as described for qlm.GenerateStructs:
+//
+//	qlmgen -db file.ql [-pkg name] [-o file]
+//
+// The definitions are preceded by a package clause for the package named by
+// -pkg, by default that in $GOPACKAGE or else "main", and the imports they
+// need. They are written to standard output if -o is omitted.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"github.com/jung-kurt/qlm"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// assertMap associates each supported field type with the type of the value
+// that ql returns for a column of that type.
+var assertMap = map[string]string{
+	"bool":          "bool",
+	"byte":          "uint8",
+	"complex128":    "complex128",
+	"complex64":     "complex64",
+	"float32":       "float32",
+	"float64":       "float64",
+	"int":           "int64",
+	"int16":         "int16",
+	"int32":         "int32",
+	"int64":         "int64",
+	"int8":          "int8",
+	"rune":          "int32",
+	"string":        "string",
+	"uint":          "uint64",
+	"uint16":        "uint16",
+	"uint32":        "uint32",
+	"uint64":        "uint64",
+	"uint8":         "uint8",
+	"[]byte":        "[]byte",
+	"time.Duration": "time.Duration",
+	"time.Time":     "time.Time",
+}
+
+type fieldType struct {
+	nameStr   string // Field name, for example "Name"
+	colStr    string // Column name
+	typeStr   string // Field type, for example "int"
+	assertStr string // Type of the value returned by ql, for example "int64"
+}
+
+type recType struct {
+	nameStr string // Type name
+	tblStr  string // Table name from the "ql_table" tag
+	idStr   string // Name of the ID field
+	fldList []fieldType
+}
+
+// exprStr returns the source text of the type expression expr.
+func exprStr(expr ast.Expr) (str string) {
+	switch x := expr.(type) {
+	case *ast.Ident:
+		str = x.Name
+	case *ast.SelectorExpr:
+		str = exprStr(x.X) + "." + x.Sel.Name
+	case *ast.ArrayType:
+		if x.Len == nil {
+			str = "[]" + exprStr(x.Elt)
+		}
+	}
+	return
+}
+
+// fieldTag returns the tag of the structure field fld.
+func fieldTag(fld *ast.Field) (tag reflect.StructTag) {
+	if fld.Tag != nil {
+		str, _ := strconv.Unquote(fld.Tag.Value)
+		tag = reflect.StructTag(str)
+	}
+	return
+}
+
+// recParse returns the description of the structure type nameStr. ok is
+// false, and no error is reported, if the structure has no field with the
+// "ql_table" tag.
+func recParse(nameStr string, st *ast.StructType) (rec recType, ok bool, err error) {
+	for _, fld := range st.Fields.List {
+		if len(fieldTag(fld).Get("ql_table")) > 0 {
+			ok = true
+		}
+	}
+	if !ok {
+		return
+	}
+	rec.nameStr = nameStr
+	for _, fld := range st.Fields.List {
+		tag := fieldTag(fld)
+		colStr, tblStr := tag.Get("ql"), tag.Get("ql_table")
+		if len(colStr) == 0 && len(tblStr) == 0 {
+			continue
+		}
+		if len(fld.Names) == 0 {
+			err = fmt.Errorf("%s: embedded field %s is not supported", nameStr, exprStr(fld.Type))
+			return
+		}
+		typeStr := exprStr(fld.Type)
+		for _, id := range fld.Names {
+			switch {
+			case !id.IsExported():
+				err = fmt.Errorf("%s: field %s must be exported", nameStr, id.Name)
+			case len(tblStr) > 0:
+				if len(rec.tblStr) > 0 {
+					err = fmt.Errorf("%s: multiple occurrence of ql_table tag", nameStr)
+				} else if typeStr != "int64" {
+					err = fmt.Errorf("%s: expecting int64 for id, got %s", nameStr, typeStr)
+				}
+				rec.tblStr, rec.idStr = tblStr, id.Name
+			default:
+				optList := strings.Split(colStr, ",")
+				colStr := strings.TrimSpace(optList[0])
+				if colStr == "*" {
+					colStr = id.Name
+				}
+				for _, optStr := range optList[1:] {
+					if optStr = strings.TrimSpace(optStr); len(optStr) > 0 && optStr != "unique" {
+						err = fmt.Errorf("%s: option %s of field %s requires reflection", nameStr, optStr, id.Name)
+					}
+				}
+				assertStr, found := assertMap[typeStr]
+				if !found {
+					err = fmt.Errorf("%s: type of field %s is not supported", nameStr, id.Name)
+				}
+				rec.fldList = append(rec.fldList, fieldType{id.Name, colStr, typeStr, assertStr})
+			}
+			if err != nil {
+				return
+			}
+		}
+	}
+	if len(rec.fldList) == 0 {
+		err = fmt.Errorf(`%s: no structure fields have "ql" tag`, nameStr)
+	}
+	return
+}
+
+// funcName returns the name of the generated function that performs opStr,
+// for example "Insert", for the type nameStr. The name is exported only if
+// the type is.
+func funcName(opStr, nameStr string) string {
+	if !token.IsExported(nameStr) {
+		opStr = strings.ToLower(opStr)
+	}
+	rn := []rune(nameStr)
+	rn[0] = unicode.ToUpper(rn[0])
+	return opStr + string(rn)
+}
+
+// recWrite writes the functions generated for rec to buf.
+func recWrite(buf *bytes.Buffer, rec recType) {
+	name := func(opStr string) string {
+		return funcName(opStr, rec.nameStr)
+	}
+	var colList, qmList, prmList, setList []string
+	for j, fld := range rec.fldList {
+		colList = append(colList, fld.colStr)
+		qmList = append(qmList, fmt.Sprintf("?%d", j+1))
+		setList = append(setList, fmt.Sprintf("%s = ?%d", fld.colStr, j+1))
+		switch fld.typeStr {
+		case "int", "uint":
+			prmList = append(prmList, fmt.Sprintf("%s(rec.%s)", fld.assertStr, fld.nameStr))
+		default:
+			prmList = append(prmList, "rec."+fld.nameStr)
+		}
+	}
+	prmStr := strings.Join(prmList, ", ")
+	fmt.Fprintf(buf, `
+// %[1]s inserts the records of list into the table %[2]s in a single
+// transaction and assigns their %[3]s fields.
+func %[1]s(db *qlm.DbType, list []%[4]s) {
+	db.Transact(func(tx *qlm.DbType) error {
+		for j := 0; j < len(list) && tx.OK(); j++ {
+			rec := &list[j]
+			tx.Exec("INSERT INTO %[2]s (%[5]s) VALUES (%[6]s);", %[7]s)
+			rec.%[3]s = tx.LastInsertID()
+		}
+		return nil
+	})
+}
+`, name("Insert"), rec.tblStr, rec.idStr, rec.nameStr, strings.Join(colList, ", "),
+		strings.Join(qmList, ", "), prmStr)
+	fmt.Fprintf(buf, `
+// %[1]s appends the records of the table %[2]s that satisfy tailStr to
+// the slice pointed to by listPtr. tailStr and prms are handled as they are by
+// Retrieve.
+func %[1]s(db *qlm.DbType, listPtr *[]%[3]s, tailStr string, prms ...interface{}) {
+	cmdStr := "SELECT id(), %[4]s FROM %[2]s"
+	if len(tailStr) > 0 {
+		cmdStr += " " + tailStr
+	}
+	rs, _ := db.Exec(cmdStr+";", prms...)
+	if db.Err() || len(rs) == 0 {
+		return
+	}
+	list := *listPtr
+	db.SetError(rs[len(rs)-1].Do(false, func(data []interface{}) (bool, error) {
+		var rec %[3]s
+		if data[0] != nil {
+			rec.%[5]s = data[0].(int64)
+		}
+`, name("Retrieve"), rec.tblStr, rec.nameStr, strings.Join(colList, ", "), rec.idStr)
+	for j, fld := range rec.fldList {
+		valStr := fmt.Sprintf("data[%d].(%s)", j+1, fld.assertStr)
+		if fld.typeStr == "int" || fld.typeStr == "uint" {
+			valStr = fmt.Sprintf("%s(%s)", fld.typeStr, valStr)
+		}
+		fmt.Fprintf(buf, "if data[%d] != nil {\nrec.%s = %s\n}\n", j+1, fld.nameStr, valStr)
+	}
+	buf.WriteString(`		list = append(list, rec)
+		return true, nil
+	}))
+	if db.OK() {
+		*listPtr = list
+	}
+}
+`)
+	fmt.Fprintf(buf, `
+// %[1]s stores every field of the record pointed to by rec in the row of
+// the table %[2]s identified by its %[3]s field.
+func %[1]s(db *qlm.DbType, rec *%[4]s) {
+	db.Transact(func(tx *qlm.DbType) error {
+		tx.Exec("UPDATE %[2]s %[5]s WHERE id() == ?%[6]d;", %[7]s, rec.%[3]s)
+		return nil
+	})
+}
+`, name("Update"), rec.tblStr, rec.idStr, rec.nameStr, strings.Join(setList, ", "),
+		len(rec.fldList)+1, prmStr)
+}
+
+// generate returns the formatted source of the functions for the record
+// types defined in the files of fileList. If typeList is empty, functions are
+// generated for every record type; otherwise, only for the types it names.
+func generate(fileList []string, typeList []string) (src []byte, err error) {
+	fset := token.NewFileSet()
+	var pkgStr string
+	var recList []recType
+	wantMap := make(map[string]bool)
+	for _, nameStr := range typeList {
+		wantMap[nameStr] = true
+	}
+	foundMap := make(map[string]bool)
+	for _, fileStr := range fileList {
+		var f *ast.File
+		if f, err = parser.ParseFile(fset, fileStr, nil, 0); err != nil {
+			return
+		}
+		pkgStr = f.Name.Name
+		ast.Inspect(f, func(node ast.Node) bool {
+			if ts, ok := node.(*ast.TypeSpec); ok && err == nil {
+				if st, ok := ts.Type.(*ast.StructType); ok && (len(wantMap) == 0 || wantMap[ts.Name.Name]) {
+					var rec recType
+					if rec, ok, err = recParse(ts.Name.Name, st); ok && err == nil {
+						recList = append(recList, rec)
+						foundMap[ts.Name.Name] = true
+					}
+				}
+			}
+			return err == nil
+		})
+		if err != nil {
+			return
+		}
+	}
+	for _, nameStr := range typeList {
+		if !foundMap[nameStr] {
+			return nil, fmt.Errorf("record type %s not found", nameStr)
+		}
+	}
+	if len(recList) == 0 {
+		return nil, fmt.Errorf("no record types found")
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by qlmgen; DO NOT EDIT.\n\npackage %s\n\nimport (\n", pkgStr)
+	for _, rec := range recList {
+		if recUses(rec, "time.") {
+			buf.WriteString("\t\"time\"\n")
+			break
+		}
+	}
+	buf.WriteString("\t\"github.com/jung-kurt/qlm\"\n)\n")
+	for _, rec := range recList {
+		recWrite(&buf, rec)
+	}
+	return format.Source(buf.Bytes())
+}
+
+// recUses returns true if the type of any field of rec begins with prefixStr.
+func recUses(rec recType, prefixStr string) bool {
+	for _, fld := range rec.fldList {
+		if strings.HasPrefix(fld.assertStr, prefixStr) {
+			return true
+		}
+	}
+	return false
+}
+
+// structsGenerate returns the source of a file in the package pkgStr that
+// defines record types for the tables of the database dbStr.
+func structsGenerate(dbStr, pkgStr string) (src []byte, err error) {
+	var buf bytes.Buffer
+	if err = qlm.GenerateStructs(dbStr, &buf); err == nil {
+		var hdr bytes.Buffer
+		fmt.Fprintf(&hdr, "// Record types generated by qlmgen from %s.\n\npackage %s\n", filepath.Base(dbStr), pkgStr)
+		var impList []string
+		for _, imp := range []struct{ pathStr, useStr string }{{"math/big", "big."}, {"time", "time."}} {
+			if bytes.Contains(buf.Bytes(), []byte(" "+imp.useStr)) {
+				impList = append(impList, strconv.Quote(imp.pathStr))
+			}
+		}
+		if len(impList) > 0 {
+			fmt.Fprintf(&hdr, "\nimport (\n%s\n)\n", strings.Join(impList, "\n"))
+		}
+		hdr.WriteString("\n")
+		hdr.Write(buf.Bytes())
+		src, err = format.Source(hdr.Bytes())
+	}
+	return
+}
+
+func main() {
+	typeStr := flag.String("type", "", "comma-separated list of record type names")
+	outStr := flag.String("o", "", "output file; default is the first source file with the suffix _qlm.go")
+	dbStr := flag.String("db", "", "ql database for which to write record types")
+	pkgStr := flag.String("pkg", os.Getenv("GOPACKAGE"), "package of the record types written for -db")
+	flag.Parse()
+	if len(*dbStr) > 0 {
+		if len(*pkgStr) == 0 {
+			*pkgStr = "main"
+		}
+		src, err := structsGenerate(*dbStr, *pkgStr)
+		if err == nil {
+			if len(*outStr) > 0 {
+				err = os.WriteFile(*outStr, src, 0644)
+			} else {
+				_, err = os.Stdout.Write(src)
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "qlmgen: %s\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	fileList := flag.Args()
+	if len(fileList) == 0 {
+		if fileStr := os.Getenv("GOFILE"); len(fileStr) > 0 {
+			fileList = []string{fileStr}
+		}
+	}
+	if len(fileList) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: qlmgen [-type T1,T2] [-o file] [file.go ...]\n       qlmgen -db file.ql [-pkg name] [-o file]")
+		os.Exit(2)
+	}
+	var typeList []string
+	if len(*typeStr) > 0 {
+		typeList = strings.Split(*typeStr, ",")
+	}
+	src, err := generate(fileList, typeList)
+	if err == nil {
+		if len(*outStr) == 0 {
+			*outStr = strings.TrimSuffix(fileList[0], ".go") + "_qlm.go"
+		}
+		err = os.WriteFile(*outStr, src, 0644)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "qlmgen: %s\n", err)
+		os.Exit(1)
+	}
+}
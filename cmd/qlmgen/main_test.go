@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package main
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// This example demonstrates the generation of functions for a record type.
+func Example_generate() {
+	srcStr := "package app\n\n" +
+		"import \"time\"\n\n" +
+		"type recType struct {\n" +
+		"\tID   int64     `ql_table:\"rec\"`\n" +
+		"\tName string    `ql:\"*,unique\"`\n" +
+		"\tQty  int       `ql:\"qty\"`\n" +
+		"\tTm   time.Time `ql:\"*\"`\n" +
+		"}\n\n" +
+		"type tagType struct {\n" +
+		"\tID  int64  `ql_table:\"tag\"`\n" +
+		"\tStr string `ql:\"*,collate=fold\"`\n" +
+		"}\n"
+	fileStr := filepath.Join(os.TempDir(), "qlmgen_example.go")
+	err := os.WriteFile(fileStr, []byte(srcStr), 0644)
+	if err == nil {
+		defer os.Remove(fileStr)
+		var src []byte
+		src, err = generate([]string{fileStr}, []string{"recType"})
+		if err == nil {
+			re := regexp.MustCompile(`(?m)^func .*$|"(INSERT|SELECT|UPDATE) [^"]*"`)
+			for _, str := range re.FindAllString(string(src), -1) {
+				fmt.Println(str)
+			}
+		}
+		_, err = generate([]string{fileStr}, nil)
+	}
+	fmt.Println(err)
+	// Output:
+	// func insertRecType(db *qlm.DbType, list []recType) {
+	// "INSERT INTO rec (Name, qty, Tm) VALUES (?1, ?2, ?3);"
+	// func retrieveRecType(db *qlm.DbType, listPtr *[]recType, tailStr string, prms ...interface{}) {
+	// "SELECT id(), Name, qty, Tm FROM rec"
+	// func updateRecType(db *qlm.DbType, rec *recType) {
+	// "UPDATE rec Name = ?1, qty = ?2, Tm = ?3 WHERE id() == ?4;"
+	// tagType: option collate=fold of field Str requires reflection
+}
+
+// This example demonstrates the generation of record types for the tables of
+// a database.
+func Example_structsGenerate() {
+	dbStr := filepath.Join(os.TempDir(), "qlmgen_example.ql")
+	db := qlm.DbCreate(dbStr)
+	db.TransactBegin()
+	db.Exec("CREATE TABLE event (Name string, At time, Dur duration);")
+	db.TransactCommit()
+	db.Close()
+	src, err := structsGenerate(dbStr, "app")
+	if err == nil {
+		fmt.Print(string(src))
+	} else {
+		fmt.Println(err)
+	}
+	os.Remove(dbStr)
+	// Output:
+	// // Record types generated by qlmgen from qlmgen_example.ql.
+	//
+	// package app
+	//
+	// import (
+	// 	"time"
+	// )
+	//
+	// // EventType holds a record of the event table.
+	// type EventType struct {
+	// 	ID   int64         `ql_table:"event"`
+	// 	Name string        `ql:"*"`
+	// 	At   time.Time     `ql:"*"`
+	// 	Dur  time.Duration `ql:"*"`
+	// }
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// colDataEncode returns a JSON object of the values of the columns in
+// nameList of the record recVl, keyed by column name. It is the form of the
+// Data field of journal and audit entries. Values that encoding/json cannot
+// represent are encoded losslessly: a floating-point value that is NaN or
+// infinite is written as the string "NaN", "+Inf" or "-Inf", and a complex
+// value as an array of its real and imaginary parts. The encoding does not
+// fail, so recording a change never prevents a valid write; a value that
+// cannot be encoded in any of these ways is written as its default format.
+func colDataEncode(dsc qlDscType, recVl reflect.Value, nameList []string) string {
+	valMap := make(map[string]json.RawMessage)
+	for _, nameStr := range nameList {
+		if sf, ok := dsc.nameMap[nameStr]; ok {
+			valMap[nameStr] = colValEncode(valueList(recVl, []reflect.StructField{sf})[0])
+		}
+	}
+	buf, _ := json.Marshal(valMap)
+	return string(buf)
+}
+
+// colValEncode returns the JSON encoding of the field value vl.
+func colValEncode(vl reflect.Value) json.RawMessage {
+	switch vl.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return colFloatEncode(vl.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := vl.Complex()
+		return json.RawMessage("[" + string(colFloatEncode(real(c))) + "," + string(colFloatEncode(imag(c))) + "]")
+	}
+	buf, err := json.Marshal(vl.Interface())
+	if err != nil {
+		buf, _ = json.Marshal(fmt.Sprintf("%v", vl.Interface()))
+	}
+	return buf
+}
+
+// colFloatEncode returns the JSON encoding of val, which may be NaN or
+// infinite.
+func colFloatEncode(val float64) json.RawMessage {
+	switch {
+	case math.IsNaN(val):
+		return json.RawMessage(`"NaN"`)
+	case math.IsInf(val, 1):
+		return json.RawMessage(`"+Inf"`)
+	case math.IsInf(val, -1):
+		return json.RawMessage(`"-Inf"`)
+	}
+	buf, _ := json.Marshal(val)
+	return buf
+}
+
+// colDataDecode assigns the column values of dataStr, an object produced by
+// colDataEncode(), to the corresponding fields of the record recVl. Columns
+// that the record type does not have are ignored.
+func colDataDecode(dsc qlDscType, recVl reflect.Value, dataStr string) (err error) {
+	var valMap map[string]json.RawMessage
+	err = json.Unmarshal([]byte(dataStr), &valMap)
+	for nameStr, raw := range valMap {
+		if sf, ok := dsc.nameMap[nameStr]; ok && err == nil {
+			err = colValDecode(fieldValue(recVl, sf), raw)
+			if err != nil {
+				err = fmt.Errorf("column %s: %s", nameStr, err)
+			}
+		}
+	}
+	return
+}
+
+// colValDecode assigns raw, encoded by colValEncode(), to the field fldVl.
+func colValDecode(fldVl reflect.Value, raw json.RawMessage) (err error) {
+	var val float64
+	switch fldVl.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if val, err = colFloatDecode(raw); err == nil {
+			fldVl.SetFloat(val)
+		}
+	case reflect.Complex64, reflect.Complex128:
+		var partList []json.RawMessage
+		var re, im float64
+		err = json.Unmarshal(raw, &partList)
+		if err == nil && len(partList) != 2 {
+			err = fmt.Errorf("expecting real and imaginary parts, got %s", raw)
+		}
+		if err == nil {
+			re, err = colFloatDecode(partList[0])
+		}
+		if err == nil {
+			im, err = colFloatDecode(partList[1])
+		}
+		if err == nil {
+			fldVl.SetComplex(complex(re, im))
+		}
+	default:
+		err = json.Unmarshal(raw, fldVl.Addr().Interface())
+	}
+	return
+}
+
+// colFloatDecode returns the value of raw, encoded by colFloatEncode().
+func colFloatDecode(raw json.RawMessage) (val float64, err error) {
+	var str string
+	if len(raw) > 0 && raw[0] == '"' {
+		if err = json.Unmarshal(raw, &str); err == nil {
+			val, err = strconv.ParseFloat(str, 64)
+		}
+	} else {
+		err = json.Unmarshal(raw, &val)
+	}
+	return
+}
@@ -0,0 +1,281 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// collateType describes a column whose values are ordered by a normalized
+// key that qlm maintains in a shadow column.
+type collateType struct {
+	nameStr string              // Collated column, for example "Name"
+	keyStr  string              // Shadow column, for example "Name_collate"
+	sf      reflect.StructField // Field of collated column
+	fold    bool                // Ignore case
+	natural bool                // Order embedded numbers by value
+}
+
+// collateSet records in dsc the collation of the column sqlStr. modeStr is
+// the value of the "collate" option: "fold", "natural" or "fold+natural".
+// Locale-aware collation is not supported, since it would make qlm depend on
+// golang.org/x/text; a mode of the form "locale:de" is rejected with an error
+// that says so.
+func (db *DbType) collateSet(dsc *qlDscType, sf reflect.StructField, sqlStr, modeStr string) {
+	if sf.Type.Kind() != reflect.String {
+		db.SetErrorf("collate option requires field %s to be of type string", sf.Name)
+		return
+	}
+	col := collateType{nameStr: sqlStr, keyStr: sqlStr + "_collate", sf: sf}
+	for _, str := range strings.Split(modeStr, "+") {
+		switch str {
+		case "fold":
+			col.fold = true
+		case "natural":
+			col.natural = true
+		default:
+			if strings.HasPrefix(str, "locale:") {
+				db.SetErrorf("locale collation %s for field %s is not supported", str, sf.Name)
+				return
+			}
+			db.SetErrorf("unknown collation %s for field %s", str, sf.Name)
+		}
+	}
+	dsc.collate = append(dsc.collate, col)
+}
+
+// collateKey returns the normalized form of str by which values of the column
+// are ordered. When the collation is natural, each run of digits is replaced
+// with its length (as two digits) followed by the digits without leading
+// zeros, so that, for example, "item9" sorts before "item10".
+func (col collateType) collateKey(str string) string {
+	if col.fold {
+		str = strings.ToLower(str)
+	}
+	if col.natural {
+		var buf []rune
+		runeList := []rune(str)
+		for j := 0; j < len(runeList); j++ {
+			if unicode.IsDigit(runeList[j]) {
+				k := j
+				for k < len(runeList) && unicode.IsDigit(runeList[k]) {
+					k++
+				}
+				numStr := strings.TrimLeft(string(runeList[j:k]), "0")
+				buf = append(buf, []rune(fmt.Sprintf("%02d%s", len(numStr), numStr))...)
+				j = k - 1
+			} else {
+				buf = append(buf, runeList[j])
+			}
+		}
+		str = string(buf)
+	}
+	return str
+}
+
+// collateVals returns the shadow column values for the record recVl.
+func (dsc qlDscType) collateVals(recVl reflect.Value) (list []interface{}) {
+	for _, col := range dsc.collate {
+		list = append(list, col.collateKey(valList(recVl, []reflect.StructField{col.sf})[0].(string)))
+	}
+	return
+}
+
+// collateUpdate extends the assignment list eqList and its arguments args
+// with the shadow columns of the collated columns in fldNames.
+func (dsc qlDscType) collateUpdate(recVl reflect.Value, fldNames []string,
+	eqList []string, args []interface{}) ([]string, []interface{}) {
+	vals := dsc.collateVals(recVl)
+	for j, col := range dsc.collate {
+		for _, nm := range fldNames {
+			if nm == col.nameStr {
+				args = append(args, vals[j])
+				strListAppend(&eqList, "%s = ?%d", col.keyStr, len(args))
+			}
+		}
+	}
+	return eqList, args
+}
+
+// collateSel returns the shadow columns, each preceded by a comma, to be
+// selected in addition to the record's columns. ql requires that the columns
+// by which a result is ordered be selected.
+func (dsc qlDscType) collateSel() (str string) {
+	for _, col := range dsc.collate {
+		str += ", " + col.keyStr
+	}
+	return
+}
+
+// collateTail returns tailStr and prms with the collated columns in its
+// WHERE and ORDER BY clauses replaced by their shadow columns. In the WHERE
+// clause, a collated column may only be compared, with one of the operators
+// ==, !=, <, <=, > and >=, to a parameter or a string literal that follows
+// it; the operand is replaced by a new parameter that holds its collation
+// key. Any other use of a collated column in the WHERE clause, for example in
+// a LIKE expression or a function call, sets an error rather than silently
+// comparing the raw values.
+func (db *DbType) collateTail(dsc qlDscType, tailStr string, prms []interface{}) (string, []interface{}) {
+	if len(dsc.collate) == 0 || db.err != nil {
+		return tailStr, prms
+	}
+	colMap := make(map[string]collateType)
+	for _, col := range dsc.collate {
+		colMap[col.nameStr] = col
+	}
+	if tailStr, prms, db.err = argsExpand(tailStr, prms); db.err != nil {
+		return tailStr, prms
+	}
+	wordList, posList := tailWordList(tailStr)
+	whereStart, whereEnd := -1, len(tailStr)
+	for j, word := range wordList {
+		switch strings.ToUpper(word) {
+		case "WHERE":
+			if whereStart < 0 {
+				whereStart = posList[j] + len(word)
+			}
+		case "GROUP", "ORDER", "LIMIT", "OFFSET":
+			if whereStart >= 0 && posList[j] < whereEnd {
+				whereEnd = posList[j]
+			}
+		}
+	}
+	if whereStart >= 0 {
+		var whereStr string
+		whereStr, prms = db.collateWhere(colMap, tailStr[whereStart:whereEnd], prms)
+		tailStr = tailStr[:whereStart] + whereStr + tailStr[whereEnd:]
+	}
+	wordList, posList = tailWordList(tailStr)
+	order := false
+	var partList []string
+	last := 0
+	for j, word := range wordList {
+		switch upStr := strings.ToUpper(word); {
+		case upStr == "ORDER":
+			order = true
+		case upStr == "LIMIT", upStr == "OFFSET", upStr == "WHERE", upStr == "GROUP":
+			order = false
+		case order && len(colMap[word].keyStr) > 0:
+			partList = append(partList, tailStr[last:posList[j]], colMap[word].keyStr)
+			last = posList[j] + len(word)
+		}
+	}
+	return strings.Join(append(partList, tailStr[last:]), ""), prms
+}
+
+// collateWhere returns whereStr, the condition of a WHERE clause, with the
+// comparisons of the collated columns in colMap rewritten as described for
+// collateTail(), and prms extended with the collation keys of the operands.
+func (db *DbType) collateWhere(colMap map[string]collateType, whereStr string,
+	prms []interface{}) (string, []interface{}) {
+	isIdent := func(ch byte, first bool) bool {
+		return ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' ||
+			!first && ch >= '0' && ch <= '9'
+	}
+	skipSpace := func(k int) int {
+		for k < len(whereStr) && unicode.IsSpace(rune(whereStr[k])) {
+			k++
+		}
+		return k
+	}
+	var buf []byte
+	for j := 0; j < len(whereStr) && db.err == nil; j++ {
+		ch := whereStr[j]
+		switch {
+		case ch == '"' || ch == '`':
+			k := strLitEnd(whereStr, j)
+			buf = append(buf, whereStr[j:k]...)
+			j = k - 1
+		case isIdent(ch, true) && (j == 0 || !isIdent(whereStr[j-1], false) && whereStr[j-1] != '.'):
+			k := j
+			for k < len(whereStr) && isIdent(whereStr[k], false) {
+				k++
+			}
+			col, ok := colMap[whereStr[j:k]]
+			if !ok {
+				buf = append(buf, whereStr[j:k]...)
+				j = k - 1
+				continue
+			}
+			// Collated column: expect an operator followed by an operand
+			opPos := skipSpace(k)
+			opStr := ""
+			for _, str := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+				if strings.HasPrefix(whereStr[opPos:], str) {
+					opStr = str
+					break
+				}
+			}
+			valPos := skipSpace(opPos + len(opStr))
+			var valStr string
+			end := valPos
+			if len(opStr) > 0 && valPos < len(whereStr) {
+				switch whereStr[valPos] {
+				case '?':
+					end = valPos + 1
+					for end < len(whereStr) && whereStr[end] >= '0' && whereStr[end] <= '9' {
+						end++
+					}
+					num, err := strconv.Atoi(whereStr[valPos+1 : end])
+					if err == nil && num >= 1 && num <= len(prms) {
+						valStr, ok = prms[num-1].(string)
+					} else {
+						ok = false
+					}
+				case '"', '`':
+					end = strLitEnd(whereStr, valPos)
+					valStr, db.err = strconv.Unquote(whereStr[valPos:end])
+				default:
+					ok = false
+				}
+			} else {
+				ok = false
+			}
+			if !ok && db.err == nil {
+				db.SetErrorf("collated column %s may only be compared with a string "+
+					"parameter or literal in a WHERE clause", col.nameStr)
+			}
+			if db.err == nil {
+				prms = append(prms[:len(prms):len(prms)], col.collateKey(valStr))
+				buf = append(buf, fmt.Sprintf("%s %s ?%d", col.keyStr, opStr, len(prms))...)
+				j = end - 1
+			}
+		default:
+			buf = append(buf, ch)
+		}
+	}
+	return string(buf), prms
+}
+
+// strLitEnd returns the position that follows the string literal that begins
+// at position j of str.
+func strLitEnd(str string, j int) int {
+	quote := str[j]
+	for k := j + 1; k < len(str); k++ {
+		switch {
+		case str[k] == '\\' && quote == '"':
+			k++
+		case str[k] == quote:
+			return k + 1
+		}
+	}
+	return len(str)
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates case-insensitive and natural ordering of string
+// columns. Comparisons in the WHERE clause respect the collation as well.
+// Locale-aware collation is not supported.
+func ExampleDbType_Retrieve_collate() {
+	type fileType struct {
+		ID   int64  `ql_table:"file"`
+		Name string `ql:"*,collate=fold+natural" ql_index:"*"`
+		Raw  string `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&fileType{})
+	var list []fileType
+	for _, str := range []string{"file10.txt", "File2.txt", "file1.txt", "apple.txt", "Zebra.txt"} {
+		list = append(list, fileType{Name: str, Raw: str})
+	}
+	db.Insert(list)
+	list[0].Name = "FILE3.txt"
+	db.Update(&list[0], "Name")
+	var byName, byRaw []fileType
+	db.Retrieve(&byName, "ORDER BY Name")
+	db.Retrieve(&byRaw, "ORDER BY Raw")
+	for j := range byName {
+		fmt.Printf("%-10s %s\n", byName[j].Name, byRaw[j].Raw)
+	}
+	byName = nil
+	db.Retrieve(&byName, "WHERE Name == ?1 || Name < \"B\" ORDER BY Name", "FILE2.TXT")
+	for _, rec := range byName {
+		fmt.Println(rec.Name)
+	}
+	db.RetrievePrefixFold(&byName, "Name", "file", "")
+	fmt.Println(db.Error())
+	db.ClearError()
+	type wordType struct {
+		ID   int64  `ql_table:"word"`
+		Word string `ql:"*,collate=locale:de"`
+	}
+	db.TableCreate(&wordType{})
+	fmt.Println(db.Error())
+	db.ClearError()
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// apple.txt  File2.txt
+	// file1.txt  Zebra.txt
+	// File2.txt  apple.txt
+	// FILE3.txt  file1.txt
+	// Zebra.txt  file10.txt
+	// apple.txt
+	// File2.txt
+	// collated column Name may only be compared with a string parameter or literal in a WHERE clause
+	// locale collation locale:de for field Word is not supported
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"github.com/cznic/ql"
+	"reflect"
+)
+
+// ColumnType describes a column of a table or of a query result.
+type ColumnType struct {
+	Name string // Column name, for example "name" or "count(*)"
+	Type string // ql type, for example "string"; empty if it could not be determined
+}
+
+// RecordsetColumns returns the names and ql types of the columns of rs. Since
+// ql record sets carry only column names, the type of each column is
+// determined from the first row in which its value is not NULL. The type of a
+// column whose values are all NULL, or of any column of an empty record set,
+// is reported as an empty string.
+func RecordsetColumns(rs ql.Recordset) (list []ColumnType, err error) {
+	var nameList []string
+	nameList, err = rs.Fields()
+	if err == nil {
+		list = make([]ColumnType, len(nameList))
+		pending := len(nameList)
+		for j, nameStr := range nameList {
+			list[j].Name = nameStr
+		}
+		if pending > 0 {
+			err = rs.Do(false, func(data []interface{}) (bool, error) {
+				for j, val := range data {
+					if val != nil && j < len(list) && list[j].Type == "" {
+						tp := reflect.TypeOf(val)
+						if tp.Kind() == reflect.Ptr {
+							tp = tp.Elem()
+						}
+						list[j].Type = qlTypeStr(tp)
+						pending--
+					}
+				}
+				return pending > 0, nil
+			})
+		}
+	}
+	return
+}
+
+// QueryColumns executes the ql statement cmdStr, typically a SELECT statement
+// that is not associated with a record structure, and returns the names and
+// types of the columns of its result. See RecordsetColumns for the manner in
+// which types are determined. Generic tools, such as exporters and
+// interactive shells, can use this function to label the output of ad hoc
+// queries.
+func (db *DbType) QueryColumns(cmdStr string, prms ...interface{}) (list []ColumnType) {
+	if db.err != nil {
+		return
+	}
+	rs, _ := db.Exec(cmdStr, prms...)
+	if db.err == nil {
+		if len(rs) > 0 {
+			list, db.err = RecordsetColumns(rs[len(rs)-1])
+		} else {
+			db.SetErrorf("statement produces no result: %s", cmdStr)
+		}
+	}
+	return
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+	"time"
+)
+
+// This example demonstrates how the columns of an ad hoc query can be
+// labeled without a record structure.
+func ExampleDbType_QueryColumns() {
+	type logType struct {
+		ID    int64     `ql_table:"log"`
+		Tm    time.Time `ql:"*"`
+		Level int32     `ql:"*"`
+		Msg   string    `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&logType{})
+	db.Insert([]logType{{0, time.Now(), 1, "start"}, {0, time.Now(), 2, "stop"}})
+	list := db.QueryColumns("SELECT Level, count(*) AS n, max(Tm) AS last, " +
+		"float64(Level) * 1.5 AS weight FROM log GROUP BY Level")
+	for _, col := range list {
+		fmt.Printf("%-6s %s\n", col.Name, col.Type)
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// Level  int32
+	// n      int64
+	// last   time
+	// weight float64
+}
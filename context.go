@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"context"
+	"github.com/cznic/ql"
+)
+
+// withCtx calls fn with ctx established as the context of the operation.
+// Statements are not executed, and the loading of retrieved records stops,
+// once ctx is done; the database error is then set to the context's error.
+func (db *DbType) withCtx(ctx context.Context, fn func()) {
+	if db.err != nil {
+		return
+	}
+	prev := db.ctx
+	db.ctx = ctx
+	defer func() {
+		db.ctx = prev
+	}()
+	fn()
+}
+
+// ExecCtx is like Exec but honors the cancellation and deadline of ctx.
+func (db *DbType) ExecCtx(ctx context.Context, cmdStr string, prms ...interface{}) (rs []ql.Recordset, index int) {
+	db.withCtx(ctx, func() {
+		rs, index = db.Exec(cmdStr, prms...)
+	})
+	return
+}
+
+// RetrieveCtx is like Retrieve but honors the cancellation and deadline of
+// ctx. The context is checked before each record is loaded, so a long scan
+// stops promptly when ctx is done. In that case, the database error is set to
+// the context's error and the slice is not modified.
+func (db *DbType) RetrieveCtx(ctx context.Context, slicePtr interface{}, tailStr string, prms ...interface{}) {
+	db.withCtx(ctx, func() {
+		db.Retrieve(slicePtr, tailStr, prms...)
+	})
+}
+
+// InsertCtx is like Insert but honors the cancellation and deadline of ctx.
+// The context is checked before each statement that inserts a chunk of
+// records; see InsertChunk(). If ctx is done before all records are inserted,
+// the transaction is rolled back.
+func (db *DbType) InsertCtx(ctx context.Context, slice interface{}) {
+	db.withCtx(ctx, func() {
+		db.Insert(slice)
+	})
+}
+
+// UpdateCtx is like Update but honors the cancellation and deadline of ctx.
+func (db *DbType) UpdateCtx(ctx context.Context, recPtr interface{}, fldNames ...string) {
+	db.withCtx(ctx, func() {
+		db.Update(recPtr, fldNames...)
+	})
+}
+
+// DeleteCtx is like Delete but honors the cancellation and deadline of ctx.
+func (db *DbType) DeleteCtx(ctx context.Context, recPtr interface{}, tailStr string, prms ...interface{}) {
+	db.withCtx(ctx, func() {
+		db.Delete(recPtr, tailStr, prms...)
+	})
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the interruption of a retrieval when its context
+// is canceled.
+func ExampleDbType_RetrieveCtx() {
+	type numType struct {
+		ID  int64 `ql_table:"num"`
+		Val int64 `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&numType{})
+	list := make([]numType, 100)
+	for j := range list {
+		list[j].Val = int64(j)
+	}
+	db.Insert(list)
+	var all []numType
+	db.RetrieveCtx(context.Background(), &all, "")
+	fmt.Printf("%d records\n", len(all))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var partial []numType
+	db.RetrieveCtx(ctx, &partial, "")
+	fmt.Printf("%d records, %v\n", len(partial), db.Error())
+	db.ClearError()
+	db.InsertCtx(ctx, list[:1])
+	fmt.Println(db.Error())
+	db.ClearError()
+	// A deadline that expires during a transaction causes it to be rolled back
+	db.TransactBegin()
+	ctx, cancel = context.WithCancel(context.Background())
+	db.DeleteCtx(ctx, &numType{}, "WHERE Val < 50")
+	cancel()
+	db.DeleteCtx(ctx, &numType{}, "WHERE Val >= 50")
+	fmt.Println(db.Error())
+	db.ClearError()
+	db.TransactRollback()
+	all = nil
+	db.Retrieve(&all, "")
+	fmt.Printf("%d records\n", len(all))
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// 100 records
+	// 0 records, context canceled
+	// context canceled
+	// context canceled
+	// 100 records
+}
@@ -0,0 +1,230 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVOptionsType specifies how ImportCSV() interprets its input. The zero
+// value is suitable for comma-separated input with RFC 3339 timestamps.
+type CSVOptionsType struct {
+	Comma       rune   // Field delimiter; a comma if zero
+	TimeLayout  string // Layout of time.Time values; time.RFC3339Nano if empty
+	BatchSize   int    // Number of records inserted with each call to Insert(); 500 if zero
+	SkipUnknown bool   // Ignore columns that correspond to no field rather than failing
+}
+
+// csvField returns the column name and field of the record type described by
+// dsc that correspond to the CSV header hdrStr. The header is compared to the
+// column names of the table, then to the field names, and finally to both
+// without regard to case.
+func csvField(dsc qlDscType, hdrStr string) (nameStr string, sf reflect.StructField, ok bool) {
+	hdrStr = strings.TrimSpace(hdrStr)
+	if sf, ok = dsc.nameMap[hdrStr]; ok {
+		return hdrStr, sf, ok
+	}
+	for _, fold := range []bool{false, true} {
+		for _, nameStr = range dsc.insert.nameList {
+			sf = dsc.nameMap[nameStr]
+			if fold && (strings.EqualFold(nameStr, hdrStr) || strings.EqualFold(sf.Name, hdrStr)) ||
+				!fold && sf.Name == hdrStr {
+				return nameStr, sf, true
+			}
+		}
+	}
+	return "", sf, false
+}
+
+// csvAssign converts str to the type of fldVl, the field of the column
+// nameStr, and assigns it. An empty string leaves the zero value in fldVl
+// unless the field is a string.
+func (dsc qlDscType) csvAssign(fldVl reflect.Value, nameStr, str, layoutStr string) (err error) {
+	if len(str) == 0 && fldVl.Kind() != reflect.String {
+		return
+	}
+	if ok, err := scannerLoad(fldVl, str); ok {
+		return err
+	}
+	if dsc.json[nameStr] {
+		var vl reflect.Value
+		if vl, err = jsonLoad(fldVl.Type(), []byte(str)); err == nil {
+			fldVl.Set(vl)
+		}
+		return
+	}
+	switch v := fldVl.Addr().Interface().(type) {
+	case *time.Time:
+		*v, err = time.Parse(layoutStr, str)
+		return
+	case *time.Duration:
+		*v, err = time.ParseDuration(str)
+		return
+	case *big.Int:
+		if _, ok := v.SetString(str, 10); !ok {
+			err = fmt.Errorf("invalid integer %q", str)
+		}
+		return
+	case *big.Rat:
+		if _, ok := v.SetString(str); !ok {
+			err = fmt.Errorf("invalid rational number %q", str)
+		}
+		return
+	case *[]byte:
+		*v, err = base64.StdEncoding.DecodeString(str)
+		return
+	}
+	switch fldVl.Kind() {
+	case reflect.String:
+		fldVl.SetString(str)
+	case reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(str); err == nil {
+			fldVl.SetBool(b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		if n, err = strconv.ParseInt(str, 10, fldVl.Type().Bits()); err == nil {
+			fldVl.SetInt(n)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var n uint64
+		if n, err = strconv.ParseUint(str, 10, fldVl.Type().Bits()); err == nil {
+			fldVl.SetUint(n)
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, err = strconv.ParseFloat(str, fldVl.Type().Bits()); err == nil {
+			fldVl.SetFloat(f)
+		}
+	case reflect.Complex64, reflect.Complex128:
+		var c complex128
+		if c, err = strconv.ParseComplex(str, fldVl.Type().Bits()); err == nil {
+			fldVl.SetComplex(c)
+		}
+	default:
+		err = fmt.Errorf("cannot convert text to %v", fldVl.Type())
+	}
+	return
+}
+
+// ImportCSV reads comma-separated values from r and inserts them as records
+// of the type pointed to by recPtr. The first line of the input is a header
+// that names the column of each value; see CSVOptionsType for the way names
+// are matched to fields. Columns that correspond to the identifier field are
+// ignored, since identifiers are assigned by Insert(). Each value is
+// converted to the type of its field: numbers and booleans are parsed as by
+// the strconv package, time.Time values according to opts.TimeLayout,
+// time.Duration values as by time.ParseDuration, big.Int and big.Rat values
+// from their usual decimal text, []byte values from standard base64, fields
+// with the json option from JSON text, and fields whose address implements
+// Scanner by passing the string to Scan(). An empty value leaves a
+// non-string field with its zero value. The records are inserted in batches
+// of opts.BatchSize within a single transaction, so either all records are
+// imported or, if an error occurs, none are. The number of records inserted
+// is returned. An error identifies the line and column of the value that
+// could not be converted.
+func (db *DbType) ImportCSV(recPtr interface{}, r io.Reader, opts CSVOptionsType) (count int64) {
+	dsc := db.dscFromPtr(recPtr)
+	if !db.writable(dsc) {
+		return
+	}
+	if opts.TimeLayout == "" {
+		opts.TimeLayout = time.RFC3339Nano
+	}
+	if opts.BatchSize < 1 {
+		opts.BatchSize = 500
+	}
+	rdr := csv.NewReader(r)
+	if opts.Comma != 0 {
+		rdr.Comma = opts.Comma
+	}
+	hdrList, err := rdr.Read()
+	if err != nil {
+		db.SetErrorf("reading CSV header: %s", err)
+		return
+	}
+	sfList := make([]reflect.StructField, len(hdrList))
+	nameList := make([]string, len(hdrList))
+	use := make([]bool, len(hdrList))
+	for j, hdrStr := range hdrList {
+		nameStr, sf, ok := csvField(dsc, hdrStr)
+		switch {
+		case ok:
+			nameList[j], sfList[j], use[j] = nameStr, sf, true
+		case strings.EqualFold(strings.TrimSpace(hdrStr), dsc.idSf.Name) ||
+			strings.EqualFold(strings.TrimSpace(hdrStr), "id()"):
+		case !opts.SkipUnknown:
+			db.SetErrorf("CSV column %s corresponds to no field of table %s", hdrStr, dsc.tblStr)
+			return
+		}
+	}
+	sliceVl := reflect.MakeSlice(reflect.SliceOf(dsc.recTp), 0, opts.BatchSize)
+	flush := func() {
+		if sliceVl.Len() > 0 {
+			db.Insert(sliceVl.Interface())
+			if db.err == nil {
+				count += int64(sliceVl.Len())
+			}
+			sliceVl = sliceVl.Slice(0, 0)
+		}
+	}
+	db.TransactBegin()
+	for db.err == nil {
+		strList, err := rdr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			db.SetErrorf("reading CSV: %s", err)
+			break
+		}
+		line, _ := rdr.FieldPos(0)
+		recVl := reflect.New(dsc.recTp).Elem()
+		for j, str := range strList {
+			if j < len(use) && use[j] {
+				fldVl := valueList(recVl, sfList[j:j+1])[0]
+				if err = dsc.csvAssign(fldVl, nameList[j], str, opts.TimeLayout); err != nil {
+					db.SetErrorf("CSV line %d, column %s: %s", line, hdrList[j], err)
+					break
+				}
+			}
+		}
+		if db.err == nil {
+			sliceVl = reflect.Append(sliceVl, recVl)
+			if sliceVl.Len() == opts.BatchSize {
+				flush()
+			}
+		}
+	}
+	if db.err == nil {
+		flush()
+	}
+	db.transactEnd(db.err == nil)
+	if db.err != nil {
+		count = 0
+	}
+	return
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// This example demonstrates the import of comma-separated values.
+func ExampleDbType_ImportCSV() {
+	type itemType struct {
+		ID     int64     `ql_table:"item"`
+		Name   string    `ql:"*"`
+		Qty    int32     `ql:"Quantity"`
+		Price  big.Rat   `ql:"*"`
+		Added  time.Time `ql:"*"`
+		Active bool      `ql:"*"`
+		Img    []byte    `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&itemType{})
+	csvStr := `id,name,Quantity,Price,Added,Active,Img,Note
+,bolt,120,3/4,2016-05-01T10:00:00Z,true,AQID,spare
+,nut,80,1.25,2016-05-02T10:00:00Z,false,,
+`
+	count := db.ImportCSV(&itemType{}, strings.NewReader(csvStr), qlm.CSVOptionsType{SkipUnknown: true})
+	fmt.Println(count)
+	var list []itemType
+	db.Retrieve(&list, "ORDER BY Name")
+	for _, it := range list {
+		fmt.Println(it.Name, it.Qty, it.Price.FloatString(2), it.Added.Format("Jan 2"), it.Active, it.Img)
+	}
+	// A conversion error rolls back the entire import
+	csvStr = "Name;Quantity\nwasher;500\nscrew;many\n"
+	count = db.ImportCSV(&itemType{}, strings.NewReader(csvStr), qlm.CSVOptionsType{Comma: ';'})
+	fmt.Println(count, db.Error())
+	db.ClearError()
+	list = nil
+	db.Retrieve(&list, "")
+	fmt.Println(len(list))
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// 2
+	// bolt 120 0.75 May 1 true [1 2 3]
+	// nut 80 1.25 May 2 false []
+	// 0 CSV line 3, column Quantity: strconv.ParseInt: parsing "many": invalid syntax
+	// 2
+}
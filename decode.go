@@ -0,0 +1,169 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"github.com/cznic/ql"
+	"reflect"
+	"strings"
+)
+
+// DecodeRecordset appends a record to the slice pointed to by slicePtr for
+// each row of rs, a record set obtained, for example, from Exec or directly
+// from ql. The elements of the slice are record structures of the kind used
+// with Retrieve. Each column of rs is assigned to the field whose "ql" tag
+// names it; the unnamed column that results from id() is assigned to the ID
+// field. A column qualified with a table name, for example "a.Name" in the
+// result of a join, is matched by the name that follows the period if it is
+// not matched in full. Columns that do not correspond to a field are ignored.
+// NULL values result in zero values.
+func DecodeRecordset(rs ql.Recordset, slicePtr interface{}) error {
+	db := new(DbType)
+	db.init()
+	db.decodeRecordset(rs, slicePtr)
+	return db.err
+}
+
+// decodeRecordset is the implementation of DecodeRecordset. It sets the
+// error of db rather than returning it.
+func (db *DbType) decodeRecordset(rs ql.Recordset, slicePtr interface{}) {
+	recTp := db.slicePtrCheck(slicePtr, "DecodeRecordset")
+	if db.err != nil {
+		return
+	}
+	dsc := db.dscFromType(recTp)
+	var nameList []string
+	if db.err == nil {
+		nameList, db.err = rs.Fields()
+	}
+	if db.err != nil {
+		return
+	}
+	var sfList []reflect.StructField
+	var colList []int
+	for j, nameStr := range nameList {
+		sf, ok := dsc.nameMap[nameStr]
+		if !ok && (nameStr == "" || nameStr == "id()") {
+			sf, ok = dsc.idSf, true
+		}
+		if pos := strings.LastIndex(nameStr, "."); !ok && pos >= 0 {
+			sf, ok = dsc.nameMap[nameStr[pos+1:]]
+		}
+		if ok {
+			sfList = append(sfList, sf)
+			colList = append(colList, j)
+		}
+	}
+	db.err = decodeRows([]ql.Recordset{rs}, reflect.ValueOf(slicePtr), sfList, colList, nil)
+}
+
+// rawFieldMap returns the fields of the structure type recTp keyed by the
+// name of the column to which each is assigned by RawRetrieve. The column
+// name is taken from the "ql" tag if one is present and from the field name
+// otherwise; a name of "*" is derived from the field name as established by
+// SetNamer(). The field with the "ql_table" tag, or else an int64 field named
+// ID, is keyed by the empty name that ql gives to id(). Unexported fields and
+// fields tagged `ql:"-"` are excluded.
+func (db *DbType) rawFieldMap(recTp reflect.Type) (fldMap map[string]reflect.StructField) {
+	fldMap = make(map[string]reflect.StructField)
+	for _, sf := range embedFields(recTp) {
+		if len(sf.PkgPath) > 0 {
+			continue
+		}
+		nameStr, _ := tagParse(qlTag(recTp, sf))
+		switch {
+		case nameStr == "-":
+			continue
+		case len(sf.Tag.Get("ql_table")) > 0:
+			nameStr = ""
+		case nameStr == "*":
+			nameStr = db.colName(recTp, sf)
+		case nameStr == "":
+			nameStr = sf.Name
+		}
+		fldMap[nameStr] = sf
+	}
+	if _, ok := fldMap[""]; !ok {
+		if sf, ok := fldMap["ID"]; ok && sf.Type.Kind() == reflect.Int64 {
+			fldMap[""] = sf
+		}
+	}
+	return
+}
+
+// RawRetrieve executes the ql SELECT statement cmdStr with the parameters
+// prms and appends a record to the slice pointed to by slicePtr for each
+// resulting row. Unlike Retrieve, the element type of the slice does not need
+// to be associated with a table, so it can be an anonymous structure declared
+// at the point of the call:
+//
+//	var list []struct {
+//		Cust  string
+//		Total float64 `ql:"total"`
+//	}
+//	db.RawRetrieve(&list, "SELECT Cust, sum(Amt) AS total FROM sale GROUP BY Cust")
+//
+// Each column of the result is assigned to the field whose "ql" tag names it
+// or, if the field has no "ql" tag, to the field of the same name. If no
+// field matches exactly, a column qualified with a table name is matched by
+// the name that follows the period, and then names are compared without
+// regard to case. The value of id() is assigned to the field with the
+// "ql_table" tag or to an int64 field named ID. Columns that do not
+// correspond to a field are ignored. Numeric values are converted to the type
+// of the field as needed and NULL values result in zero values.
+func (db *DbType) RawRetrieve(slicePtr interface{}, cmdStr string, prms ...interface{}) {
+	if db.err != nil {
+		return
+	}
+	recTp := db.slicePtrCheck(slicePtr, "RawRetrieve")
+	if db.err == nil && recTp.Kind() != reflect.Struct {
+		db.SetErrorf("function RawRetrieve expecting slice of structures, got slice of %v", recTp.Kind())
+	}
+	if db.err != nil {
+		return
+	}
+	fldMap := db.rawFieldMap(recTp)
+	rs, _ := db.Exec(cmdStr, prms...)
+	for _, res := range rs {
+		var nameList []string
+		if db.err == nil {
+			nameList, db.err = res.Fields()
+		}
+		if db.err != nil {
+			return
+		}
+		var sfList []reflect.StructField
+		var colList []int
+		for j, nameStr := range nameList {
+			sf, ok := fldMap[nameStr]
+			if pos := strings.LastIndex(nameStr, "."); !ok && pos >= 0 {
+				nameStr = nameStr[pos+1:]
+				sf, ok = fldMap[nameStr]
+			}
+			for keyStr, fldSf := range fldMap {
+				if !ok && len(nameStr) > 0 && strings.EqualFold(keyStr, nameStr) {
+					sf, ok = fldSf, true
+				}
+			}
+			if ok {
+				sfList = append(sfList, sf)
+				colList = append(colList, j)
+			}
+		}
+		db.err = decodeRows([]ql.Recordset{res}, reflect.ValueOf(slicePtr), sfList, colList, nil)
+	}
+}
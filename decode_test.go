@@ -0,0 +1,137 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the decoding of the result of a raw ql query
+// into qlm records.
+func ExampleDecodeRecordset() {
+	type itemType struct {
+		ID    int64   `ql_table:"item"`
+		Name  string  `ql:"*"`
+		Price float64 `ql:"*"`
+		Qty   int32   `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&itemType{})
+	db.Insert([]itemType{{0, "bolt", 0.25, 100}, {0, "nut", 0.1, 0}, {0, "gear", 4.5, 3}})
+	rs, _ := db.Exec("SELECT Qty, Name, id(), Price * 2.0 AS Double FROM item WHERE Qty > 0 ORDER BY Name;")
+	if db.OK() {
+		var list []itemType
+		err := qlm.DecodeRecordset(rs[0], &list)
+		if err == nil {
+			for _, rec := range list {
+				fmt.Printf("%d %-4s %3d %.2f\n", rec.ID, rec.Name, rec.Qty, rec.Price)
+			}
+		} else {
+			fmt.Println(err)
+		}
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// 1 bolt 100 0.00
+	// 3 gear   3 0.00
+}
+
+// This example demonstrates the retrieval of ad hoc query results into
+// slices of anonymous structures.
+func ExampleDbType_RawRetrieve() {
+	type saleType struct {
+		ID   int64   `ql_table:"sale"`
+		Cust string  `ql:"*"`
+		Amt  float64 `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&saleType{})
+	db.Insert([]saleType{{0, "ann", 40}, {0, "bob", 15}, {0, "ann", 85}})
+	var totals []struct {
+		Cust  string
+		Count int
+		Total float64 `ql:"sum"`
+	}
+	db.RawRetrieve(&totals, "SELECT Cust, count(*) AS count, sum(Amt) AS sum "+
+		"FROM sale GROUP BY Cust ORDER BY Cust")
+	for _, rec := range totals {
+		fmt.Printf("%-3s %d %6.2f\n", rec.Cust, rec.Count, rec.Total)
+	}
+	// Anonymous structures with qlm tags work with Retrieve as well
+	var big []struct {
+		ID  int64   `ql_table:"sale"`
+		Amt float64 `ql:"*"`
+	}
+	db.Retrieve(&big, "WHERE Amt > ?1 ORDER BY Amt", 20.0)
+	for _, rec := range big {
+		fmt.Printf("%d %6.2f\n", rec.ID, rec.Amt)
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// ann 2 125.00
+	// bob 1  15.00
+	// 1  40.00
+	// 3  85.00
+}
+
+// This example demonstrates the retrieval of the rows of a join into a slice
+// of anonymous structures. Qualified column names are matched by the name
+// that follows the period.
+func ExampleDbType_RawRetrieve_join() {
+	type custType struct {
+		ID   int64  `ql_table:"cust"`
+		Name string `ql:"*"`
+	}
+	type orderType struct {
+		ID     int64   `ql_table:"orders"`
+		CustID int64   `ql:"*"`
+		Amt    float64 `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&custType{})
+	db.TableCreate(&orderType{})
+	custList := []custType{{0, "ann"}, {0, "bob"}}
+	db.Insert(custList)
+	db.Insert([]orderType{{0, custList[0].ID, 40}, {0, custList[1].ID, 15},
+		{0, custList[0].ID, 85}})
+	var list []struct {
+		Name string
+		Amt  float64
+		Big  bool `ql:"big"`
+	}
+	db.RawRetrieve(&list, "SELECT cust.Name, orders.Amt, orders.Amt > :min AS big "+
+		"FROM cust, orders WHERE id(cust) == orders.CustID ORDER BY orders.Amt",
+		qlm.Args{"min": 20.0})
+	for _, rec := range list {
+		fmt.Printf("%-3s %6.2f %v\n", rec.Name, rec.Amt, rec.Big)
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// bob  15.00 false
+	// ann  40.00 true
+	// ann  85.00 true
+}
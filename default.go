@@ -0,0 +1,92 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// defaultType describes a field that is assigned a default value by Insert
+// when it is zero.
+type defaultType struct {
+	sf reflect.StructField // Field that receives the default
+	vl reflect.Value       // Default value of the field's type
+}
+
+// defaultSet registers the default value valStr, taken from the default option
+// of the tag of field sf, with the descriptor dsc. The value is converted to
+// the type of the field. The ql literal that expresses the default in the
+// DEFAULT clause of the column definition is returned.
+func (db *DbType) defaultSet(dsc *qlDscType, sf reflect.StructField, valStr string) (litStr string) {
+	vl := reflect.New(sf.Type).Elem()
+	var err error
+	switch kd := sf.Type.Kind(); {
+	case sf.Type == reflect.TypeOf(time.Duration(0)):
+		db.SetErrorf("default option not supported for field %s of type %v", sf.Name, sf.Type)
+		return
+	case kd == reflect.String:
+		vl.SetString(valStr)
+		litStr = strconv.Quote(valStr)
+	case kd == reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(valStr); err == nil {
+			vl.SetBool(b)
+			litStr = strconv.FormatBool(b)
+		}
+	case kd >= reflect.Int && kd <= reflect.Int64:
+		var n int64
+		if n, err = strconv.ParseInt(valStr, 10, sf.Type.Bits()); err == nil {
+			vl.SetInt(n)
+			litStr = strconv.FormatInt(n, 10)
+		}
+	case kd >= reflect.Uint && kd <= reflect.Uint64:
+		var n uint64
+		if n, err = strconv.ParseUint(valStr, 10, sf.Type.Bits()); err == nil {
+			vl.SetUint(n)
+			litStr = strconv.FormatUint(n, 10)
+		}
+	case kd == reflect.Float32 || kd == reflect.Float64:
+		var f float64
+		if f, err = strconv.ParseFloat(valStr, sf.Type.Bits()); err == nil {
+			vl.SetFloat(f)
+			litStr = strconv.FormatFloat(f, 'g', -1, sf.Type.Bits())
+		}
+	default:
+		db.SetErrorf("default option not supported for field %s of type %v", sf.Name, sf.Type)
+		return
+	}
+	if err != nil {
+		db.SetErrorf("invalid default value %s for field %s: %s", valStr, sf.Name, err)
+		litStr = ""
+		return
+	}
+	dsc.defaults = append(dsc.defaults, defaultType{sf: sf, vl: vl})
+	return
+}
+
+// defaultAssign assigns the registered default values to the zero-valued
+// fields of the record recVl.
+func (dsc qlDscType) defaultAssign(recVl reflect.Value) {
+	for _, def := range dsc.defaults {
+		fldVl := valueList(recVl, []reflect.StructField{def.sf})[0]
+		if fldVl.IsZero() {
+			fldVl.Set(def.vl)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the assignment of default values to zero-valued
+// fields.
+func ExampleDbType_TableCreate_default() {
+	type taskType struct {
+		ID       int64   `ql_table:"task"`
+		Name     string  `ql:"*"`
+		Status   string  `ql:"*,default=open"`
+		Priority int32   `ql:"*,default=3"`
+		Weight   float64 `ql:"*,default=1.5"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&taskType{})
+	list := []taskType{{Name: "paint"}, {Name: "sand", Status: "done", Priority: 1}}
+	db.Insert(list)
+	fmt.Println(list[0].Status, list[0].Priority, list[0].Weight)
+	db.TransactBegin()
+	db.Exec("INSERT INTO task (Name) VALUES (\"prime\");")
+	db.TransactCommit()
+	list = nil
+	db.Retrieve(&list, "ORDER BY Name")
+	for _, task := range list {
+		fmt.Println(task.Name, task.Status, task.Priority, task.Weight)
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// open 3 1.5
+	// paint open 3 1.5
+	// prime open 3 1.5
+	// sand done 1 1.5
+}
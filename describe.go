@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+// FieldDescType describes one managed field of a record structure and the
+// database column to which it is mapped.
+type FieldDescType struct {
+	Field   string            // Name of the structure field, for example "Name"
+	Column  string            // Name of the table column, for example "name"
+	Type    string            // ql type of the column, for example "string"
+	Index   bool              // True if the column is indexed
+	Options map[string]string // Options specified in the "ql" tag, for example {"ttl": "24h"}
+}
+
+// DescType is a read-only view of the information that qlm gathers from the
+// tags of a record structure.
+type DescType struct {
+	Table   string          // Name of the table
+	IDField string          // Name of the structure field that holds the record's id()
+	IDIndex bool            // True if id() is indexed
+	Fields  []FieldDescType // Managed fields in structure order
+}
+
+// Describe returns the table name, ID field and field/column mapping that qlm
+// uses for the record type pointed to by recPtr. Tools such as code
+// generators and administration interfaces can use this information instead
+// of parsing the tags themselves. The returned value is a copy; modifying it
+// has no effect on qlm's operation.
+func (db *DbType) Describe(recPtr interface{}) (desc DescType) {
+	if db.err != nil {
+		return
+	}
+	dsc := db.dscFromPtr(recPtr)
+	if db.err != nil {
+		return
+	}
+	idxMap := make(map[string]bool)
+	for _, idx := range dsc.create.idxList {
+		idxMap[idx.fldStr] = true
+	}
+	desc.Table = dsc.tblStr
+	desc.IDField = dsc.idSf.Name
+	desc.IDIndex = idxMap["id()"]
+	for j, nameStr := range dsc.insert.nameList {
+		fld := FieldDescType{
+			Field:  dsc.insert.sfList[j].Name,
+			Column: nameStr,
+			Type:   dsc.typeMap[nameStr],
+			Index:  idxMap[nameStr],
+		}
+		if optMap, ok := dsc.optMap[nameStr]; ok {
+			fld.Options = make(map[string]string)
+			for key, val := range optMap {
+				fld.Options[key] = val
+			}
+		}
+		desc.Fields = append(desc.Fields, fld)
+	}
+	return
+}
+
+// Column returns the description of the field mapped to the column named
+// colStr. False is returned if no such column exists.
+func (desc DescType) Column(colStr string) (fld FieldDescType, ok bool) {
+	for _, fld = range desc.Fields {
+		if fld.Column == colStr {
+			return fld, true
+		}
+	}
+	return FieldDescType{}, false
+}
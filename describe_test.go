@@ -0,0 +1,51 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+	"time"
+)
+
+// This example demonstrates how the mapping between a record structure and
+// its table can be inspected.
+func ExampleDbType_Describe() {
+	type logType struct {
+		ID  int64     `ql_table:"log" ql_index:"*"`
+		Tm  time.Time `ql:"tm,ttl=24h" ql_index:"*"`
+		Msg string    `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	desc := db.Describe(&logType{})
+	fmt.Printf("table %s, id field %s, indexed %v\n", desc.Table, desc.IDField, desc.IDIndex)
+	for _, fld := range desc.Fields {
+		fmt.Printf("%-3s %-3s %-6s %-5v %v\n", fld.Field, fld.Column, fld.Type, fld.Index, fld.Options)
+	}
+	if fld, ok := desc.Column("tm"); ok {
+		fmt.Printf("column tm is field %s\n", fld.Field)
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// table log, id field ID, indexed true
+	// Tm  tm  time   true  map[ttl:24h]
+	// Msg Msg string false map[]
+	// column tm is field Tm
+}
@@ -36,6 +36,15 @@ Later, to receive updates, run
 
 	go get -u github.com/jung-kurt/qlm
 
+By default, qlm accesses record fields by address using package unsafe. In
+environments that do not permit this, build with the qlm_safe tag, for example
+
+	go build -tags qlm_safe
+
+to use reflection alone. Fields tagged "ql" must be exported in either mode.
+The ID field tagged "ql_table" may be unexported by default, but in this mode
+it too must be exported.
+
 Quick Start
 
 The following Go code demonstrates the creation of a database, the creation of
@@ -60,9 +69,12 @@ which to store records of this type. It is associated with the int64 ID that is
 generated by the ql engine. The "ql" tags identify application fields that will
 be stored in the database. The tag value is the name to use in the database; an
 asterisk indicates that the field name itself will be used. The Name field is
-indexed for fast record selection by virtue of the "ql_index". All managed
-fields must be exported, that is, their names must begin with an uppercase
-letter.
+indexed for fast record selection by virtue of the "ql_index". Fields tagged
+"ql" must be exported, that is, their names must begin with an uppercase
+letter; the ID field may be unexported unless qlm is built with the qlm_safe
+tag. The tagged fields of an anonymous embedded structure are stored in the
+table of the enclosing structure as if they had been declared in it directly,
+so a group of columns that several tables share can be declared once.
 
 See the tutorials in the qlm_test.go file (shown as examples in this
 documentation) for other operations.
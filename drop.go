@@ -0,0 +1,58 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+)
+
+// TableDrop removes the table associated with the type of the record pointed
+// to by recPtr, along with its indexes and the records it contains. An error
+// is set if the table does not exist. See also TableDropIfExists().
+func (db *DbType) TableDrop(recPtr interface{}) {
+	db.tableDrop(recPtr, false)
+}
+
+// TableDropIfExists is like TableDrop except that no error is set if the
+// table does not exist.
+func (db *DbType) TableDropIfExists(recPtr interface{}) {
+	db.tableDrop(recPtr, true)
+}
+
+func (db *DbType) tableDrop(recPtr interface{}, ifExists bool) {
+	if db.err != nil {
+		return
+	}
+	dsc := db.dscFromPtr(recPtr)
+	if db.writable(dsc) {
+		db.TableDropName(dsc.tblStr, ifExists)
+	}
+}
+
+// TableDropName removes the table named tblStr, along with its indexes and the
+// records it contains. This is useful for tables that have no corresponding
+// record type, for example ones that remain after a type has been renamed. If
+// ifExists is false, an error is set if the table does not exist.
+func (db *DbType) TableDropName(tblStr string, ifExists bool) {
+	if db.err != nil {
+		return
+	}
+	db.TransactBegin()
+	cmd := fmt.Sprintf("DROP TABLE%s %s;", strIf(ifExists, " IF EXISTS", ""), tblStr)
+	_, _ = db.Exec(cmd)
+	db.transactEnd(db.err == nil)
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the removal of tables.
+func ExampleDbType_TableDrop() {
+	type recType struct {
+		ID   int64  `ql_table:"rec" ql_index:"*"`
+		Name string `ql:"*" ql_index:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&recType{})
+	db.TransactBegin()
+	db.Exec("CREATE TABLE legacy (Code string);")
+	db.TransactCommit()
+	count := func() (n int64) {
+		rs, _ := db.Exec("SELECT count(*) FROM __Table WHERE !hasPrefix(Name, \"__\");")
+		if db.OK() {
+			row, _ := rs[0].FirstRow()
+			n = row[0].(int64)
+		}
+		return
+	}
+	fmt.Println(count())
+	db.TableDrop(&recType{})
+	db.TableDropName("legacy", false)
+	fmt.Println(count())
+	db.TableDropIfExists(&recType{})
+	db.TableDropName("legacy", true)
+	fmt.Println(db.Error())
+	db.TableDrop(&recType{})
+	fmt.Println(db.Error())
+	db.ClearError()
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// 2
+	// 0
+	// <nil>
+	// DROP TABLE: table rec does not exist
+}
@@ -0,0 +1,246 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dumpRowCount is the number of records written with each INSERT statement
+// by Dump().
+const dumpRowCount = 100
+
+// qlLiteral returns the ql expression that evaluates to val, a value
+// retrieved from ql. Values other than strings and booleans are converted
+// explicitly to their ql type so that they are stored with that type.
+func qlLiteral(val interface{}) (str string, err error) {
+	switch v := val.(type) {
+	case nil:
+		str = "NULL"
+	case bool:
+		str = strconv.FormatBool(v)
+	case string:
+		str = strconv.Quote(v)
+	case []byte:
+		str = fmt.Sprintf("blob(%s)", strconv.Quote(string(v)))
+	case int8, int16, int32, int64, uint8, uint16, uint32, uint64:
+		str = fmt.Sprintf("%s(%d)", qlTypeStr(reflect.TypeOf(v)), v)
+	case float32:
+		str = fmt.Sprintf("float32(%s)", strconv.FormatFloat(float64(v), 'g', -1, 32))
+	case float64:
+		str = fmt.Sprintf("float64(%s)", strconv.FormatFloat(v, 'g', -1, 64))
+	case complex64:
+		str = fmt.Sprintf("complex64(complex(%s, %s))", strconv.FormatFloat(float64(real(v)), 'g', -1, 32),
+			strconv.FormatFloat(float64(imag(v)), 'g', -1, 32))
+	case complex128:
+		str = fmt.Sprintf("complex128(complex(%s, %s))", strconv.FormatFloat(real(v), 'g', -1, 64),
+			strconv.FormatFloat(imag(v), 'g', -1, 64))
+	case *big.Int:
+		str = fmt.Sprintf("bigint(%q)", v.String())
+	case *big.Rat:
+		str = fmt.Sprintf("bigrat(%q)", v.String())
+	case time.Time:
+		str = fmt.Sprintf("parseTime(%q, %q)", time.RFC3339Nano, v.Format(time.RFC3339Nano))
+	case time.Duration:
+		str = fmt.Sprintf("duration(%d)", int64(v))
+	default:
+		err = fmt.Errorf("cannot write value of type %T", val)
+	}
+	return
+}
+
+// Dump writes to w a script of ql statements that reproduces the tables named
+// in tblNames, or all tables if none are named. For each table, the CREATE
+// TABLE statement, the CREATE INDEX statements and INSERT statements for its
+// records are written. Each statement occupies one line and the script is
+// enclosed in a transaction, so it can be replayed with Load() or with the ql
+// command line tool. The dump is made within a transaction and so reflects a
+// consistent state of the database. Record identifiers are assigned anew when
+// the script is replayed, so values that refer to the identifiers of other
+// records are not preserved.
+func (db *DbType) Dump(w io.Writer, tblNames ...string) {
+	if db.err != nil {
+		return
+	}
+	var tblList []struct {
+		Name   string
+		Schema string
+	}
+	var idxList []struct {
+		TableName  string
+		ColumnName string
+		Name       string
+		IsUnique   bool
+	}
+	db.TransactBegin()
+	db.RawRetrieve(&tblList, "SELECT Name, Schema FROM __Table WHERE !hasPrefix(Name, \"__\") ORDER BY Name;")
+	db.RawRetrieve(&idxList, "SELECT TableName, ColumnName, Name, IsUnique FROM __Index "+
+		"WHERE !hasPrefix(TableName, \"__\") ORDER BY Name;")
+	if len(tblNames) > 0 && db.err == nil {
+		wantMap := make(map[string]bool)
+		for _, nameStr := range tblNames {
+			wantMap[nameStr] = true
+		}
+		j := 0
+		for _, tbl := range tblList {
+			if wantMap[tbl.Name] {
+				delete(wantMap, tbl.Name)
+				tblList[j] = tbl
+				j++
+			}
+		}
+		tblList = tblList[:j]
+		for _, nameStr := range tblNames {
+			if wantMap[nameStr] {
+				db.SetErrorf("table %s does not exist", nameStr)
+			}
+		}
+	}
+	bw := bufio.NewWriter(w)
+	if db.err == nil {
+		fmt.Fprintf(bw, "// ql database dump written by qlm\nBEGIN TRANSACTION;\n")
+	}
+	for _, tbl := range tblList {
+		if db.err != nil {
+			break
+		}
+		fmt.Fprintf(bw, "%s\n", tbl.Schema)
+		for _, idx := range idxList {
+			if idx.TableName == tbl.Name {
+				fmt.Fprintf(bw, "CREATE %sINDEX %s ON %s (%s);\n", strIf(idx.IsUnique, "UNIQUE ", ""),
+					idx.Name, idx.TableName, idx.ColumnName)
+			}
+		}
+		db.dumpRows(bw, tbl.Name)
+	}
+	if db.err == nil {
+		fmt.Fprintf(bw, "COMMIT;\n")
+		db.err = bw.Flush()
+	}
+	db.transactEnd(db.err == nil)
+}
+
+// dumpRows writes to bw the INSERT statements that reproduce the records of
+// the table tblStr.
+func (db *DbType) dumpRows(bw *bufio.Writer, tblStr string) {
+	var nameList []string
+	for _, col := range db.tableColumns(tblStr) {
+		nameList = append(nameList, col.Name)
+	}
+	if len(nameList) == 0 || db.err != nil {
+		return
+	}
+	rs, _ := db.Exec(fmt.Sprintf("SELECT %s FROM %s ORDER BY id();", strings.Join(nameList, ", "), tblStr))
+	prefixStr := fmt.Sprintf("INSERT INTO %s (%s) VALUES ", tblStr, strings.Join(nameList, ", "))
+	count := 0
+	valList := make([]string, len(nameList))
+	for _, res := range rs {
+		if db.err != nil {
+			break
+		}
+		db.err = res.Do(false, func(data []interface{}) (more bool, err error) {
+			for j := range valList {
+				if valList[j], err = qlLiteral(data[j]); err != nil {
+					return false, fmt.Errorf("table %s, column %s: %s", tblStr, nameList[j], err)
+				}
+			}
+			if count == 0 {
+				bw.WriteString(prefixStr)
+			} else {
+				bw.WriteString(", ")
+			}
+			fmt.Fprintf(bw, "(%s)", strings.Join(valList, ", "))
+			count++
+			if count == dumpRowCount {
+				bw.WriteString(";\n")
+				count = 0
+			}
+			return true, nil
+		})
+	}
+	if count > 0 {
+		bw.WriteString(";\n")
+	}
+}
+
+// Load executes the script read from r, typically one written by Dump(). Each
+// line of the script must hold one complete statement; blank lines and lines
+// that begin with "//" are skipped. The statements are executed in order
+// within a single transaction. The BEGIN TRANSACTION, COMMIT and ROLLBACK
+// statements of the script, such as those written by Dump(), are nested
+// within that transaction. Execution stops at the first statement that
+// fails, in which case every transaction begun by the script, as well as the
+// enclosing one, is rolled back and the database error identifies the line
+// of the failing statement. A script that ends with a transaction still open
+// is treated as having failed. Statements executed by Load are not retained
+// in the statement cache and are not included in Stats().
+func (db *DbType) Load(r io.Reader) {
+	if db.err != nil {
+		return
+	}
+	br := bufio.NewReader(r)
+	db.transient = true
+	db.TransactBegin()
+	base := db.transact.nest // Levels above base are begun by the script
+	line := 1
+	for ; db.err == nil; line++ {
+		str, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			db.err = err
+			break
+		}
+		stmtStr := strings.TrimSpace(str)
+		if len(stmtStr) > 0 && !strings.HasPrefix(stmtStr, "//") {
+			switch kwStr := strings.ToUpper(strings.Join(strings.Fields(strings.TrimRight(stmtStr, "; \t")), " ")); kwStr {
+			case "BEGIN TRANSACTION":
+				db.TransactBegin()
+			case "COMMIT", "ROLLBACK":
+				if db.transact.nest > base {
+					db.transactEnd(kwStr == "COMMIT")
+				} else {
+					db.SetErrorf("no transaction to %s", strings.ToLower(kwStr))
+				}
+			default:
+				db.Exec(stmtStr)
+			}
+			if db.err != nil {
+				db.err = fmt.Errorf("line %d: %s", line, db.err)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+	if db.err == nil && db.transact.nest > base {
+		db.SetErrorf("script ends within a transaction")
+	}
+	for base > 0 && db.transact.nest >= base {
+		nest := db.transact.nest
+		db.transactEnd(db.err == nil)
+		if db.transact.nest == nest {
+			break
+		}
+	}
+	db.transient = false
+}
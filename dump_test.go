@@ -0,0 +1,114 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/jung-kurt/qlm"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+// This example demonstrates copying a database with a dump script.
+func ExampleDbType_Dump() {
+	type itemType struct {
+		ID    int64         `ql_table:"item"`
+		Name  string        `ql:"*,unique"`
+		Qty   int32         `ql:"*"`
+		Price big.Rat       `ql:"*"`
+		Added time.Time     `ql:"*"`
+		Ttl   time.Duration `ql:"*"`
+		Img   []byte        `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&itemType{})
+	list := []itemType{
+		{Name: "bolt \"M6\"", Qty: 120, Added: time.Date(2016, 5, 1, 10, 0, 0, 0, time.UTC),
+			Ttl: time.Hour, Img: []byte{0, 1, 2}},
+		{Name: "nut", Qty: 80, Added: time.Date(2016, 5, 2, 10, 0, 0, 0, time.UTC)},
+	}
+	list[0].Price.SetString("3/4")
+	list[1].Price.SetString("5/4")
+	db.Insert(list)
+	var buf bytes.Buffer
+	db.Dump(&buf, "item")
+	db.Close()
+	fmt.Print(buf.String())
+	os.Remove("data/copy.ql")
+	cp := qlm.DbCreate("data/copy.ql")
+	cp.Load(&buf)
+	list = nil
+	cp.Retrieve(&list, "ORDER BY Name")
+	for _, it := range list {
+		fmt.Println(it.Name, it.Qty, it.Price.FloatString(2), it.Added.UTC().Format("Jan 2"), it.Ttl, it.Img)
+	}
+	cp.Close()
+	for _, d := range []*qlm.DbType{db, cp} {
+		if d.Err() {
+			fmt.Println(d.Error())
+		}
+	}
+	// Output:
+	// // ql database dump written by qlm
+	// BEGIN TRANSACTION;
+	// CREATE TABLE item (Name string, Qty int32, Price bigrat, Added time, Ttl duration, Img blob);
+	// CREATE UNIQUE INDEX itemName ON item (Name);
+	// INSERT INTO item (Name, Qty, Price, Added, Ttl, Img) VALUES ("bolt \"M6\"", int32(120), bigrat("3/4"), parseTime("2006-01-02T15:04:05.999999999Z07:00", "2016-05-01T10:00:00Z"), duration(3600000000000), blob("\x00\x01\x02")), ("nut", int32(80), bigrat("5/4"), parseTime("2006-01-02T15:04:05.999999999Z07:00", "2016-05-02T10:00:00Z"), duration(0), blob(""));
+	// COMMIT;
+	// bolt "M6" 120 0.75 May 1 1h0m0s [0 1 2]
+	// nut 80 1.25 May 2 0s []
+}
+
+// This example demonstrates that a script that fails partway leaves the
+// database unchanged and usable. Here, the script attempts to create a table
+// that already exists.
+func ExampleDbType_Load() {
+	type itemType struct {
+		ID   int64  `ql_table:"item"`
+		Name string `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&itemType{})
+	db.Insert([]itemType{{Name: "bolt"}})
+	var buf bytes.Buffer
+	db.Dump(&buf)
+	db.Load(strings.NewReader("CREATE TABLE extra (Str string);\n" + buf.String()))
+	fmt.Println(db.Error())
+	db.ClearError()
+	db.Insert([]itemType{{Name: "nut"}})
+	db.Close()
+	db = qlm.DbOpen("data/example.ql")
+	var list []itemType
+	db.Retrieve(&list, "ORDER BY Name")
+	fmt.Println(len(list), list[0].Name, list[1].Name, len(db.Tables()))
+	db.Load(strings.NewReader("BEGIN TRANSACTION;\nCREATE TABLE extra (Str string);\n"))
+	fmt.Println(db.Error())
+	db.ClearError()
+	fmt.Println(len(db.Tables()))
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// line 4: CREATE TABLE: table exists item
+	// 2 bolt nut 1
+	// script ends within a transaction
+	// 1
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"github.com/cznic/ql"
+)
+
+// The methods in this file parallel the principal DbType methods but return
+// the error of the operation rather than leaving it in the database instance.
+// Each one runs only if the database has no error, and clears the error
+// before returning it, so that a failed call does not halt subsequent ones.
+// This suits code that checks each result in the usual Go fashion, for
+// example
+//
+//	if err := db.InsertE(list); err != nil {
+//		return fmt.Errorf("saving orders: %w", err)
+//	}
+//
+// The returned error is the one that would otherwise be reported by Error(),
+// so errors.Is() and errors.As() can be used with values such as ErrNotFound
+// and DuplicateError. Calls of both kinds may be mixed; an error left by a
+// method that does not return one is returned, and cleared, by the next E
+// method.
+
+// errTake returns the current error and clears it.
+func (db *DbType) errTake() (err error) {
+	err = db.err
+	db.err = nil
+	return
+}
+
+// ExecE is like Exec but returns the error of the operation.
+func (db *DbType) ExecE(cmdStr string, prms ...interface{}) (rs []ql.Recordset, index int, err error) {
+	rs, index = db.Exec(cmdStr, prms...)
+	return rs, index, db.errTake()
+}
+
+// TableCreateE is like TableCreate but returns the error of the operation.
+func (db *DbType) TableCreateE(recPtr interface{}) error {
+	db.TableCreate(recPtr)
+	return db.errTake()
+}
+
+// TableEnsureE is like TableEnsure but returns the error of the operation.
+func (db *DbType) TableEnsureE(recPtr interface{}) error {
+	db.TableEnsure(recPtr)
+	return db.errTake()
+}
+
+// InsertE is like Insert but returns the error of the operation.
+func (db *DbType) InsertE(slice interface{}) error {
+	db.Insert(slice)
+	return db.errTake()
+}
+
+// RetrieveE is like Retrieve but returns the error of the operation.
+func (db *DbType) RetrieveE(slicePtr interface{}, tailStr string, prms ...interface{}) error {
+	db.Retrieve(slicePtr, tailStr, prms...)
+	return db.errTake()
+}
+
+// RetrieveOneE is like RetrieveOne but returns the error of the operation.
+// ErrNotFound is returned if no record satisfies the specified conditions.
+func (db *DbType) RetrieveOneE(recPtr interface{}, tailStr string, prms ...interface{}) error {
+	db.RetrieveOne(recPtr, tailStr, prms...)
+	return db.errTake()
+}
+
+// UpdateE is like Update but returns the error of the operation.
+func (db *DbType) UpdateE(recPtr interface{}, fldNames ...string) error {
+	db.Update(recPtr, fldNames...)
+	return db.errTake()
+}
+
+// DeleteE is like Delete but returns the error of the operation.
+func (db *DbType) DeleteE(recPtr interface{}, tailStr string, prms ...interface{}) error {
+	db.Delete(recPtr, tailStr, prms...)
+	return db.errTake()
+}
+
+// TruncateE is like Truncate but returns the error of the operation.
+func (db *DbType) TruncateE(recPtr interface{}) error {
+	db.Truncate(recPtr)
+	return db.errTake()
+}
+
+// TransactBeginE is like TransactBegin but returns the error of the
+// operation.
+func (db *DbType) TransactBeginE() error {
+	db.TransactBegin()
+	return db.errTake()
+}
+
+// TransactCommitE is like TransactCommit but returns the error of the
+// operation.
+func (db *DbType) TransactCommitE() error {
+	db.TransactCommit()
+	return db.errTake()
+}
+
+// TransactRollbackE is like TransactRollback but returns the error of the
+// operation.
+func (db *DbType) TransactRollbackE() error {
+	db.TransactRollback()
+	return db.errTake()
+}
+
+// CloseE is like Close but returns the error of the operation.
+func (db *DbType) CloseE() error {
+	db.Close()
+	return db.errTake()
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the error-returning methods.
+func ExampleDbType_InsertE() {
+	type userType struct {
+		ID    int64  `ql_table:"user"`
+		Email string `ql:"*,unique"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	err := db.TableCreateE(&userType{})
+	if err == nil {
+		err = db.InsertE([]userType{{0, "ann@example.com"}})
+	}
+	if err == nil {
+		err = db.InsertE([]userType{{0, "ann@example.com"}})
+		err = fmt.Errorf("adding user: %w", err)
+		fmt.Println(qlm.IsDuplicate(err))
+	}
+	var user userType
+	err = db.RetrieveOneE(&user, "WHERE Email == ?1", "bob@example.com")
+	fmt.Println(errors.Is(err, qlm.ErrNotFound))
+	var list []userType
+	err = db.RetrieveE(&list, "")
+	fmt.Println(err, len(list))
+	fmt.Println(db.CloseE())
+	// Output:
+	// true
+	// true
+	// <nil> 1
+	// <nil>
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SeqAny can be passed as the expected sequence number to
+// EventStoreType.Append() to append events regardless of the current state of
+// the stream.
+const SeqAny int64 = -1
+
+// ErrSequence is the error that is set by EventStoreType.Append() when the
+// last sequence number of a stream does not match the expected value. It
+// indicates that another writer has appended to the stream since the caller
+// last read it.
+var ErrSequence = errors.New("event stream sequence number does not match expected value")
+
+// EventType is a single entry in an event stream. Stream, Seq and Tm are
+// assigned by EventStoreType.Append(); Kind and Data are left to the
+// application.
+type EventType struct {
+	ID     int64     `ql_table:"qlm_event"`
+	Stream string    `ql:"*" ql_index:"*"`
+	Seq    int64     `ql:"*"`
+	Tm     time.Time `ql:"*"`
+	Kind   string    `ql:"*"`
+	Data   []byte    `ql:"*"`
+}
+
+// EventStoreType provides append-only storage of events organized into
+// streams. Events are stored in the table qlm_event. Within each stream,
+// events are numbered consecutively starting with one. Errors are reported
+// by means of the qlm instance from which the store was obtained.
+type EventStoreType struct {
+	db *DbType
+}
+
+// EventStore returns an event store that uses db for storage. The event table
+// is created if it does not already exist.
+func (db *DbType) EventStore() (es *EventStoreType) {
+	es = &EventStoreType{db: db}
+	db.TableEnsure(&EventType{})
+	return
+}
+
+// Append adds the specified events to the end of the stream identified by
+// streamStr and returns the sequence number of the last event in the stream.
+// If expectSeq is not SeqAny, it must match the sequence number of the last
+// event currently in the stream (zero for an empty stream); otherwise nothing
+// is appended and the qlm error is set to ErrSequence. The check and the
+// append take place within a single transaction. The Stream, Seq and Tm
+// fields of the events are assigned by this method; a time value that is
+// already set is retained.
+func (es *EventStoreType) Append(streamStr string, expectSeq int64, list ...EventType) (seq int64) {
+	db := es.db
+	if db.err != nil {
+		return
+	}
+	db.TransactBegin()
+	dsc := db.dscFromPtr(&EventType{})
+	row := db.firstRow(fmt.Sprintf("SELECT max(Seq) FROM %s WHERE Stream == ?1;", dsc.tblStr), streamStr)
+	if db.err == nil {
+		if len(row) > 0 && row[0] != nil {
+			seq = row[0].(int64)
+		}
+		if expectSeq != SeqAny && expectSeq != seq {
+			db.SetError(ErrSequence)
+		}
+	}
+	if db.err == nil {
+		tm := time.Now()
+		for j := range list {
+			seq++
+			list[j].Stream = streamStr
+			list[j].Seq = seq
+			if list[j].Tm.IsZero() {
+				list[j].Tm = tm
+			}
+		}
+		db.Insert(list)
+	}
+	db.transactEnd(db.err == nil)
+	return
+}
+
+// ReadStream returns, in sequence order, the events of the stream identified
+// by streamStr whose sequence numbers are fromSeq or greater.
+func (es *EventStoreType) ReadStream(streamStr string, fromSeq int64) (list []EventType) {
+	es.db.Retrieve(&list, "WHERE Stream == ?1 && Seq >= ?2 ORDER BY Seq", streamStr, fromSeq)
+	return
+}
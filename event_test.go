@@ -0,0 +1,47 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates an event store. The second writer expects the
+// stream to be at sequence number 1, but the first writer has already
+// appended another event, so the append fails with ErrSequence.
+func ExampleEventStoreType() {
+	db := qlm.DbCreate("data/example.ql")
+	es := db.EventStore()
+	seq := es.Append("acct-17", 0, qlm.EventType{Kind: "opened"})
+	seq = es.Append("acct-17", seq, qlm.EventType{Kind: "deposit", Data: []byte("40")},
+		qlm.EventType{Kind: "withdrawal", Data: []byte("15")})
+	es.Append("acct-42", qlm.SeqAny, qlm.EventType{Kind: "opened"})
+	for _, ev := range es.ReadStream("acct-17", 2) {
+		fmt.Printf("%d %s %s\n", ev.Seq, ev.Kind, ev.Data)
+	}
+	es.Append("acct-17", 1, qlm.EventType{Kind: "closed"})
+	fmt.Println(db.Error() == qlm.ErrSequence)
+	db.ClearError()
+	fmt.Println(len(es.ReadStream("acct-17", 0)))
+	db.Close()
+	// Output:
+	// 2 deposit 40
+	// 3 withdrawal 15
+	// true
+	// 3
+}
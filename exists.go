@@ -0,0 +1,45 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+)
+
+// Exists returns true if at least one record of the type pointed to by recPtr
+// satisfies the WHERE clause of tailStr and its parameters. Any ORDER BY,
+// LIMIT or OFFSET clause in tailStr is ignored. The query stops at the first
+// match and no record is materialized. Records that have been deleted from a
+// table whose type embeds SoftModel are not considered.
+func (db *DbType) Exists(recPtr interface{}, tailStr string, prms ...interface{}) (ok bool) {
+	if db.err != nil {
+		return
+	}
+	dsc := db.dscFromPtr(recPtr)
+	if db.err == nil {
+		if len(dsc.soft.nameStr) > 0 {
+			tailStr = whereAnd(tailStr, dsc.soft.nameStr+" IS NULL")
+		}
+		whereStr, _ := tailSplit(tailStr)
+		if len(whereStr) > 0 {
+			whereStr = " WHERE " + whereStr
+		}
+		row := db.firstRow(fmt.Sprintf("SELECT id() FROM %s%s LIMIT 1;", dsc.fromStr(), whereStr), prms...)
+		ok = db.err == nil && row != nil
+	}
+	return
+}
@@ -0,0 +1,48 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates a check for matching records that does not load
+// them.
+func ExampleDbType_Exists() {
+	type userType struct {
+		qlm.SoftModel `ql_table:"user"`
+		Name          string `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&userType{})
+	db.Insert([]userType{{Name: "Ann"}, {Name: "Bob"}})
+	db.Delete(&userType{}, "WHERE Name == ?1", "Bob")
+	for _, nameStr := range []string{"Ann", "Bob", "Cy"} {
+		fmt.Println(nameStr, db.Exists(&userType{}, "WHERE Name == ?1 ORDER BY Name", nameStr))
+	}
+	fmt.Println(db.Exists(&userType{}, ""))
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// Ann true
+	// Bob false
+	// Cy false
+	// true
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// expireBatch is the maximum number of records deleted in each transaction by
+// ExpireNow()
+const expireBatch = 256
+
+// ttlSet records in dsc the time-to-live option of the column sqlStr.
+func (db *DbType) ttlSet(dsc *qlDscType, sf reflect.StructField, sqlStr, ttlStr string) {
+	dur, err := time.ParseDuration(ttlStr)
+	switch {
+	case err != nil:
+		db.SetErrorf("invalid ttl option for field %s: %s", sf.Name, err)
+	case sf.Type != reflect.TypeOf(time.Time{}):
+		db.SetErrorf("ttl option requires field %s to be of type time.Time", sf.Name)
+	case len(dsc.ttl.nameStr) > 0:
+		db.SetErrorf("multiple occurrence of ttl option")
+	default:
+		dsc.ttl.nameStr = sqlStr
+		dsc.ttl.dur = dur
+	}
+}
+
+// ExpireNow deletes the records that have outlived the time-to-live specified
+// for their table. A time-to-live is assigned to a table by means of the "ttl"
+// option in the "ql" tag of a time.Time field, for example
+//
+//	Created time.Time `ql:"created,ttl=720h"`
+//
+// Records in which the tagged field is older than the current time less the
+// time-to-live are deleted in batches, each in its own transaction, so that
+// large backlogs do not result in a single enormous transaction. Records are
+// removed even if their type embeds SoftModel.
+//
+// The tables to examine are specified by one or more record pointers. If none
+// are specified, all tables with a time-to-live whose record types have been
+// used with db are examined. The number of deleted records is returned.
+func (db *DbType) ExpireNow(recPtrs ...interface{}) (count int64) {
+	if db.err != nil {
+		return
+	}
+	var dscList []qlDscType
+	if len(recPtrs) > 0 {
+		for _, recPtr := range recPtrs {
+			dscList = append(dscList, db.dscFromPtr(recPtr))
+		}
+	} else {
+		dscList = db.dscCache.list()
+	}
+	tm := time.Now()
+	for _, dsc := range dscList {
+		if db.err == nil && len(dsc.ttl.nameStr) > 0 {
+			tailStr := fmt.Sprintf("WHERE %s < ?1 LIMIT %d", dsc.ttl.nameStr, expireBatch)
+			more := true
+			for more && db.err == nil {
+				idList := db.idList(dsc, tailStr, tm.Add(-dsc.ttl.dur))
+				db.deleteIDs(dsc, idList)
+				count += int64(len(idList))
+				more = len(idList) == expireBatch
+			}
+		}
+	}
+	db.expire.last = tm
+	return
+}
+
+// ExpireEvery arranges for ExpireNow() to be called for all tables with a
+// time-to-live whenever Insert() is called and at least the specified
+// duration has elapsed since the previous sweep. A duration of zero disables
+// automatic sweeping. The sweep takes place in the goroutine that calls
+// Insert(), so no synchronization is needed on the part of the application.
+func (db *DbType) ExpireEvery(dur time.Duration) {
+	if db.err == nil {
+		db.expire.every = dur
+		db.expire.last = time.Now()
+	}
+}
+
+// expireCheck performs an automatic sweep if one is due.
+func (db *DbType) expireCheck() {
+	if db.expire.every > 0 && time.Since(db.expire.last) >= db.expire.every {
+		db.ExpireNow()
+	}
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+	"time"
+)
+
+// This example demonstrates the expiration of records. The "ttl" option in
+// the tag of the Tm field specifies that records are to be deleted when they
+// are more than a day old.
+func ExampleDbType_ExpireNow() {
+	type logType struct {
+		ID  int64     `ql_table:"log"`
+		Tm  time.Time `ql:"*,ttl=24h"`
+		Msg string    `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&logType{})
+	now := time.Now()
+	var list []logType
+	for j := 0; j < 1000; j++ {
+		list = append(list, logType{0, now.Add(-time.Hour*time.Duration(j) - 30*time.Minute), fmt.Sprintf("msg %d", j)})
+	}
+	db.Insert(list)
+	fmt.Println(db.ExpireNow())
+	list = nil
+	db.Retrieve(&list, "")
+	fmt.Println(len(list))
+	type badType struct {
+		ID  int64  `ql_table:"bad"`
+		Msg string `ql:"*,ttl=24h"`
+	}
+	db.TableCreate(&badType{})
+	fmt.Println(db.Error())
+	db.Close()
+	// Output:
+	// 976
+	// 24
+	// ttl option requires field Msg to be of type time.Time
+}
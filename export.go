@@ -0,0 +1,157 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// ExportJSON writes to w the records of the type pointed to by recPtr that
+// satisfy tailStr and its parameters, which are handled as they are in
+// Retrieve(). The records are written as newline-delimited JSON: one object
+// per line, with the column names of the table as keys, in column order. The
+// identifier of each record is written with the name of its field, for
+// example "ID". Values are encoded as by the encoding/json package, so, for
+// example, time.Time values are written in RFC 3339 format, []byte values in
+// base64, and big.Rat values as strings. Records are written as they are
+// read, so memory use does not depend on the number of records. The number of
+// records written is returned.
+func (db *DbType) ExportJSON(recPtr interface{}, w io.Writer, tailStr string, prms ...interface{}) (count int64) {
+	dsc := db.dscFromPtr(recPtr)
+	if db.err != nil {
+		return
+	}
+	var sfList []reflect.StructField
+	var keyList [][]byte
+	for j, sf := range dsc.sel.sfList {
+		if dsc.sel.typeStrList[j] != "expr" { // Computed fields cannot be imported
+			key, _ := json.Marshal(dsc.selName(sf))
+			sfList = append(sfList, sf)
+			keyList = append(keyList, key)
+		}
+	}
+	bw := bufio.NewWriter(w)
+	db.retrieveEach(dsc, false, func(recVl reflect.Value) (err error) {
+		var buf []byte
+		bw.WriteByte('{')
+		for j, fldVl := range valueList(recVl, sfList) {
+			// The address is encoded so that pointer methods such as those of
+			// big.Rat are used
+			if buf, err = json.Marshal(fldVl.Addr().Interface()); err != nil {
+				return fmt.Errorf("encoding column %s: %s", keyList[j], err)
+			}
+			if j > 0 {
+				bw.WriteByte(',')
+			}
+			bw.Write(keyList[j])
+			bw.WriteByte(':')
+			bw.Write(buf)
+		}
+		bw.WriteString("}\n")
+		count++
+		return
+	}, tailStr, prms...)
+	if db.err == nil {
+		db.err = bw.Flush()
+	}
+	return
+}
+
+// selName returns the column name of the selected field sf of the record type
+// described by dsc, or the name of the field if it is the identifier.
+func (dsc qlDscType) selName(sf reflect.StructField) string {
+	for j, f := range dsc.insert.sfList {
+		if reflect.DeepEqual(f.Index, sf.Index) {
+			return dsc.insert.nameList[j]
+		}
+	}
+	return sf.Name
+}
+
+// ImportJSON reads newline-delimited JSON from r, in the form written by
+// ExportJSON(), and inserts each object as a record of the type pointed to by
+// recPtr. The keys of each object are matched to the column names of the
+// table; a key that matches no column is an error. The identifier key is
+// ignored, since identifiers are assigned by Insert(). Values are decoded as
+// by the encoding/json package into the fields of the record. The records are
+// inserted in batches within a single transaction, so either all records are
+// imported or, if an error occurs, none are. The number of records inserted
+// is returned.
+func (db *DbType) ImportJSON(recPtr interface{}, r io.Reader) (count int64) {
+	dsc := db.dscFromPtr(recPtr)
+	if !db.writable(dsc) {
+		return
+	}
+	const batchSize = 500
+	dec := json.NewDecoder(r)
+	sliceVl := reflect.MakeSlice(reflect.SliceOf(dsc.recTp), 0, batchSize)
+	flush := func() {
+		if sliceVl.Len() > 0 {
+			db.Insert(sliceVl.Interface())
+			if db.err == nil {
+				count += int64(sliceVl.Len())
+			}
+			sliceVl = sliceVl.Slice(0, 0)
+		}
+	}
+	db.TransactBegin()
+	for objJ := 1; db.err == nil; objJ++ {
+		var obj map[string]json.RawMessage
+		err := dec.Decode(&obj)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			db.SetErrorf("decoding JSON object %d: %s", objJ, err)
+			break
+		}
+		recVl := reflect.New(dsc.recTp).Elem()
+		for keyStr, raw := range obj {
+			sf, ok := dsc.nameMap[keyStr]
+			switch {
+			case ok:
+				fldVl := valueList(recVl, []reflect.StructField{sf})[0]
+				if err = json.Unmarshal(raw, fldVl.Addr().Interface()); err != nil {
+					db.SetErrorf("decoding JSON object %d, key %s: %s", objJ, keyStr, err)
+				}
+			case keyStr != dsc.idSf.Name:
+				db.SetErrorf("JSON key %s corresponds to no column of table %s", keyStr, dsc.tblStr)
+			}
+			if db.err != nil {
+				break
+			}
+		}
+		if db.err == nil {
+			sliceVl = reflect.Append(sliceVl, recVl)
+			if sliceVl.Len() == batchSize {
+				flush()
+			}
+		}
+	}
+	if db.err == nil {
+		flush()
+	}
+	db.transactEnd(db.err == nil)
+	if db.err != nil {
+		count = 0
+	}
+	return
+}
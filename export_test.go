@@ -0,0 +1,72 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/jung-kurt/qlm"
+	"math/big"
+	"os"
+	"time"
+)
+
+// This example demonstrates the transfer of records between databases as
+// newline-delimited JSON.
+func ExampleDbType_ExportJSON() {
+	type itemType struct {
+		ID    int64     `ql_table:"item"`
+		Name  string    `ql:"name"`
+		Price big.Rat   `ql:"price"`
+		Added time.Time `ql:"added"`
+		Tags  []string  `ql:"tags,json"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&itemType{})
+	list := []itemType{
+		{Name: "bolt", Added: time.Date(2016, 5, 1, 10, 0, 0, 0, time.UTC), Tags: []string{"steel"}},
+		{Name: "nut", Added: time.Date(2016, 5, 2, 10, 0, 0, 0, time.UTC)},
+	}
+	list[0].Price.SetString("3/4")
+	list[1].Price.SetString("5/4")
+	db.Insert(list)
+	var buf bytes.Buffer
+	count := db.ExportJSON(&itemType{}, &buf, "ORDER BY name")
+	fmt.Println(count)
+	db.Close()
+	os.Remove("data/copy.ql")
+	cp := qlm.DbCreate("data/copy.ql")
+	cp.TableCreate(&itemType{})
+	count = cp.ImportJSON(&itemType{}, &buf)
+	fmt.Println(count)
+	list = nil
+	cp.Retrieve(&list, "ORDER BY name")
+	for _, it := range list {
+		fmt.Println(it.Name, it.Price.FloatString(2), it.Added.Format("Jan 2"), it.Tags)
+	}
+	cp.Close()
+	for _, d := range []*qlm.DbType{db, cp} {
+		if d.Err() {
+			fmt.Println(d.Error())
+		}
+	}
+	// Output:
+	// 2
+	// 2
+	// bolt 0.75 May 1 [steel]
+	// nut 1.25 May 2 []
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"reflect"
+	"strings"
+)
+
+// exprTagPrefix introduces the expression of a computed field in a "ql" tag.
+const exprTagPrefix = "expr:"
+
+// exprSet configures dsc for the computed field sf whose "ql" tag specifies
+// the SELECT expression exprStr, for example `ql:"expr:len(Name),ro"`. The
+// value of the expression is assigned to the field when records are
+// retrieved. The field has no column, so it is excluded from the statements
+// that create, insert and update records. The only option permitted with an
+// expression is "ro", which states explicitly that the field is read-only.
+func (db *DbType) exprSet(dsc *qlDscType, sf reflect.StructField, exprStr string, optMap map[string]string) {
+	exprStr = strings.TrimSpace(exprStr)
+	if len(exprStr) == 0 {
+		db.SetErrorf("missing expression in ql tag of field %s", sf.Name)
+		return
+	}
+	for optStr := range optMap {
+		if optStr != "ro" {
+			db.SetErrorf("option %s cannot be used with expression field %s", optStr, sf.Name)
+			return
+		}
+	}
+	if len(sf.PkgPath) > 0 {
+		db.SetErrorf("field %s must be exported", sf.Name)
+		return
+	}
+	dsc.sel.sfList = append(dsc.sel.sfList, sf)
+	dsc.sel.typeStrList = append(dsc.sel.typeStrList, "expr")
+}
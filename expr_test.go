@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+	"time"
+)
+
+// This example demonstrates computed fields, which are assigned the value of
+// a SELECT expression when records are retrieved.
+func ExampleDbType_expression() {
+	type lineType struct {
+		ID      int64     `ql_table:"line"`
+		Name    string    `ql:"*"`
+		Qty     int64     `ql:"*"`
+		Price   float64   `ql:"*"`
+		Tm      time.Time `ql:"*"`
+		NameLen int       `ql:"expr:len(Name),ro"`
+		Total   float64   `ql:"expr:float64(Qty)*Price"`
+		Month   string    `ql:"expr:formatTime(timeIn(Tm, \"UTC\"), \"2006-01\")"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&lineType{})
+	tm := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	db.Insert([]lineType{{Name: "widget", Qty: 3, Price: 2.5, Tm: tm, NameLen: 99}})
+	var list []lineType
+	db.Retrieve(&list, "WHERE Qty > ?1", int64(1))
+	rec := list[0]
+	fmt.Println(rec.Name, rec.NameLen, rec.Total, rec.Month)
+	rec.Qty = 4
+	rec.Total = 0
+	db.Update(&rec, "*")
+	list = nil
+	db.Retrieve(&list, "")
+	fmt.Println(list[0].Qty, list[0].Total)
+	var strList []string
+	for _, col := range db.Columns("line") {
+		strList = append(strList, col.Name)
+	}
+	fmt.Println(strList)
+	type badType struct {
+		ID  int64  `ql_table:"bad"`
+		Str string `ql:"expr:len(Name),unique"`
+	}
+	db.Retrieve(&[]badType{}, "")
+	fmt.Println(db.Error())
+	db.ClearError()
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// widget 6 7.5 2024-03
+	// 4 10
+	// [Name Qty Price Tm]
+	// option unique cannot be used with expression field Str
+}
@@ -0,0 +1,37 @@
+//go:build !qlm_safe
+
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// fieldUnexported reports whether fieldValue can reach unexported fields.
+// Columns tagged "ql" must be exported in any case; this governs only the
+// field tagged "ql_table" that holds the record ID.
+const fieldUnexported = true
+
+// fieldValue returns the settable field sf of the addressable structure
+// recVl. The field is located by its offset, so an unexported ID field is
+// accessible. Building with the qlm_safe tag replaces this with an
+// implementation that does not import package unsafe.
+func fieldValue(recVl reflect.Value, sf reflect.StructField) reflect.Value {
+	return reflect.Indirect(reflect.NewAt(sf.Type, unsafe.Pointer(recVl.UnsafeAddr()+sf.Offset)))
+}
@@ -0,0 +1,36 @@
+//go:build qlm_safe
+
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"reflect"
+)
+
+// fieldUnexported reports whether fieldValue can reach unexported fields.
+// Package reflect cannot set them, so in this mode the field tagged
+// "ql_table" must be exported like the columns tagged "ql".
+const fieldUnexported = false
+
+// fieldValue returns the settable field sf of the addressable structure
+// recVl. This implementation, selected with the qlm_safe build tag, uses
+// only package reflect for environments in which package unsafe is not
+// permitted. It is somewhat slower than the default.
+func fieldValue(recVl reflect.Value, sf reflect.StructField) reflect.Value {
+	return recVl.FieldByIndex(sf.Index)
+}
@@ -0,0 +1,157 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// goTypeMap associates the ql types reported in ql's system tables with the
+// Go types of the fields generated by GenerateStructs.
+var goTypeMap = map[string]string{
+	"bigint":     "big.Int",
+	"bigrat":     "big.Rat",
+	"blob":       "[]byte",
+	"bool":       "bool",
+	"complex128": "complex128",
+	"complex64":  "complex64",
+	"duration":   "time.Duration",
+	"float32":    "float32",
+	"float64":    "float64",
+	"int16":      "int16",
+	"int32":      "int32",
+	"int64":      "int64",
+	"int8":       "int8",
+	"string":     "string",
+	"time":       "time.Time",
+	"uint16":     "uint16",
+	"uint32":     "uint32",
+	"uint64":     "uint64",
+	"uint8":      "uint8",
+}
+
+// goName returns an exported Go identifier derived from the ql name str, for
+// example "OrderDate" for "order_date".
+func goName(str string) string {
+	var buf []rune
+	upper := true
+	for _, rn := range str {
+		switch {
+		case unicode.IsLetter(rn) || unicode.IsDigit(rn):
+			if len(buf) == 0 && unicode.IsDigit(rn) {
+				buf = append(buf, 'X')
+			}
+			if upper {
+				rn = unicode.ToUpper(rn)
+			}
+			buf = append(buf, rn)
+			upper = false
+		default:
+			upper = true
+		}
+	}
+	if len(buf) == 0 {
+		buf = []rune("X")
+	}
+	return string(buf)
+}
+
+// defaultOpt returns the value of the default option that corresponds to the
+// ql expression exprStr from the DEFAULT clause of a column definition. An
+// empty string is returned if the expression is not a literal that the
+// option can express.
+func defaultOpt(exprStr string) (str string) {
+	if s, err := strconv.Unquote(exprStr); err == nil {
+		str = s
+	} else if _, err = strconv.ParseFloat(exprStr, 64); err == nil {
+		str = exprStr
+	} else if _, err = strconv.ParseBool(exprStr); err == nil {
+		str = exprStr
+	}
+	if strings.ContainsAny(str, ",\"'`()") {
+		str = ""
+	}
+	return
+}
+
+// GenerateStructs writes to w a Go type definition, with the tags required by
+// qlm, for each table in the ql database file at dbPath. This is useful when
+// adopting qlm for a database created by another application. Each type is
+// named after its table, for example CustOrderType for cust_order, and has an
+// ID field for the record's id(). Simple indexes are expressed with the
+// ql_index tag and the unique option; literal column defaults are expressed
+// with the default option. Constraints are not expressed. The output is
+// formatted but is not preceded by a package clause or imports; fields of
+// types from the time and math/big packages refer to them as time and big.
+// The database is opened but not modified.
+func GenerateStructs(dbPath string, w io.Writer) error {
+	db := DbOpen(dbPath)
+	var buf bytes.Buffer
+	for _, tbl := range db.Tables() {
+		colList := db.Columns(tbl.Name)
+		if db.err != nil {
+			break
+		}
+		idxMap := make(map[string]IndexInfoType)
+		for _, idx := range tbl.Indexes {
+			idxMap[idx.Column] = idx
+		}
+		idStr := "ID"
+		for _, col := range colList {
+			if goName(col.Name) == idStr {
+				idStr = "RowID"
+			}
+		}
+		typeStr := goName(tbl.Name) + "Type"
+		fmt.Fprintf(&buf, "\n// %s holds a record of the %s table.\ntype %s struct {\n", typeStr, tbl.Name, typeStr)
+		fmt.Fprintf(&buf, "%s int64 `ql_table:\"%s\"%s`\n", idStr, tbl.Name,
+			strIf(len(idxMap["id()"].Name) > 0, ` ql_index:"*"`, ""))
+		for _, col := range colList {
+			fldStr := goName(col.Name)
+			tagStr := strIf(fldStr == col.Name, "*", col.Name)
+			fldTypeStr, ok := goTypeMap[col.Type]
+			if !ok {
+				db.SetErrorf("column %s of table %s has unsupported type %s", col.Name, tbl.Name, col.Type)
+				break
+			}
+			if optStr := defaultOpt(col.Default); len(optStr) > 0 {
+				tagStr += ",default=" + optStr
+			}
+			idx, indexed := idxMap[col.Name]
+			if indexed && idx.Unique {
+				tagStr += ",unique"
+			}
+			fmt.Fprintf(&buf, "%s %s `ql:\"%s\"%s`\n", fldStr, fldTypeStr, tagStr,
+				strIf(indexed && !idx.Unique, ` ql_index:"*"`, ""))
+		}
+		buf.WriteString("}\n")
+	}
+	db.Close()
+	if db.err == nil {
+		var src []byte
+		if src, db.err = format.Source(buf.Bytes()); db.err == nil {
+			_, db.err = w.Write(bytes.TrimLeft(src, "\n"))
+		}
+	}
+	return db.err
+}
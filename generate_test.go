@@ -0,0 +1,54 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+	"os"
+)
+
+// This example demonstrates the generation of record types for the tables of
+// an existing database.
+func ExampleGenerateStructs() {
+	db := qlm.DbCreate("data/example.ql")
+	db.TransactBegin()
+	db.Exec(`CREATE TABLE cust_order (cust_name string, Total float64, placed time,
+		Status string DEFAULT "open", Ref string);`)
+	db.Exec("CREATE INDEX orderID ON cust_order (id());")
+	db.Exec("CREATE INDEX orderName ON cust_order (cust_name);")
+	db.Exec("CREATE UNIQUE INDEX orderRef ON cust_order (Ref);")
+	db.TransactCommit()
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	err := qlm.GenerateStructs("data/example.ql", os.Stdout)
+	if err != nil {
+		fmt.Println(err)
+	}
+	// Output:
+	// // CustOrderType holds a record of the cust_order table.
+	// type CustOrderType struct {
+	// 	ID       int64     `ql_table:"cust_order" ql_index:"*"`
+	// 	CustName string    `ql:"cust_name" ql_index:"*"`
+	// 	Total    float64   `ql:"*"`
+	// 	Placed   time.Time `ql:"placed"`
+	// 	Status   string    `ql:"*,default=open"`
+	// 	Ref      string    `ql:"*,unique"`
+	// }
+}
@@ -0,0 +1,242 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// earthRadius is the mean radius of the earth in meters
+const earthRadius = 6371008.8
+
+const geohashDigits = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// BoxType describes a region bounded by lines of latitude and longitude, in
+// degrees. If West is greater than East, the box spans the antimeridian.
+type BoxType struct {
+	South, West, North, East float64
+}
+
+// geoSet records in dsc the geospatial options of the column sqlStr. The
+// "lat" and "lon" options identify float64 fields that hold the latitude and
+// longitude of a record. The "geohash" option identifies a string field that
+// qlm maintains with the geohash of the location; its optional value is the
+// precision in characters, nine by default.
+func (db *DbType) geoSet(dsc *qlDscType, sf reflect.StructField, sqlStr string, optMap map[string]string) {
+	_, lat := optMap["lat"]
+	_, lon := optMap["lon"]
+	hashVal, hash := optMap["geohash"]
+	switch {
+	case (lat || lon) && sf.Type.Kind() != reflect.Float64:
+		db.SetErrorf("lat and lon options require field %s to be of type float64", sf.Name)
+	case lat:
+		dsc.geo.latStr, dsc.geo.latSf = sqlStr, sf
+	case lon:
+		dsc.geo.lonStr, dsc.geo.lonSf = sqlStr, sf
+	case hash && sf.Type.Kind() != reflect.String:
+		db.SetErrorf("geohash option requires field %s to be of type string", sf.Name)
+	case hash:
+		dsc.geo.hashStr, dsc.geo.hashSf, dsc.geo.hashLen = sqlStr, sf, 9
+		if len(hashVal) > 0 {
+			var err error
+			dsc.geo.hashLen, err = strconv.Atoi(hashVal)
+			if err != nil || dsc.geo.hashLen < 1 || dsc.geo.hashLen > 12 {
+				db.SetErrorf("invalid geohash precision for field %s", sf.Name)
+			}
+		}
+	}
+}
+
+// geoLocation returns the latitude and longitude of the record recVl.
+func geoLocation(dsc qlDscType, recVl reflect.Value) (lat, lon float64) {
+	vl := valueList(recVl, []reflect.StructField{dsc.geo.latSf, dsc.geo.lonSf})
+	return vl[0].Float(), vl[1].Float()
+}
+
+// geoAssign sets the geohash field of the record recVl if one is maintained.
+func (db *DbType) geoAssign(dsc qlDscType, recVl reflect.Value) {
+	if len(dsc.geo.hashStr) > 0 {
+		lat, lon := geoLocation(dsc, recVl)
+		valueList(recVl, []reflect.StructField{dsc.geo.hashSf})[0].SetString(
+			Geohash(lat, lon, dsc.geo.hashLen))
+	}
+}
+
+// Geohash returns the geohash of the specified location with the specified
+// number of characters. Locations that are near each other generally share a
+// common geohash prefix.
+func Geohash(lat, lon float64, length int) string {
+	latLo, latHi := -90.0, 90.0
+	lonLo, lonHi := -180.0, 180.0
+	buf := make([]byte, 0, length)
+	var bits, ch int
+	even := true
+	for len(buf) < length {
+		if even {
+			mid := (lonLo + lonHi) / 2
+			ch <<= 1
+			if lon >= mid {
+				ch |= 1
+				lonLo = mid
+			} else {
+				lonHi = mid
+			}
+		} else {
+			mid := (latLo + latHi) / 2
+			ch <<= 1
+			if lat >= mid {
+				ch |= 1
+				latLo = mid
+			} else {
+				latHi = mid
+			}
+		}
+		even = !even
+		bits++
+		if bits == 5 {
+			buf = append(buf, geohashDigits[ch])
+			bits, ch = 0, 0
+		}
+	}
+	return string(buf)
+}
+
+// Distance returns the great-circle distance in meters between two locations
+// specified in degrees.
+func Distance(latA, lonA, latB, lonB float64) float64 {
+	rad := math.Pi / 180
+	dLat := (latB - latA) * rad
+	dLon := (lonB - lonA) * rad
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(latA*rad)*math.Cos(latB*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// geoDsc returns the descriptor of the record type of slicePtr after
+// verifying that slicePtr points to a slice and that the type has location
+// fields. fncStr names the calling function in error messages.
+func (db *DbType) geoDsc(slicePtr interface{}, fncStr string) (dsc qlDscType) {
+	if db.slicePtrCheck(slicePtr, fncStr); db.err != nil {
+		return
+	}
+	dsc = db.dscFromType(recTypeOf(slicePtr))
+	if db.err == nil && len(dsc.geo.latStr) == 0 {
+		db.SetErrorf("record type %v has no fields with lat and lon options", dsc.recTp)
+	}
+	return
+}
+
+// RetrieveWithin appends to the slice pointed to by slicePtr the records
+// located within box that satisfy tailStr and its parameters. The location of
+// a record is specified by float64 fields that have the "lat" and "lon"
+// options in their "ql" tags, for example
+//
+//	Lat float64 `ql:"*,lat" ql_index:"*"`
+//	Lon float64 `ql:"*,lon"`
+//
+// The test is expressed as ranges so that an index on either column, if
+// present, can be used.
+func (db *DbType) RetrieveWithin(slicePtr interface{}, box BoxType, tailStr string, prms ...interface{}) {
+	if db.err != nil {
+		return
+	}
+	dsc := db.geoDsc(slicePtr, "RetrieveWithin")
+	if db.err == nil {
+		n := len(prms)
+		latStr, lonStr := dsc.geo.latStr, dsc.geo.lonStr
+		opStr := "&&"
+		if box.West > box.East {
+			opStr = "||"
+		}
+		condStr := fmt.Sprintf("%s >= ?%d && %s <= ?%d && (%s >= ?%d %s %s <= ?%d)",
+			latStr, n+1, latStr, n+2, lonStr, n+3, opStr, lonStr, n+4)
+		db.Retrieve(slicePtr, whereAnd(tailStr, condStr),
+			append(prms, box.South, box.North, box.West, box.East)...)
+	}
+}
+
+// BoxAround returns the smallest box that contains the circle of the specified
+// radius, in meters, centered on the specified location.
+func BoxAround(lat, lon, radius float64) (box BoxType) {
+	dLat := radius / earthRadius * 180 / math.Pi
+	box.South = math.Max(-90, lat-dLat)
+	box.North = math.Min(90, lat+dLat)
+	cos := math.Cos(lat * math.Pi / 180)
+	if cos < 1e-9 || box.South == -90 || box.North == 90 {
+		box.West, box.East = -180, 180
+	} else {
+		dLon := dLat / cos
+		if dLon >= 180 {
+			box.West, box.East = -180, 180
+		} else {
+			box.West = math.Mod(lon-dLon+540, 360) - 180
+			box.East = math.Mod(lon+dLon+540, 360) - 180
+		}
+	}
+	return
+}
+
+// RetrieveNear appends to the slice pointed to by slicePtr up to limit
+// records located within radius meters of the specified location that satisfy
+// tailStr and its parameters. Records are appended in order of increasing
+// distance. A value of zero for limit places no limit on the number of
+// records. The database is used to exclude records outside of the box that
+// encloses the circle; the remaining candidates are measured by the
+// application.
+func (db *DbType) RetrieveNear(slicePtr interface{}, lat, lon, radius float64, limit int,
+	tailStr string, prms ...interface{}) {
+	if db.err != nil {
+		return
+	}
+	dsc := db.geoDsc(slicePtr, "RetrieveNear")
+	slicePtrVl := reflect.ValueOf(slicePtr)
+	if db.err == nil {
+		candPtrVl := reflect.New(slicePtrVl.Elem().Type())
+		db.RetrieveWithin(candPtrVl.Interface(), BoxAround(lat, lon, radius), tailStr, prms...)
+		if db.err == nil {
+			type distType struct {
+				val   float64
+				recVl reflect.Value
+			}
+			var distList []distType
+			candVl := candPtrVl.Elem()
+			for j := 0; j < candVl.Len(); j++ {
+				recVl := candVl.Index(j)
+				recLat, recLon := geoLocation(dsc, recVl)
+				val := Distance(lat, lon, recLat, recLon)
+				if val <= radius {
+					distList = append(distList, distType{val, recVl})
+				}
+			}
+			sort.SliceStable(distList, func(a, b int) bool {
+				return distList[a].val < distList[b].val
+			})
+			if limit > 0 && len(distList) > limit {
+				distList = distList[:limit]
+			}
+			sliceVl := slicePtrVl.Elem()
+			for _, dist := range distList {
+				sliceVl = reflect.Append(sliceVl, dist.recVl)
+			}
+			slicePtrVl.Elem().Set(sliceVl)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates location-based retrieval. The "lat" and "lon"
+// options identify the location fields, and the "geohash" option causes qlm
+// to maintain a geohash of the location with the specified precision.
+func ExampleDbType_RetrieveNear() {
+	type siteType struct {
+		ID   int64   `ql_table:"site"`
+		Name string  `ql:"*"`
+		Lat  float64 `ql:"*,lat" ql_index:"*"`
+		Lon  float64 `ql:"*,lon"`
+		Hash string  `ql:"*,geohash=6"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&siteType{})
+	db.Insert([]siteType{
+		{Name: "Louvre", Lat: 48.8606, Lon: 2.3376},
+		{Name: "Eiffel Tower", Lat: 48.8584, Lon: 2.2945},
+		{Name: "Notre-Dame", Lat: 48.8530, Lon: 2.3499},
+		{Name: "Versailles", Lat: 48.8049, Lon: 2.1204},
+		{Name: "Big Ben", Lat: 51.5007, Lon: -0.1246},
+	})
+	var list []siteType
+	db.RetrieveNear(&list, 48.8566, 2.3522, 5000, 2, "")
+	for _, s := range list {
+		fmt.Println(s.Name, s.Hash)
+	}
+	list = nil
+	db.RetrieveWithin(&list, qlm.BoxType{South: 48, West: -1, North: 52, East: 2.2}, "ORDER BY Name")
+	for _, s := range list {
+		fmt.Println(s.Name)
+	}
+	fmt.Printf("%.0f km\n", qlm.Distance(48.8566, 2.3522, 51.5007, -0.1246)/1000)
+	db.RetrieveNear(list, 48.8566, 2.3522, 5000, 2, "")
+	fmt.Println(db.Error())
+	db.ClearError()
+	db.RetrieveWithin(nil, qlm.BoxType{South: 48, West: -1, North: 52, East: 2.2}, "")
+	fmt.Println(db.Error())
+	db.ClearError()
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// Notre-Dame u09tvm
+	// Louvre u09tvn
+	// Big Ben
+	// Versailles
+	// 343 km
+	// function RetrieveNear expecting pointer to slice, got []qlm_test.siteType
+	// function RetrieveWithin expecting pointer to slice, got <nil>
+}
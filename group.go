@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RetrieveGroup selects summary rows from the table of the type pointed to by
+// recPtr and appends them to the slice pointed to by slicePtr. The elements
+// of this slice are result structures whose "ql" tags specify the columns
+// and aggregate expressions to select, for example
+//
+//	type deptType struct {
+//		Dept  string  `ql:"*"`
+//		Count int64   `ql:"count(*)"`
+//		Total float64 `ql:"sum(Amount)"`
+//	}
+//	db.RetrieveGroup(&list, &saleType{}, "WHERE Year == ?1 GROUP BY Dept ORDER BY Dept", year)
+//
+// tailStr typically includes a GROUP BY clause; without one, a single row
+// summarizing all matching records is appended. A column that appears in an
+// ORDER BY clause must be among the selected expressions. Records that have
+// been deleted from a table whose type embeds SoftModel are excluded.
+func (db *DbType) RetrieveGroup(slicePtr interface{}, recPtr interface{}, tailStr string,
+	prms ...interface{}) {
+	if db.err != nil {
+		return
+	}
+	resTp := db.slicePtrCheck(slicePtr, "RetrieveGroup")
+	dsc := db.dscFromPtr(recPtr)
+	if db.err != nil {
+		return
+	}
+	exprList, sfList, _ := db.resultFields(resTp)
+	if db.err == nil {
+		if len(dsc.soft.nameStr) > 0 {
+			tailStr = whereAnd(tailStr, dsc.soft.nameStr+" IS NULL")
+		}
+		cmdStr := fmt.Sprintf("SELECT %s FROM %s%s;", strings.Join(exprList, ", "),
+			dsc.fromStr(), prePad(tailStr))
+		db.retrieveResult(slicePtr, sfList, 0, nil, cmdStr, prms...)
+	}
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the retrieval of grouped summaries.
+func ExampleDbType_RetrieveGroup() {
+	type saleType struct {
+		ID     int64   `ql_table:"sale"`
+		Dept   string  `ql:"*"`
+		Amount float64 `ql:"*"`
+	}
+	type deptType struct {
+		Dept  string  `ql:"*"`
+		Count int64   `ql:"count(*)"`
+		Total float64 `ql:"sum(Amount)"`
+		Max   float64 `ql:"max(Amount)"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&saleType{})
+	db.Insert([]saleType{{0, "tools", 12.5}, {0, "garden", 40}, {0, "tools", 7.5},
+		{0, "paint", 22}, {0, "garden", 15}, {0, "tools", 3}})
+	var list []deptType
+	db.RetrieveGroup(&list, &saleType{}, "WHERE Amount > ?1 GROUP BY Dept ORDER BY Dept", 5.0)
+	for _, rec := range list {
+		fmt.Printf("%-6s %d %5.2f %5.2f\n", rec.Dept, rec.Count, rec.Total, rec.Max)
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// garden 2 55.00 40.00
+	// paint  1 22.00 22.00
+	// tools  2 20.00 12.50
+}
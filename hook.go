@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"reflect"
+)
+
+// BeforeInserter is implemented by a record type that needs to prepare or
+// validate a record before Insert() stores it. BeforeInsert() is called with
+// a pointer to the record before qlm assigns timestamps, defaults and other
+// maintained fields. A non-nil return value becomes the qlm error and causes
+// the insertion of the entire slice to be rolled back.
+type BeforeInserter interface {
+	BeforeInsert() error
+}
+
+// AfterInserter is implemented by a record type that needs to act on a
+// record after Insert() stores it and assigns its ID field. A non-nil return
+// value becomes the qlm error and causes the insertion of the entire slice to
+// be rolled back.
+type AfterInserter interface {
+	AfterInsert() error
+}
+
+// BeforeUpdater is implemented by a record type that needs to prepare or
+// validate a record before Update() stores it. A non-nil return value becomes
+// the qlm error and prevents the update.
+type BeforeUpdater interface {
+	BeforeUpdate() error
+}
+
+// AfterRetriever is implemented by a record type that needs to complete a
+// record after Retrieve() loads it, for example by computing fields that are
+// not stored. A non-nil return value becomes the qlm error and stops the
+// retrieval.
+type AfterRetriever interface {
+	AfterRetrieve() error
+}
+
+var (
+	beforeInserterType = reflect.TypeOf((*BeforeInserter)(nil)).Elem()
+	afterInserterType  = reflect.TypeOf((*AfterInserter)(nil)).Elem()
+	beforeUpdaterType  = reflect.TypeOf((*BeforeUpdater)(nil)).Elem()
+	afterRetrieverType = reflect.TypeOf((*AfterRetriever)(nil)).Elem()
+)
+
+// hookSet records in dsc which of the hook interfaces are implemented by a
+// pointer to the record type.
+func (dsc *qlDscType) hookSet() {
+	ptrTp := reflect.PtrTo(dsc.recTp)
+	dsc.hook.beforeInsert = ptrTp.Implements(beforeInserterType)
+	dsc.hook.afterInsert = ptrTp.Implements(afterInserterType)
+	dsc.hook.beforeUpdate = ptrTp.Implements(beforeUpdaterType)
+	dsc.hook.afterRetrieve = ptrTp.Implements(afterRetrieverType)
+}
+
+// hookBeforeInsert calls the BeforeInsert() method of the record recVl if it
+// has one.
+func (db *DbType) hookBeforeInsert(dsc qlDscType, recVl reflect.Value) {
+	if dsc.hook.beforeInsert && db.err == nil {
+		db.SetError(recVl.Addr().Interface().(BeforeInserter).BeforeInsert())
+	}
+}
+
+// hookAfterInsert calls the AfterInsert() method of the record recVl if it
+// has one.
+func (db *DbType) hookAfterInsert(dsc qlDscType, recVl reflect.Value) {
+	if dsc.hook.afterInsert && db.err == nil {
+		db.SetError(recVl.Addr().Interface().(AfterInserter).AfterInsert())
+	}
+}
+
+// hookBeforeUpdate calls the BeforeUpdate() method of the record recVl if it
+// has one.
+func (db *DbType) hookBeforeUpdate(dsc qlDscType, recVl reflect.Value) {
+	if dsc.hook.beforeUpdate && db.err == nil {
+		db.SetError(recVl.Addr().Interface().(BeforeUpdater).BeforeUpdate())
+	}
+}
+
+// hookAfterRetrieve returns the result of the AfterRetrieve() method of the
+// record recVl if it has one.
+func (dsc qlDscType) hookAfterRetrieve(recVl reflect.Value) (err error) {
+	if dsc.hook.afterRetrieve {
+		err = recVl.Addr().Interface().(AfterRetriever).AfterRetrieve()
+	}
+	return
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jung-kurt/qlm"
+	"strings"
+)
+
+type contactType struct {
+	ID      int64  `ql_table:"contact"`
+	Email   string `ql:"*"`
+	Display string
+}
+
+// BeforeInsert satisfies qlm.BeforeInserter.
+func (c *contactType) BeforeInsert() error {
+	c.Email = strings.ToLower(strings.TrimSpace(c.Email))
+	if !strings.Contains(c.Email, "@") {
+		return errors.New("invalid email address " + c.Email)
+	}
+	return nil
+}
+
+// BeforeUpdate satisfies qlm.BeforeUpdater.
+func (c *contactType) BeforeUpdate() error {
+	return c.BeforeInsert()
+}
+
+// AfterRetrieve satisfies qlm.AfterRetriever.
+func (c *contactType) AfterRetrieve() error {
+	c.Display = "<" + c.Email + ">"
+	return nil
+}
+
+// This example demonstrates record methods that are called by Insert,
+// Update and Retrieve.
+func ExampleBeforeInserter() {
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&contactType{})
+	db.Insert([]contactType{{Email: " Ann@Example.COM"}, {Email: "bob@example.com"}})
+	db.Insert([]contactType{{Email: "cy@example.com"}, {Email: "dee"}})
+	fmt.Println(db.Error())
+	db.ClearError()
+	var list []contactType
+	db.Retrieve(&list, "ORDER BY Email")
+	list[1].Email = "Bobby@Example.com"
+	db.Update(&list[1], "Email")
+	list = nil
+	db.Retrieve(&list, "ORDER BY Email")
+	for _, c := range list {
+		fmt.Println(c.Display)
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// invalid email address dee
+	// <ann@example.com>
+	// <bobby@example.com>
+}
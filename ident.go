@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"strings"
+)
+
+// keywordMap contains, in lower case, the words that ql reserves. ql matches
+// keywords without regard to case and has no syntax for quoting identifiers,
+// so none of these words can be used as a table or column name.
+var keywordMap = map[string]bool{
+	"add": true, "alter": true, "and": true, "as": true, "asc": true,
+	"begin": true, "between": true, "bigint": true, "bigrat": true,
+	"blob": true, "bool": true, "by": true, "byte": true, "column": true,
+	"commit": true, "complex128": true, "complex64": true, "create": true,
+	"default": true, "delete": true, "desc": true, "distinct": true,
+	"drop": true, "duration": true, "exists": true, "explain": true,
+	"false": true, "float": true, "float32": true, "float64": true,
+	"from": true, "full": true, "group": true, "if": true, "in": true,
+	"index": true, "insert": true, "int": true, "int16": true, "int32": true,
+	"int64": true, "int8": true, "into": true, "is": true, "join": true,
+	"left": true, "like": true, "limit": true, "not": true, "null": true,
+	"offset": true, "on": true, "or": true, "order": true, "outer": true,
+	"right": true, "rollback": true, "rune": true, "select": true,
+	"set": true, "string": true, "table": true, "time": true,
+	"transaction": true, "true": true, "truncate": true, "uint": true,
+	"uint16": true, "uint32": true, "uint64": true, "uint8": true,
+	"unique": true, "update": true, "values": true, "where": true,
+}
+
+// identCheck sets an error if nameStr, the name of a table or column as
+// indicated by kindStr, cannot be used unaltered in a ql statement. ql
+// identifiers begin with a letter or underscore followed by letters, digits
+// and underscores, and may not be keywords. Because ql cannot quote an
+// identifier, an unusable name is reported when the record type is first
+// described rather than as a parse error in the first statement that uses it.
+// srcStr identifies the origin of the name in the error message.
+func (db *DbType) identCheck(kindStr, nameStr, srcStr string) {
+	if db.err != nil {
+		return
+	}
+	valid := len(nameStr) > 0
+	for j := 0; j < len(nameStr) && valid; j++ {
+		ch := nameStr[j]
+		valid = ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' ||
+			j > 0 && ch >= '0' && ch <= '9'
+	}
+	switch {
+	case !valid:
+		db.SetErrorf("%s name %q in %s is not a valid ql identifier", kindStr, nameStr, srcStr)
+	case keywordMap[strings.ToLower(nameStr)]:
+		db.SetErrorf("%s name %s in %s is a ql keyword and cannot be used", kindStr, nameStr, srcStr)
+	}
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the checking of table and column names. ql has no
+// way to quote identifiers, so a field whose name is a ql keyword needs a
+// different column name in its tag.
+func ExampleDbType_identifiers() {
+	type badColType struct {
+		ID    int64  `ql_table:"sale"`
+		Order string `ql:"*"`
+	}
+	type badTblType struct {
+		ID  int64  `ql_table:"Select"`
+		Str string `ql:"*"`
+	}
+	type badNameType struct {
+		ID  int64  `ql_table:"sale"`
+		Str string `ql:"unit-price"`
+	}
+	type goodType struct {
+		ID    int64  `ql_table:"sale"`
+		Order string `ql:"OrderNum"`
+		Time  int64  `ql:"Tm"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	for _, recPtr := range []interface{}{&badColType{}, &badTblType{}, &badNameType{}, &goodType{}} {
+		db.TableCreate(recPtr)
+		if db.Err() {
+			fmt.Println(db.Error())
+			db.ClearError()
+		}
+	}
+	db.Insert([]goodType{{Order: "A-100", Time: 42}})
+	var list []goodType
+	db.Retrieve(&list, "WHERE OrderNum == ?1", "A-100")
+	fmt.Println(len(list), list[0].Order, list[0].Time)
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// column name Order in ql tag of field Order is a ql keyword and cannot be used
+	// table name Select in ql_table tag of field ID is a ql keyword and cannot be used
+	// column name "unit-price" in ql tag of field Str is not a valid ql identifier
+	// 1 A-100 42
+}
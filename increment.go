@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Increment adds delta to the integer column fldStr of the records that
+// satisfy tailStr and its parameters, and returns the new value of the column
+// in the first such record. The update and the subsequent read take place
+// within a single transaction, so concurrent increments are not lost as they
+// can be when Retrieve() and Update() are used for the same purpose. fldStr
+// is the name used in the database, that is, the name identified with the
+// "ql" tag in the structure definition. For example,
+//
+//	hits := db.Increment(&pageType{}, "hits", 1, "WHERE id() == ?1", id)
+//
+// Zero is returned if no record satisfies the tail clause. If the record type
+// has a version field, the version of each updated record is incremented as
+// well, so that a copy retrieved earlier is reported as stale by Update().
+func (db *DbType) Increment(recPtr interface{}, fldStr string, delta int64,
+	tailStr string, prms ...interface{}) (val int64) {
+	if db.err != nil {
+		return
+	}
+	dsc := db.dscFromPtr(recPtr)
+	if !db.writable(dsc) {
+		return
+	}
+	sf, ok := dsc.nameMap[fldStr]
+	if !ok {
+		db.SetErrorf("field %s not found in table %s", fldStr, dsc.tblStr)
+		return
+	}
+	switch sf.Type.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		db.SetErrorf("function Increment requires integer field, got %v", sf.Type)
+		return
+	}
+	if len(dsc.soft.nameStr) > 0 {
+		tailStr = whereAnd(tailStr, dsc.soft.nameStr+" IS NULL")
+	}
+	// ql requires the operands of an expression to have the same type
+	deltaVal := reflect.ValueOf(delta).Convert(sf.Type).Interface()
+	db.TransactBegin()
+	if db.err == nil {
+		verStr := ""
+		if len(dsc.version.nameStr) > 0 && dsc.version.nameStr != fldStr {
+			verStr = fmt.Sprintf(", %s = %s + 1", dsc.version.nameStr, dsc.version.nameStr)
+		}
+		cmd := fmt.Sprintf("UPDATE %s %s = %s + ?%d%s%s;",
+			dsc.tblStr, fldStr, fldStr, len(prms)+1, verStr, prePad(tailStr))
+		_, _ = db.Exec(cmd, append(prms, deltaVal)...)
+		if db.tracked(dsc) || len(dsc.sum.nameStr) > 0 {
+			idList := db.idList(dsc, tailStr, prms...)
+			db.derivedRepair(dsc, idList)
+			db.journalIDs(dsc, ChangeUpdate, idList)
+		}
+		row := db.firstRow(fmt.Sprintf("SELECT %s FROM %s%s;",
+			fldStr, dsc.tblStr, prePad(tailStr)), prms...)
+		if db.err == nil && len(row) > 0 && row[0] != nil {
+			val = reflect.ValueOf(row[0]).Convert(reflect.TypeOf(val)).Int()
+		}
+	}
+	db.transactEnd(db.err == nil)
+	return
+}
@@ -0,0 +1,48 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates atomic increments of a counter column. The column
+// may have any integer type; the delta is converted as needed.
+func ExampleDbType_Increment() {
+	type pageType struct {
+		ID   int64  `ql_table:"page"`
+		Path string `ql:"*"`
+		Hits int32  `ql:"hits"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&pageType{})
+	list := []pageType{{0, "/index.html", 0}, {0, "/about.html", 0}}
+	db.Insert(list)
+	for j := 0; j < 3; j++ {
+		db.Increment(&pageType{}, "hits", 1, "WHERE id() == ?1", list[0].ID)
+	}
+	fmt.Println(db.Increment(&pageType{}, "hits", 10, "WHERE Path == ?1", "/index.html"))
+	fmt.Println(db.Increment(&pageType{}, "hits", -2, "WHERE Path == ?1", "/about.html"))
+	db.Increment(&pageType{}, "Path", 1, "")
+	fmt.Println(db.Error())
+	db.Close()
+	// Output:
+	// 13
+	// -2
+	// function Increment requires integer field, got string
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+// IndexInfoType describes an index of a table as recorded by ql.
+type IndexInfoType struct {
+	Name   string // Name of the index, for example "recName"
+	Column string // Indexed column, or "id()" for an index of record ids
+	Unique bool   // True if the index does not permit duplicate values
+}
+
+// TableInfoType describes a table of the database as recorded by ql.
+type TableInfoType struct {
+	Name    string          // Name of the table
+	Schema  string          // Statement that creates the table, including constraints
+	Indexes []IndexInfoType // Indexes of the table ordered by name
+}
+
+// ColumnInfoType describes a column of a table as recorded by ql.
+type ColumnInfoType struct {
+	Name       string // Name of the column
+	Type       string // ql type of the column, for example "int64"
+	Ordinal    int64  // Position of the column in the table, starting with 1
+	NotNull    bool   // True if the column has the NOT NULL constraint
+	Constraint string // Constraint expression, for example "Qty > 0"; empty if none
+	Default    string // Default expression, for example `"active"`; empty if none
+}
+
+// tableExists returns true if the table tblStr, possibly a system table,
+// exists.
+func (db *DbType) tableExists(tblStr string) (ok bool) {
+	row := db.firstRow("SELECT Name FROM __Table WHERE Name == ?1;", tblStr)
+	return db.err == nil && row != nil
+}
+
+// Tables returns a description of each table in the database, ordered by
+// name. ql's system tables, whose names begin with two underscores, are
+// excluded.
+func (db *DbType) Tables() (list []TableInfoType) {
+	if db.err != nil {
+		return
+	}
+	db.RawRetrieve(&list, "SELECT Name, Schema FROM __Table WHERE !hasPrefix(Name, \"__\") ORDER BY Name;")
+	var idxList []struct {
+		TableName  string
+		ColumnName string
+		Name       string
+		IsUnique   bool
+	}
+	db.RawRetrieve(&idxList, "SELECT TableName, ColumnName, Name, IsUnique FROM __Index ORDER BY Name;")
+	if db.err != nil {
+		return nil
+	}
+	for j := range list {
+		for _, idx := range idxList {
+			if idx.TableName == list[j].Name {
+				list[j].Indexes = append(list[j].Indexes, IndexInfoType{idx.Name, idx.ColumnName, idx.IsUnique})
+			}
+		}
+	}
+	return
+}
+
+// Columns returns a description of each column of the table tblStr in the
+// order in which the columns are defined. An error is set if the table does
+// not exist.
+func (db *DbType) Columns(tblStr string) (list []ColumnInfoType) {
+	if db.err != nil {
+		return
+	}
+	if !db.tableExists(tblStr) {
+		db.SetErrorf("table %s does not exist", tblStr)
+		return
+	}
+	db.RawRetrieve(&list, "SELECT Name, Type, Ordinal FROM __Column WHERE TableName == ?1 ORDER BY Ordinal;", tblStr)
+	// __Column2 is created by ql only when a constraint or default is first
+	// defined
+	if db.err == nil && db.tableExists("__Column2") {
+		var conList []struct {
+			Name           string
+			NotNull        bool
+			ConstraintExpr string
+			DefaultExpr    string
+		}
+		db.RawRetrieve(&conList, "SELECT Name, NotNull, ConstraintExpr, DefaultExpr FROM __Column2 WHERE TableName == ?1;", tblStr)
+		for _, con := range conList {
+			for j := range list {
+				if list[j].Name == con.Name {
+					list[j].NotNull = con.NotNull
+					list[j].Constraint = con.ConstraintExpr
+					list[j].Default = con.DefaultExpr
+				}
+			}
+		}
+	}
+	if db.err != nil {
+		list = nil
+	}
+	return
+}
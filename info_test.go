@@ -0,0 +1,58 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the listing of tables and their columns.
+func ExampleDbType_Tables() {
+	type recType struct {
+		ID     int64  `ql_table:"rec" ql_index:"*"`
+		Name   string `ql:"*,unique"`
+		Status string `ql:"*,default=active"`
+		Qty    int64  `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&recType{})
+	for _, tbl := range db.Tables() {
+		fmt.Println(tbl.Name)
+		for _, idx := range tbl.Indexes {
+			fmt.Printf("  index %s on %s, unique %v\n", idx.Name, idx.Column, idx.Unique)
+		}
+		for _, col := range db.Columns(tbl.Name) {
+			fmt.Printf("  %d %s %s [%s]\n", col.Ordinal, col.Name, col.Type, col.Default)
+		}
+	}
+	db.Columns("missing")
+	fmt.Println(db.Error())
+	db.ClearError()
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// rec
+	//   index recID on id(), unique false
+	//   index recName on Name, unique true
+	//   1 Name string []
+	//   2 Status string ["active"]
+	//   3 Qty int64 []
+	// table missing does not exist
+}
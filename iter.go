@@ -0,0 +1,119 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"reflect"
+)
+
+// iterBatchSize is the number of records that IterType loads with each query.
+const iterBatchSize = 100
+
+// IterType steps through the records selected by Iter() one at a time.
+// Errors are reported by means of the qlm instance from which the iterator
+// was obtained.
+type IterType struct {
+	db      *DbType
+	dsc     qlDscType
+	tailStr string        // Tail clause that selects the next batch
+	prms    []interface{} // Parameters of tailStr, excluding the last identifier
+	batchVl reflect.Value // Records of the current batch
+	pos     int           // Position of the next record in batchVl
+	lastID  int64         // Identifier of the last record in batchVl
+	more    bool          // True if another batch may follow batchVl
+}
+
+// Iter returns an iterator over the records of the type pointed to by recPtr
+// that satisfy tailStr and its parameters. tailStr may contain only a WHERE
+// clause; records are delivered in order of their identifiers. For example,
+//
+//	it := db.Iter(&recType{}, "WHERE Qty > ?1", 10)
+//	defer it.Close()
+//	var rec recType
+//	for it.Next(&rec) {
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// Records are loaded in small batches, each with a separate query that
+// resumes after the last identifier of the previous batch, so memory use does
+// not depend on the number of records and the loop may stop at any point.
+// Records that are inserted or changed during the iteration are visited if
+// they satisfy tailStr and follow the current record.
+func (db *DbType) Iter(recPtr interface{}, tailStr string, prms ...interface{}) (it *IterType) {
+	it = &IterType{db: db}
+	if db.err != nil {
+		return
+	}
+	it.dsc = db.dscFromPtr(recPtr)
+	it.tailStr = db.batchTail("Iter", tailStr, len(prms), iterBatchSize)
+	if db.err == nil {
+		it.prms = prms[:len(prms):len(prms)]
+		it.batchVl = reflect.MakeSlice(reflect.SliceOf(it.dsc.recTp), 0, 0)
+		it.more = true
+	}
+	return
+}
+
+// Next assigns the next record to the record pointed to by recPtr and returns
+// true. False is returned when no records remain, when the iterator has been
+// closed, or when an error has occurred; call Err() to distinguish the last
+// case.
+func (it *IterType) Next(recPtr interface{}) bool {
+	db := it.db
+	if db.err != nil || !it.batchVl.IsValid() {
+		return false
+	}
+	recVl := reflect.ValueOf(recPtr)
+	if recVl.Kind() != reflect.Ptr || recVl.Elem().Type() != it.dsc.recTp {
+		db.SetErrorf("function Next expecting pointer to %v, got %v", it.dsc.recTp, recVl.Type())
+		return false
+	}
+	if it.pos >= it.batchVl.Len() {
+		if !it.more {
+			return false
+		}
+		slicePtrVl := reflect.New(it.batchVl.Type())
+		db.Retrieve(slicePtrVl.Interface(), it.tailStr, append(it.prms, it.lastID)...)
+		it.batchVl, it.pos = slicePtrVl.Elem(), 0
+		count := it.batchVl.Len()
+		it.more = count == iterBatchSize
+		if db.err != nil || count == 0 {
+			return false
+		}
+		lastVl := it.batchVl.Index(count - 1)
+		it.lastID = fieldValue(lastVl, it.dsc.idSf).Int()
+	}
+	recVl.Elem().Set(it.batchVl.Index(it.pos))
+	it.pos++
+	return true
+}
+
+// Err returns the error of the qlm instance from which the iterator was
+// obtained; this will be nil if no error has occurred.
+func (it *IterType) Err() error {
+	return it.db.err
+}
+
+// Close releases the records held by the iterator. Subsequent calls to Next()
+// return false.
+func (it *IterType) Close() {
+	it.batchVl = reflect.Value{}
+	it.more = false
+}
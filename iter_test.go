@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the traversal of records with an iterator that
+// is abandoned once the desired record has been found.
+func ExampleDbType_Iter() {
+	type recType struct {
+		ID  int64 `ql_table:"rec"`
+		Num int64 `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&recType{})
+	var list []recType
+	for j := int64(1); j <= 250; j++ {
+		list = append(list, recType{Num: j * j})
+	}
+	db.Insert(list)
+	it := db.Iter(&recType{}, "WHERE Num%2 == ?1", int64(1))
+	var rec recType
+	var count int
+	for it.Next(&rec) {
+		count++
+		if rec.Num > 20000 {
+			break
+		}
+	}
+	it.Close()
+	fmt.Println(count, rec.Num, it.Next(&rec), it.Err())
+	it = db.Iter(&recType{}, "")
+	count = 0
+	for it.Next(&rec) {
+		count++
+	}
+	fmt.Println(count, rec.Num, it.Err())
+	it = db.Iter(&recType{}, "ORDER BY Num")
+	fmt.Println(it.Next(&rec), it.Err())
+	db.ClearError()
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// 72 20449 false <nil>
+	// 250 62500 <nil>
+	// false function Iter accepts only a WHERE clause, got ORDER BY Num
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// joinField returns the field of the composite structure type tp whose type
+// is recTp, adjusting the fields of the record described by dsc to be relative
+// to the composite structure. The select expressions of these fields are
+// qualified with the table name.
+func (db *DbType) joinField(tp reflect.Type, dsc qlDscType) (exprList []string, sfList []reflect.StructField) {
+	for j := 0; j < tp.NumField() && sfList == nil; j++ {
+		fld := tp.Field(j)
+		if fld.Type == dsc.recTp {
+			for k, nameStr := range dsc.sel.nameList {
+				switch {
+				case dsc.sel.typeStrList[k] == "expr":
+					continue // Column references in the expression would be ambiguous
+				case nameStr == "id()":
+					nameStr = fmt.Sprintf("id(%s)", dsc.tblStr)
+				default:
+					nameStr = dsc.tblStr + "." + nameStr
+				}
+				sf := dsc.sel.sfList[k]
+				sf.Offset += fld.Offset
+				sf.Index = append(append([]int{}, fld.Index...), sf.Index...)
+				exprList = append(exprList, nameStr)
+				sfList = append(sfList, sf)
+			}
+		}
+	}
+	if sfList == nil {
+		db.SetErrorf("join type %v has no field of type %v", tp, dsc.recTp)
+	}
+	return
+}
+
+// RetrieveJoin selects pairs of records from the tables of the types pointed
+// to by parentPtr and childPtr and appends them to the slice pointed to by
+// slicePtr. The elements of this slice are composite structures that have a
+// field of each record type, for example
+//
+//	type custOrderType struct {
+//		Cust  custType
+//		Order orderType
+//	}
+//	db.RetrieveJoin(&list, &custType{}, &orderType{}, "id(cust) == orders.CustID",
+//		"WHERE orders.Amt > ?1 ORDER BY orders.Amt", 20.0)
+//
+// A pair is selected for each combination of parent and child records that
+// satisfies onStr and the WHERE clause of tailStr. Columns in both clauses
+// are qualified with their table names, and the identifier of a record is
+// referred to with id(table). Records that have been deleted from a table
+// whose type embeds SoftModel are excluded. The parent and child types must
+// differ.
+func (db *DbType) RetrieveJoin(slicePtr interface{}, parentPtr, childPtr interface{}, onStr string,
+	tailStr string, prms ...interface{}) {
+	if db.err != nil {
+		return
+	}
+	resTp := db.slicePtrCheck(slicePtr, "RetrieveJoin")
+	parent := db.dscFromPtr(parentPtr)
+	child := db.dscFromPtr(childPtr)
+	if db.err == nil {
+		if resTp.Kind() != reflect.Struct {
+			db.SetErrorf("function RetrieveJoin expecting slice of structures, got slice of %v", resTp.Kind())
+		} else if parent.recTp == child.recTp {
+			db.SetErrorf("function RetrieveJoin requires distinct parent and child types")
+		}
+	}
+	if db.err != nil {
+		return
+	}
+	exprList, sfList := db.joinField(resTp, parent)
+	childExprList, childSfList := db.joinField(resTp, child)
+	if db.err != nil {
+		return
+	}
+	exprList = append(exprList, childExprList...)
+	sfList = append(sfList, childSfList...)
+	if len(onStr) > 0 {
+		tailStr = whereAnd(tailStr, onStr)
+	}
+	for _, dsc := range []qlDscType{child, parent} {
+		if len(dsc.soft.nameStr) > 0 {
+			tailStr = whereAnd(tailStr, dsc.tblStr+"."+dsc.soft.nameStr+" IS NULL")
+		}
+	}
+	cmdStr := fmt.Sprintf("SELECT %s FROM %s, %s%s;", strings.Join(exprList, ", "),
+		parent.fromStr(), child.fromStr(), prePad(tailStr))
+	db.retrieveResult(slicePtr, sfList, 0, nil, cmdStr, prms...)
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the retrieval of joined parent and child records.
+func ExampleDbType_RetrieveJoin() {
+	type custType struct {
+		ID   int64  `ql_table:"cust"`
+		Name string `ql:"*"`
+	}
+	type orderType struct {
+		qlm.SoftModel `ql_table:"orders"`
+		CustID        int64   `ql:"*"`
+		Amt           float64 `ql:"*"`
+	}
+	type custOrderType struct {
+		Cust  custType
+		Order orderType
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&custType{})
+	db.TableCreate(&orderType{})
+	custList := []custType{{0, "ann"}, {0, "bob"}}
+	db.Insert(custList)
+	db.Insert([]orderType{{CustID: custList[0].ID, Amt: 40}, {CustID: custList[1].ID, Amt: 15},
+		{CustID: custList[0].ID, Amt: 85}, {CustID: custList[1].ID, Amt: 60}})
+	db.Delete(&orderType{}, "WHERE Amt == ?1", 60.0)
+	var list []custOrderType
+	db.RetrieveJoin(&list, &custType{}, &orderType{}, "id(cust) == orders.CustID",
+		"WHERE orders.Amt > ?1 ORDER BY orders.Amt", 10.0)
+	for _, rec := range list {
+		fmt.Printf("%-3s %6.2f %v %v\n", rec.Cust.Name, rec.Order.Amt,
+			rec.Cust.ID == rec.Order.CustID, rec.Order.CreatedAt.IsZero())
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// bob  15.00 true false
+	// ann  40.00 true false
+	// ann  85.00 true false
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Operations recorded in the change journal
+const (
+	ChangeInsert   = "insert"
+	ChangeUpdate   = "update"
+	ChangeDelete   = "delete"
+	ChangeTruncate = "truncate"
+)
+
+// ChangeType is an entry of the change journal. The entries of a journal are
+// ordered by Seq, which increases with each change that is committed.
+type ChangeType struct {
+	Seq   int64     `ql_table:"qlm_journal"`
+	Tm    time.Time `ql:"*"`
+	Table string    `ql:"Tbl"`
+	Op    string    `ql:"*"` // ChangeInsert, ChangeUpdate, ChangeDelete or ChangeTruncate
+	RecID int64     `ql:"*"` // Identifier of the affected record; zero for ChangeTruncate
+	Data  string    `ql:"*"` // JSON object of the record's columns after an insertion or update, keyed by column name
+}
+
+// Decode assigns the record that is encoded in the change entry to the
+// structure pointed to by recPtr. The structure must be of the record type
+// associated with the entry's table. The ID field of the record is assigned
+// the entry's RecID.
+func (ch ChangeType) Decode(recPtr interface{}) (err error) {
+	db := new(DbType)
+	db.init()
+	dsc := db.dscFromPtr(recPtr)
+	if db.err == nil {
+		recVl := reflect.ValueOf(recPtr).Elem()
+		if len(ch.Data) > 0 {
+			db.err = colDataDecode(dsc, recVl, ch.Data)
+		}
+		if db.err == nil {
+			valueList(recVl, []reflect.StructField{dsc.idSf})[0].SetInt(ch.RecID)
+		}
+	}
+	return db.err
+}
+
+// Journal enables or disables the change journal. While the journal is
+// enabled, every record that is inserted, updated or deleted by qlm is
+// recorded in the table qlm_journal, in the same transaction as the change
+// itself, so the journal contains exactly the changes that have been
+// committed. The journal is the basis for replication; see ChangesSince and
+// Replicate. Changes made with Exec or ExecScript are not recorded.
+func (db *DbType) Journal(on bool) {
+	if db.err == nil {
+		if on {
+			db.TableEnsure(&ChangeType{})
+		}
+		db.journal = on && db.err == nil
+	}
+}
+
+// ChangesSince returns, in order, the journal entries whose sequence number
+// is greater than seq. Pass zero to retrieve the entire journal. At most limit
+// entries are returned unless limit is less than one.
+func (db *DbType) ChangesSince(seq int64, limit int) (list []ChangeType) {
+	if db.err == nil {
+		db.TableEnsure(&ChangeType{})
+		tailStr := "WHERE id() > ?1 ORDER BY id()"
+		if limit > 0 {
+			tailStr += " LIMIT ?2"
+		}
+		db.Retrieve(&list, tailStr, seq, int64(limit))
+	}
+	return
+}
+
+// journaled returns true if changes to the records described by dsc are to
+// be recorded in the journal.
+func (db *DbType) journaled(dsc qlDscType) bool {
+	return db.journal && db.err == nil && dsc.recTp != reflect.TypeOf(ChangeType{})
+}
+
+// journalRec records the operation opStr on the record recVl that has the
+// identifier id. This method must be called within a transaction.
+func (db *DbType) journalRec(dsc qlDscType, opStr string, id int64, recVl reflect.Value) {
+	ch := ChangeType{Tm: time.Now(), Table: dsc.tblStr, Op: opStr, RecID: id}
+	if recVl.IsValid() {
+		ch.Data = colDataEncode(dsc, recVl, dsc.insert.nameList)
+	}
+	if db.err == nil {
+		// The record is inserted directly rather than with Insert in order to
+		// bypass progress reporting and expiration
+		cdsc := db.dscFromType(reflect.TypeOf(ch))
+		if db.err == nil {
+			_, _ = db.Exec(fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s);", cdsc.tblStr,
+				cdsc.insert.nameStr, cdsc.insert.qmStr), valList(reflect.ValueOf(&ch).Elem(), cdsc.insert.sfList)...)
+		}
+	}
+}
+
+// journalIDs records the operation opStr on the records described by dsc
+// that have the identifiers in idList. The current content of the records is
+// recorded unless opStr is ChangeDelete. The operation is also queued for
+// subscribers and recorded in the audit trail; nameList, if not empty,
+// restricts the columns in the audit entry to those that were stored. This
+// method must be called within a transaction.
+func (db *DbType) journalIDs(dsc qlDscType, opStr string, idList []int64, nameList ...string) {
+	if !db.tracked(dsc) || len(idList) == 0 {
+		return
+	}
+	db.notifyQueue(dsc, opStr, idList...)
+	journal, audit := db.journaled(dsc), db.audited(dsc)
+	if !journal && !audit {
+		return
+	}
+	if opStr == ChangeDelete {
+		for _, id := range idList {
+			if journal {
+				db.journalRec(dsc, opStr, id, reflect.Value{})
+			}
+			if audit {
+				db.auditRec(dsc, opStr, id, reflect.Value{}, nil)
+			}
+		}
+		return
+	}
+	slicePtrVl := reflect.New(reflect.SliceOf(dsc.recTp))
+	condStr, prms := inCond("id()", idList, 0)
+	cmdStr := fmt.Sprintf("SELECT %s FROM %s WHERE %s ORDER BY id();",
+		dsc.sel.nameStr, dsc.tblStr, condStr)
+	db.retrieveResult(slicePtrVl.Interface(), dsc.sel.sfList, 0, nil, cmdStr, prms...)
+	sliceVl := slicePtrVl.Elem()
+	for j := 0; j < sliceVl.Len() && db.err == nil; j++ {
+		recVl := sliceVl.Index(j)
+		id := valueList(recVl, []reflect.StructField{dsc.idSf})[0].Int()
+		if journal {
+			db.journalRec(dsc, opStr, id, recVl)
+		}
+		if audit {
+			db.auditRec(dsc, opStr, id, recVl, nameList)
+		}
+	}
+}
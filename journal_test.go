@@ -0,0 +1,95 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+	"math"
+)
+
+// This example demonstrates the change journal.
+func ExampleDbType_ChangesSince() {
+	type noteType struct {
+		ID   int64  `ql_table:"note"`
+		Text string `ql:"*"`
+		Hits int32  `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&noteType{})
+	db.Journal(true)
+	list := []noteType{{0, "alpha", 0}, {0, "beta", 0}}
+	db.Insert(list)
+	list[0].Text = "ALPHA"
+	db.Update(&list[0], "Text")
+	db.Increment(&noteType{}, "Hits", 5, "WHERE Text == ?1", "beta")
+	db.Delete(&noteType{}, "WHERE id() == ?1", list[0].ID)
+	// Rolled back changes are not journaled
+	db.TransactBegin()
+	db.Insert([]noteType{{0, "gamma", 0}})
+	db.TransactRollback()
+	var rec noteType
+	for _, ch := range db.ChangesSince(0, 0) {
+		rec = noteType{}
+		err := ch.Decode(&rec)
+		if err == nil {
+			fmt.Printf("%-4s %s %d %-5s %d\n", ch.Table, ch.Op, rec.ID, rec.Text, rec.Hits)
+		} else {
+			fmt.Println(err)
+		}
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// note insert 1 alpha 0
+	// note insert 2 beta  0
+	// note update 1 ALPHA 0
+	// note update 2 beta  5
+	// note delete 1       0
+}
+
+// This example demonstrates that the journal records every column, including
+// complex and non-finite values and fields that are excluded from JSON.
+func ExampleChangeType_Decode() {
+	type sigType struct {
+		ID     int64      `ql_table:"sig"`
+		Z      complex128 `ql:"*"`
+		Gain   float64    `ql:"*"`
+		Secret string     `ql:"*" json:"-"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&sigType{})
+	db.Journal(true)
+	db.Insert([]sigType{{0, complex(1.5, math.Inf(-1)), math.NaN(), "s3"}})
+	fmt.Println(db.Error())
+	for _, ch := range db.ChangesSince(0, 0) {
+		var rec sigType
+		err := ch.Decode(&rec)
+		fmt.Println(ch.Data)
+		fmt.Println(rec.ID, rec.Z, rec.Gain, rec.Secret, err)
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// <nil>
+	// {"Gain":"NaN","Secret":"s3","Z":[1.5,"-Inf"]}
+	// 1 (1.5-Infi) NaN s3 <nil>
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+var blobType = reflect.TypeOf([]byte(nil))
+
+// jsonKindMap contains the kinds of field that can receive a JSON-encoded
+// blob.
+var jsonKindMap = map[reflect.Kind]bool{
+	reflect.Array:  true,
+	reflect.Map:    true,
+	reflect.Ptr:    true,
+	reflect.Slice:  true,
+	reflect.Struct: true,
+}
+
+// jsonStore returns the JSON encoding of val for storage in the blob column
+// nameStr. A field with the json option in its tag, for example
+// `ql:"payload,json"`, can be of any type that encoding/json supports, such
+// as a map, a slice or a nested structure.
+func (db *DbType) jsonStore(nameStr string, val interface{}) (buf []byte) {
+	var err error
+	if buf, err = json.Marshal(val); err != nil {
+		db.SetErrorf("cannot encode column %s as JSON: %s", nameStr, err)
+	}
+	return
+}
+
+// jsonLoad returns a value of type tp decoded from the JSON-encoded blob
+// val.
+func jsonLoad(tp reflect.Type, val interface{}) (vl reflect.Value, err error) {
+	ptrVl := reflect.New(tp)
+	if err = json.Unmarshal(val.([]byte), ptrVl.Interface()); err == nil {
+		vl = ptrVl.Elem()
+	}
+	return
+}
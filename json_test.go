@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the storage of composite field values as JSON.
+func ExampleDbType_TableCreate_json() {
+	type dimType struct {
+		W, H float64
+	}
+	type itemType struct {
+		ID    int64             `ql_table:"item"`
+		Name  string            `ql:"*"`
+		Tags  []string          `ql:"*,json"`
+		Attrs map[string]string `ql:"*,json"`
+		Dim   *dimType          `ql:"*,json"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&itemType{})
+	list := []itemType{
+		{Name: "lamp", Tags: []string{"light", "desk"}, Attrs: map[string]string{"color": "red"},
+			Dim: &dimType{12, 40}},
+		{Name: "rug"},
+	}
+	db.Insert(list)
+	list[1].Attrs = map[string]string{"size": "large"}
+	db.Update(&list[1], "Attrs")
+	list = nil
+	db.Retrieve(&list, "ORDER BY Name")
+	for _, item := range list {
+		fmt.Println(item.Name, item.Tags, item.Attrs, item.Dim)
+	}
+	var raw []struct {
+		Name  string
+		Attrs map[string]string
+	}
+	db.RawRetrieve(&raw, "SELECT Name, Attrs FROM item ORDER BY Name")
+	for _, item := range raw {
+		fmt.Println(item.Name, item.Attrs)
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// lamp [light desk] map[color:red] &{12 40}
+	// rug [] map[size:large] <nil>
+	// lamp map[color:red]
+	// rug map[size:large]
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"encoding/json"
+)
+
+type kvRecType struct {
+	ID     int64  `ql_table:"qlm_kv"`
+	Bucket string `ql:"*" ql_index:"*"`
+	Name   string `ql:"*" ql_index:"*"`
+	Val    []byte `ql:"*"`
+}
+
+// KVType provides simple key-value storage for settings and other small items
+// of state that do not warrant a dedicated structure and table. Keys are
+// grouped into buckets; the entries of all buckets are stored in the table
+// qlm_kv. Errors are reported by means of the qlm instance from which the
+// bucket was obtained.
+type KVType struct {
+	db        *DbType
+	bucketStr string
+}
+
+// KV returns the key-value bucket identified by bucketStr. The key-value table
+// is created if it does not already exist.
+func (db *DbType) KV(bucketStr string) (kv *KVType) {
+	kv = &KVType{db: db, bucketStr: bucketStr}
+	db.TableEnsure(&kvRecType{})
+	return
+}
+
+// Get returns the value associated with keyStr. ok is false if the key is
+// not present in the bucket.
+func (kv *KVType) Get(keyStr string) (val []byte, ok bool) {
+	var list []kvRecType
+	kv.db.Retrieve(&list, "WHERE Bucket == ?1 && Name == ?2", kv.bucketStr, keyStr)
+	if len(list) > 0 {
+		val, ok = list[0].Val, true
+	}
+	return
+}
+
+// Set associates val with keyStr, replacing any previous value.
+func (kv *KVType) Set(keyStr string, val []byte) {
+	db := kv.db
+	if db.err != nil {
+		return
+	}
+	var list []kvRecType
+	db.TransactBegin()
+	db.Retrieve(&list, "WHERE Bucket == ?1 && Name == ?2", kv.bucketStr, keyStr)
+	if db.err == nil {
+		if len(list) > 0 {
+			list[0].Val = val
+			db.Update(&list[0], "Val")
+		} else {
+			db.Insert([]kvRecType{{Bucket: kv.bucketStr, Name: keyStr, Val: val}})
+		}
+	}
+	db.transactEnd(db.err == nil)
+}
+
+// GetJSON decodes the JSON value associated with keyStr into the value
+// pointed to by valPtr. ok is false if the key is not present in the bucket,
+// in which case valPtr is not modified.
+func (kv *KVType) GetJSON(keyStr string, valPtr interface{}) (ok bool) {
+	var buf []byte
+	buf, ok = kv.Get(keyStr)
+	if ok {
+		kv.db.SetError(json.Unmarshal(buf, valPtr))
+		ok = kv.db.err == nil
+	}
+	return
+}
+
+// SetJSON associates the JSON encoding of val with keyStr, replacing any
+// previous value.
+func (kv *KVType) SetJSON(keyStr string, val interface{}) {
+	if kv.db.err == nil {
+		buf, err := json.Marshal(val)
+		kv.db.SetError(err)
+		kv.Set(keyStr, buf)
+	}
+}
+
+// Delete removes keyStr and its value from the bucket. It is not an error if
+// the key is not present.
+func (kv *KVType) Delete(keyStr string) {
+	kv.db.Delete(&kvRecType{}, "WHERE Bucket == ?1 && Name == ?2", kv.bucketStr, keyStr)
+}
+
+// Keys returns the keys of the bucket in ascending order.
+func (kv *KVType) Keys() (keyList []string) {
+	var list []kvRecType
+	kv.db.Retrieve(&list, "WHERE Bucket == ?1 ORDER BY Name", kv.bucketStr)
+	for _, rec := range list {
+		keyList = append(keyList, rec.Name)
+	}
+	return
+}
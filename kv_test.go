@@ -0,0 +1,54 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the key-value convenience layer. Values can be
+// stored as raw bytes or, for structured data, as JSON.
+func ExampleKVType() {
+	type winType struct {
+		X, Y, W, H int
+	}
+	db := qlm.DbCreate("data/example.ql")
+	kv := db.KV("settings")
+	kv.Set("theme", []byte("dark"))
+	kv.Set("theme", []byte("light"))
+	kv.SetJSON("window", winType{10, 20, 640, 480})
+	db.KV("other").Set("theme", []byte("unused"))
+	val, ok := kv.Get("theme")
+	fmt.Println(string(val), ok)
+	var win winType
+	ok = kv.GetJSON("window", &win)
+	fmt.Println(win, ok)
+	fmt.Println(kv.Keys())
+	kv.Delete("theme")
+	_, ok = kv.Get("theme")
+	fmt.Println(ok, kv.Keys())
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// light true
+	// {10 20 640 480} true
+	// [theme window]
+	// false [window]
+}
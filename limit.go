@@ -0,0 +1,81 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// errLimit stops the loading of records once the requested number have been
+// retrieved.
+var errLimit = errors.New("record limit reached")
+
+// RetrieveN is like Retrieve() except that at most maxRows records, starting
+// after the first offset records that satisfy tailStr, are appended to the
+// slice pointed to by slicePtr. tailStr may contain WHERE and ORDER BY
+// clauses but not LIMIT or OFFSET clauses; these are appended by this method.
+// maxRows must be positive and offset must not be negative. Loading stops as
+// soon as maxRows records have been appended, so this method can be used to
+// cap the size of a result set regardless of how the query is constructed.
+func (db *DbType) RetrieveN(slicePtr interface{}, maxRows, offset int, tailStr string, prms ...interface{}) {
+	if db.err != nil {
+		return
+	}
+	if maxRows < 1 {
+		db.SetErrorf("maximum row count must be positive, got %d", maxRows)
+		return
+	}
+	if offset < 0 {
+		db.SetErrorf("row offset must not be negative, got %d", offset)
+		return
+	}
+	wordList, _ := tailWordList(tailStr)
+	for _, word := range wordList {
+		switch strings.ToUpper(word) {
+		case "LIMIT", "OFFSET":
+			db.SetErrorf("function RetrieveN does not accept a %s clause", strings.ToUpper(word))
+			return
+		}
+	}
+	recTp := db.slicePtrCheck(slicePtr, "RetrieveN")
+	if db.err != nil {
+		return
+	}
+	dsc := db.dscFromType(recTp)
+	slicePtrVl := reflect.ValueOf(slicePtr)
+	sliceVl := slicePtrVl.Elem()
+	tailStr = fmt.Sprintf("%s LIMIT ?%d OFFSET ?%d", tailStr, len(prms)+1, len(prms)+2)
+	prms = append(prms[:len(prms):len(prms)], int64(maxRows), int64(offset))
+	count := 0
+	db.retrieveEach(dsc, false, func(recVl reflect.Value) error {
+		sliceVl = reflect.Append(sliceVl, recVl)
+		count++
+		if count >= maxRows {
+			return errLimit
+		}
+		return nil
+	}, strings.TrimSpace(tailStr), prms...)
+	if db.err == errLimit {
+		db.err = nil
+	}
+	if db.err == nil {
+		slicePtrVl.Elem().Set(sliceVl)
+	}
+}
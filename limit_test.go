@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the retrieval of records one page at a time.
+func ExampleDbType_RetrieveN() {
+	type recType struct {
+		ID  int64  `ql_table:"rec"`
+		Num int64  `ql:"*"`
+		Str string `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&recType{})
+	var list []recType
+	for j := int64(1); j <= 10; j++ {
+		list = append(list, recType{Num: j, Str: fmt.Sprintf("rec %d", j)})
+	}
+	db.Insert(list)
+	for offset := 0; offset < 10 && db.OK(); offset += 4 {
+		list = nil
+		db.RetrieveN(&list, 4, offset, "WHERE Num > ?1 ORDER BY Num DESC", int64(1))
+		for _, rec := range list {
+			fmt.Printf("%s, ", rec.Str)
+		}
+		fmt.Println(len(list))
+	}
+	db.RetrieveN(&list, 4, 0, "ORDER BY Num LIMIT 2")
+	fmt.Println(db.Error())
+	db.ClearError()
+	db.RetrieveN(&list, 0, 0, "")
+	fmt.Println(db.Error())
+	db.ClearError()
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// rec 10, rec 9, rec 8, rec 7, 4
+	// rec 6, rec 5, rec 4, rec 3, 4
+	// rec 2, 1
+	// function RetrieveN does not accept a LIMIT clause
+	// maximum row count must be positive, got 0
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogEntryType describes a statement that has been submitted to ql for
+// execution. It is passed to the logger established with SetLogger().
+type LogEntryType struct {
+	Cmd      string        // Statement text, after the expansion of named parameters
+	Prms     []interface{} // Parameters of the statement
+	Cached   bool          // True if the compiled statement was reused
+	Transact bool          // True if a transaction was pending
+	Dur      time.Duration // Time taken to compile and execute the statement
+	Err      error         // Error reported by ql, or nil
+	Slow     bool          // True if Dur exceeds the threshold set by SlowQueryThreshold()
+}
+
+// Expanded returns the statement text of entry with each positional
+// parameter replaced by its value. String values are quoted and other values
+// are formatted with the %v verb of the fmt package. The result is meant for
+// logs and diagnostic messages; it is not guaranteed to be valid ql.
+func (entry LogEntryType) Expanded() string {
+	var buf strings.Builder
+	var quote byte
+	str := entry.Cmd
+	for j := 0; j < len(str); j++ {
+		ch := str[j]
+		switch {
+		case quote != 0:
+			if ch == '\\' && quote == '"' && j+1 < len(str) {
+				buf.WriteByte(ch)
+				j++
+				ch = str[j]
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '`':
+			quote = ch
+		case ch == '?':
+			k := j + 1
+			for k < len(str) && str[k] >= '0' && str[k] <= '9' {
+				k++
+			}
+			if n, err := strconv.Atoi(str[j+1 : k]); err == nil && n >= 1 && n <= len(entry.Prms) {
+				if valStr, ok := entry.Prms[n-1].(string); ok {
+					buf.WriteString(strconv.Quote(valStr))
+				} else {
+					fmt.Fprintf(&buf, "%v", entry.Prms[n-1])
+				}
+				j = k - 1
+				continue
+			}
+		}
+		buf.WriteByte(ch)
+	}
+	return buf.String()
+}
+
+// Logger is the interface that is satisfied by loggers that can be passed to
+// SetLogger().
+type Logger interface {
+	Log(entry LogEntryType)
+}
+
+// LoggerFunc is an adapter that allows an ordinary function to be used as a
+// Logger, for example
+//
+//	db.SetLogger(qlm.LoggerFunc(func(e qlm.LogEntryType) {
+//		slog.Debug("ql", "cmd", e.Cmd, "dur", e.Dur, "err", e.Err)
+//	}))
+type LoggerFunc func(entry LogEntryType)
+
+// Log calls fn(entry).
+func (fn LoggerFunc) Log(entry LogEntryType) {
+	fn(entry)
+}
+
+// traceLogger is the logger that is established by Trace(). It prints each
+// statement with a three character flag indicating whether the statement was
+// cached (C), whether a transaction was pending (T), and whether an error
+// occurred (E).
+type traceLogger struct{}
+
+func (traceLogger) Log(entry LogEntryType) {
+	fmt.Printf("QL [%s%s%s] %s\n",
+		strIf(entry.Cached, "C", "-"),
+		strIf(entry.Transact, "T", "-"),
+		strIf(entry.Err != nil, "E", "-"),
+		entry.Cmd)
+}
+
+// SlowQueryThreshold restricts the statements that are passed to the logger
+// established with SetLogger() or Trace() to those whose execution takes
+// longer than dur. The Slow field of each such entry is set. This allows
+// intermittently slow statements to be identified without logging every
+// statement. The Expanded() method of the entry renders the statement with
+// its parameters in place. A value of zero for dur, the default, passes all
+// statements to the logger.
+func (db *DbType) SlowQueryThreshold(dur time.Duration) {
+	if db.err == nil {
+		db.slow = dur
+	}
+}
+
+// SetLogger establishes lg as the recipient of a LogEntryType value for each
+// statement that is submitted to ql for execution, whether by Exec() or by
+// another qlm method. This allows statements to be routed into the logging
+// facility of the application. A value of nil for lg stops logging. Only one
+// logger is active at a time; calling SetLogger replaces the logger
+// established by Trace(), and vice versa.
+func (db *DbType) SetLogger(lg Logger) {
+	if db.err == nil {
+		db.logger = lg
+	}
+}
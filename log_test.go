@@ -0,0 +1,70 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+	"time"
+)
+
+// This example demonstrates routing executed statements to a logger.
+func ExampleDbType_SetLogger() {
+	type noteType struct {
+		ID   int64  `ql_table:"note"`
+		Text string `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&noteType{})
+	db.SetLogger(qlm.LoggerFunc(func(e qlm.LogEntryType) {
+		fmt.Printf("%-40s %v %v %v %v\n", e.Cmd, e.Prms, e.Transact, e.Err != nil, e.Dur >= 0)
+	}))
+	var list []noteType
+	db.Retrieve(&list, "WHERE Text == ?1", "alpha")
+	db.SetLogger(nil)
+	db.Retrieve(&list, "")
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// SELECT id(), Text FROM note WHERE Text == ?1; [alpha] false false true
+}
+
+// This example demonstrates the logging of slow statements.
+func ExampleDbType_SlowQueryThreshold() {
+	type noteType struct {
+		ID   int64  `ql_table:"note"`
+		Text string `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&noteType{})
+	db.SetLogger(qlm.LoggerFunc(func(e qlm.LogEntryType) {
+		fmt.Println(e.Slow, e.Expanded())
+	}))
+	db.SlowQueryThreshold(time.Hour)
+	var list []noteType
+	db.Retrieve(&list, "WHERE Text == ?1", "alpha")
+	db.SlowQueryThreshold(time.Nanosecond)
+	db.Retrieve(&list, "WHERE Text == ?1 || Text == \"?2\" || id() == ?2", "beta", int64(7))
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// true SELECT id(), Text FROM note WHERE Text == "beta" || Text == "?2" || id() == 7;
+}
@@ -0,0 +1,212 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ManagerType owns several named qlm instances and routes operations to one
+// of them based on the type of the record involved. This allows an
+// application that splits its data across multiple ql files to use a single
+// facade. Like DbType, ManagerType retains the first error that occurs,
+// whether in routing or in one of the managed databases, and subsequent
+// operations return without effect until ClearError() is called.
+type ManagerType struct {
+	dbMap    map[string]*DbType
+	routeMap map[reflect.Type]string
+	err      error
+}
+
+// Manager returns an empty manager. Databases are associated with it using
+// Add() and record types are mapped to databases using Route().
+func Manager() (mgr *ManagerType) {
+	mgr = new(ManagerType)
+	mgr.dbMap = make(map[string]*DbType)
+	mgr.routeMap = make(map[reflect.Type]string)
+	return
+}
+
+// OK returns true if no processing errors have occurred.
+func (mgr *ManagerType) OK() bool {
+	return mgr.err == nil
+}
+
+// Err returns true if a processing error has occurred.
+func (mgr *ManagerType) Err() bool {
+	return mgr.err != nil
+}
+
+// ClearError unsets the current error value of the manager and of each
+// managed database.
+func (mgr *ManagerType) ClearError() {
+	mgr.err = nil
+	for _, db := range mgr.dbMap {
+		db.ClearError()
+	}
+}
+
+// SetErrorf sets the internal manager error with formatted text to halt
+// subsequent calls.
+func (mgr *ManagerType) SetErrorf(fmtStr string, args ...interface{}) {
+	if mgr.err == nil {
+		mgr.err = fmt.Errorf(fmtStr, args...)
+	}
+}
+
+// Error returns the internal manager error; this will be nil if no error has
+// occurred.
+func (mgr *ManagerType) Error() error {
+	return mgr.err
+}
+
+// Add associates the qlm instance db with the name nameStr. An error
+// occurring previously in db is transferred to the manager.
+func (mgr *ManagerType) Add(nameStr string, db *DbType) {
+	if mgr.err == nil {
+		if _, ok := mgr.dbMap[nameStr]; ok {
+			mgr.SetErrorf("database %s already added to manager", nameStr)
+		} else {
+			mgr.dbMap[nameStr] = db
+			mgr.errTransfer(db)
+		}
+	}
+}
+
+// Route maps the types of the specified records to the database that was
+// added with the name nameStr. Each element of recList may be a record, a
+// pointer to a record, a slice of records, or a pointer to a slice of records.
+func (mgr *ManagerType) Route(nameStr string, recList ...interface{}) {
+	if mgr.err == nil {
+		if _, ok := mgr.dbMap[nameStr]; ok {
+			for _, rec := range recList {
+				mgr.routeMap[recTypeOf(rec)] = nameStr
+			}
+		} else {
+			mgr.SetErrorf("database %s has not been added to manager", nameStr)
+		}
+	}
+}
+
+// Db returns the qlm instance to which the type of rec is routed. rec may be
+// any of the forms accepted by Route(). Nil is returned and the manager error
+// is set if the type has not been routed.
+func (mgr *ManagerType) Db(rec interface{}) (db *DbType) {
+	if mgr.err == nil {
+		tp := recTypeOf(rec)
+		nameStr, ok := mgr.routeMap[tp]
+		if ok {
+			db = mgr.dbMap[nameStr]
+		} else {
+			mgr.SetErrorf("no database routed for type %v", tp)
+		}
+	}
+	return
+}
+
+// Close closes each of the managed qlm instances in name order.
+func (mgr *ManagerType) Close() {
+	var nameList []string
+	for nameStr := range mgr.dbMap {
+		nameList = append(nameList, nameStr)
+	}
+	sort.Strings(nameList)
+	for _, nameStr := range nameList {
+		db := mgr.dbMap[nameStr]
+		db.Close()
+		mgr.errTransfer(db)
+	}
+}
+
+// TableCreate calls TableCreate() on the database to which the record type is
+// routed.
+func (mgr *ManagerType) TableCreate(recPtr interface{}) {
+	if db := mgr.Db(recPtr); db != nil {
+		db.TableCreate(recPtr)
+		mgr.errTransfer(db)
+	}
+}
+
+// TableEnsure calls TableEnsure() on the database to which the record type is
+// routed.
+func (mgr *ManagerType) TableEnsure(recPtr interface{}) {
+	if db := mgr.Db(recPtr); db != nil {
+		db.TableEnsure(recPtr)
+		mgr.errTransfer(db)
+	}
+}
+
+// Insert calls Insert() on the database to which the record type is routed.
+func (mgr *ManagerType) Insert(slice interface{}) {
+	if db := mgr.Db(slice); db != nil {
+		db.Insert(slice)
+		mgr.errTransfer(db)
+	}
+}
+
+// Retrieve calls Retrieve() on the database to which the record type is
+// routed.
+func (mgr *ManagerType) Retrieve(slicePtr interface{}, tailStr string, prms ...interface{}) {
+	if db := mgr.Db(slicePtr); db != nil {
+		db.Retrieve(slicePtr, tailStr, prms...)
+		mgr.errTransfer(db)
+	}
+}
+
+// Update calls Update() on the database to which the record type is routed.
+func (mgr *ManagerType) Update(recPtr interface{}, fldNames ...string) {
+	if db := mgr.Db(recPtr); db != nil {
+		db.Update(recPtr, fldNames...)
+		mgr.errTransfer(db)
+	}
+}
+
+// Delete calls Delete() on the database to which the record type is routed.
+func (mgr *ManagerType) Delete(recPtr interface{}, tailStr string, prms ...interface{}) {
+	if db := mgr.Db(recPtr); db != nil {
+		db.Delete(recPtr, tailStr, prms...)
+		mgr.errTransfer(db)
+	}
+}
+
+// Truncate calls Truncate() on the database to which the record type is
+// routed.
+func (mgr *ManagerType) Truncate(recPtr interface{}) {
+	if db := mgr.Db(recPtr); db != nil {
+		db.Truncate(recPtr)
+		mgr.errTransfer(db)
+	}
+}
+
+// errTransfer retains the error of db, if any, as the manager error.
+func (mgr *ManagerType) errTransfer(db *DbType) {
+	if mgr.err == nil && db.err != nil {
+		mgr.err = db.err
+	}
+}
+
+// recTypeOf returns the record type of rec after removing any levels of
+// pointer and slice indirection.
+func recTypeOf(rec interface{}) (tp reflect.Type) {
+	tp = reflect.TypeOf(rec)
+	for tp != nil && (tp.Kind() == reflect.Ptr || tp.Kind() == reflect.Slice) {
+		tp = tp.Elem()
+	}
+	return
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the use of a manager to route records of
+// different types to separate database files. Once the routes are configured,
+// the application does not need to keep track of which handle stores which
+// table.
+func ExampleManagerType() {
+	type cfgType struct {
+		ID  int64  `ql_table:"cfg"`
+		Key string `ql:"*"`
+		Val string `ql:"*"`
+	}
+	type logType struct {
+		ID  int64  `ql_table:"log"`
+		Msg string `ql:"*"`
+	}
+	mgr := qlm.Manager()
+	mgr.Add("config", qlm.DbCreate("data/config.ql"))
+	mgr.Add("log", qlm.DbCreate("data/log.ql"))
+	mgr.Route("config", &cfgType{})
+	mgr.Route("log", &logType{})
+	mgr.TableCreate(&cfgType{})
+	mgr.TableCreate(&logType{})
+	mgr.Insert([]cfgType{{0, "color", "blue"}})
+	mgr.Insert([]logType{{0, "started"}, {0, "configured"}})
+	var cfgList []cfgType
+	var logList []logType
+	mgr.Retrieve(&cfgList, "")
+	mgr.Retrieve(&logList, "ORDER BY id()")
+	for _, c := range cfgList {
+		fmt.Printf("%s: %s\n", c.Key, c.Val)
+	}
+	for _, l := range logList {
+		fmt.Println(l.Msg)
+	}
+	fmt.Println(mgr.Db(&logList) == mgr.Db(logType{}))
+	mgr.Retrieve(&[]struct{ A int }{}, "")
+	fmt.Println(mgr.Error())
+	mgr.Close()
+	// Output:
+	// color: blue
+	// started
+	// configured
+	// true
+	// no database routed for type struct { A int }
+}
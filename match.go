@@ -0,0 +1,190 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// fuzzyMin is the minimum trigram similarity for a record to be included in
+// the results of RetrieveFuzzy()
+const fuzzyMin = 0.3
+
+// stringField returns the structure field associated with the string column
+// fldStr of the table described by dsc.
+func (db *DbType) stringField(dsc qlDscType, fldStr string) (sf reflect.StructField) {
+	var ok bool
+	sf, ok = dsc.nameMap[fldStr]
+	if !ok {
+		db.SetErrorf("field %s not found in table %s", fldStr, dsc.tblStr)
+	} else if sf.Type.Kind() != reflect.String {
+		db.SetErrorf("string field expected, got %v", sf.Type)
+	}
+	return
+}
+
+// retrieveMatch calls Retrieve() after conjoining condStr, which refers to the
+// parameters in condPrms, to the WHERE clause of tailStr. Parameter tokens in
+// condStr are written as "?%d" and are numbered following prms. fncStr names
+// the calling function in error messages.
+func (db *DbType) retrieveMatch(fncStr string, slicePtr interface{}, fldStr, condStr string,
+	condPrms []interface{}, tailStr string, prms []interface{}) {
+	if db.err != nil {
+		return
+	}
+	if db.slicePtrCheck(slicePtr, fncStr); db.err != nil {
+		return
+	}
+	dsc := db.dscFromType(recTypeOf(slicePtr))
+	db.stringField(dsc, fldStr)
+	if db.err == nil {
+		var numList []interface{}
+		for j := range condPrms {
+			numList = append(numList, len(prms)+j+1)
+		}
+		condStr = fmt.Sprintf(condStr, numList...)
+		db.Retrieve(slicePtr, whereAnd(tailStr, condStr), append(prms, condPrms...)...)
+	}
+}
+
+// RetrievePrefix appends to the slice pointed to by slicePtr the records whose
+// string column fldStr begins with prefixStr and that satisfy tailStr and its
+// parameters. The comparison is case-sensitive. The prefix test is expressed
+// as a range so that an index on fldStr, if present, can be used.
+func (db *DbType) RetrievePrefix(slicePtr interface{}, fldStr, prefixStr string,
+	tailStr string, prms ...interface{}) {
+	// The upper bound is the shortest string that is greater than every string
+	// beginning with the prefix
+	hiStr := strings.TrimRight(prefixStr, "\xff")
+	if len(hiStr) > 0 {
+		last := len(hiStr) - 1
+		hiStr = hiStr[:last] + string([]byte{hiStr[last] + 1})
+		db.retrieveMatch("RetrievePrefix", slicePtr, fldStr, fldStr+" >= ?%d && "+fldStr+" < ?%d",
+			[]interface{}{prefixStr, hiStr}, tailStr, prms)
+	} else {
+		db.retrieveMatch("RetrievePrefix", slicePtr, fldStr, fldStr+" >= ?%d",
+			[]interface{}{prefixStr}, tailStr, prms)
+	}
+}
+
+// RetrievePrefixFold is like RetrievePrefix() except that the comparison is
+// case-insensitive. This test requires each record to be examined.
+func (db *DbType) RetrievePrefixFold(slicePtr interface{}, fldStr, prefixStr string,
+	tailStr string, prms ...interface{}) {
+	db.retrieveMatch("RetrievePrefixFold", slicePtr, fldStr, fldStr+" LIKE ?%d",
+		[]interface{}{"(?i)^" + regexp.QuoteMeta(prefixStr)}, tailStr, prms)
+}
+
+// RetrieveFold appends to the slice pointed to by slicePtr the records whose
+// string column fldStr is equal to str without regard to case and that
+// satisfy tailStr and its parameters.
+func (db *DbType) RetrieveFold(slicePtr interface{}, fldStr, str string,
+	tailStr string, prms ...interface{}) {
+	db.retrieveMatch("RetrieveFold", slicePtr, fldStr, fldStr+" LIKE ?%d",
+		[]interface{}{"(?i)^" + regexp.QuoteMeta(str) + "$"}, tailStr, prms)
+}
+
+// trigramMap returns the set of case-folded three-character sequences in str.
+// The string is padded with spaces so that short strings and word boundaries
+// contribute sequences.
+func trigramMap(str string) (gramMap map[string]bool) {
+	gramMap = make(map[string]bool)
+	rl := []rune("  " + strings.ToLower(str) + " ")
+	for j := 0; j+3 <= len(rl); j++ {
+		gramMap[string(rl[j:j+3])] = true
+	}
+	return
+}
+
+// trigramSimilarity returns the ratio of shared trigrams to all trigrams of
+// the two sets.
+func trigramSimilarity(aMap, bMap map[string]bool) float64 {
+	var shared int
+	for gramStr := range aMap {
+		if bMap[gramStr] {
+			shared++
+		}
+	}
+	all := len(aMap) + len(bMap) - shared
+	if all == 0 {
+		return 0
+	}
+	return float64(shared) / float64(all)
+}
+
+// RetrieveFuzzy appends to the slice pointed to by slicePtr up to limit
+// records whose string column fldStr is similar to str and that satisfy
+// tailStr and its parameters. Similarity is measured by the proportion of
+// shared three-character sequences (trigrams) without regard to case. Records
+// are appended in order of decreasing similarity. A value of zero for limit
+// places no limit on the number of records. The database is used to exclude
+// records that have no trigram in common with str, so only plausible
+// candidates are transferred to the application for scoring.
+func (db *DbType) RetrieveFuzzy(slicePtr interface{}, fldStr, str string, limit int,
+	tailStr string, prms ...interface{}) {
+	if db.err != nil {
+		return
+	}
+	strMap := trigramMap(str)
+	var patList []string
+	rl := []rune(str)
+	for j := 0; j+3 <= len(rl); j++ {
+		patList = append(patList, regexp.QuoteMeta(string(rl[j:j+3])))
+	}
+	if len(patList) == 0 {
+		patList = append(patList, regexp.QuoteMeta(str))
+	}
+	slicePtrVl := reflect.ValueOf(slicePtr)
+	if slicePtrVl.Kind() != reflect.Ptr || slicePtrVl.Elem().Kind() != reflect.Slice {
+		db.SetErrorf("function RetrieveFuzzy expecting pointer to slice, got %v", slicePtrVl.Kind())
+		return
+	}
+	candPtrVl := reflect.New(slicePtrVl.Elem().Type())
+	db.retrieveMatch("RetrieveFuzzy", candPtrVl.Interface(), fldStr, fldStr+" LIKE ?%d",
+		[]interface{}{"(?i)" + strings.Join(patList, "|")}, tailStr, prms)
+	if db.err == nil {
+		type scoreType struct {
+			val   float64
+			recVl reflect.Value
+		}
+		var scoreList []scoreType
+		candVl := candPtrVl.Elem()
+		sf := db.stringField(db.dscFromType(recTypeOf(slicePtr)), fldStr)
+		for j := 0; j < candVl.Len(); j++ {
+			recVl := candVl.Index(j)
+			val := trigramSimilarity(strMap, trigramMap(valueList(recVl, []reflect.StructField{sf})[0].String()))
+			if val >= fuzzyMin {
+				scoreList = append(scoreList, scoreType{val, recVl})
+			}
+		}
+		sort.SliceStable(scoreList, func(a, b int) bool {
+			return scoreList[a].val > scoreList[b].val
+		})
+		if limit > 0 && len(scoreList) > limit {
+			scoreList = scoreList[:limit]
+		}
+		sliceVl := slicePtrVl.Elem()
+		for _, score := range scoreList {
+			sliceVl = reflect.Append(sliceVl, score.recVl)
+		}
+		slicePtrVl.Elem().Set(sliceVl)
+	}
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates prefix, case-insensitive and fuzzy matching of a
+// string column, as might be used for autocompletion.
+func ExampleDbType_RetrievePrefix() {
+	type cityType struct {
+		ID   int64  `ql_table:"city"`
+		Name string `ql:"*" ql_index:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&cityType{})
+	db.Insert([]cityType{{0, "Paris"}, {0, "Parma"}, {0, "Pisa"}, {0, "palermo"},
+		{0, "Marseille"}, {0, "Marsala"}})
+	show := func(list []cityType) {
+		for _, c := range list {
+			fmt.Printf(" %s", c.Name)
+		}
+		fmt.Println()
+	}
+	var list []cityType
+	db.RetrievePrefix(&list, "Name", "Par", "ORDER BY Name")
+	show(list)
+	list = nil
+	db.RetrievePrefixFold(&list, "Name", "pa", "ORDER BY Name")
+	show(list)
+	list = nil
+	db.RetrieveFold(&list, "Name", "PISA", "")
+	show(list)
+	list = nil
+	db.RetrieveFuzzy(&list, "Name", "marseile", 2, "")
+	show(list)
+	for _, fnc := range []func(interface{}, string, string, string, ...interface{}){
+		db.RetrievePrefix, db.RetrievePrefixFold, db.RetrieveFold} {
+		fnc(nil, "Name", "pa", "")
+		fmt.Println(db.Error())
+		db.ClearError()
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	//  Paris Parma
+	//  Paris Parma palermo
+	//  Pisa
+	//  Marseille Marsala
+	// function RetrievePrefix expecting pointer to slice, got <nil>
+	// function RetrievePrefixFold expecting pointer to slice, got <nil>
+	// function RetrieveFold expecting pointer to slice, got <nil>
+}
@@ -0,0 +1,173 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+)
+
+// MergePolicyType is a function that resolves a conflict between two versions
+// of a record during Merge. a and b point to the versions from the first and
+// second database respectively. The function returns the version to keep;
+// this may be a or b, or a pointer to a new record of the same type that
+// combines them.
+type MergePolicyType func(a, b interface{}) interface{}
+
+// mergeLoad returns a slice of all records, including those marked as deleted,
+// of the table described by dsc.
+func (db *DbType) mergeLoad(dsc qlDscType) reflect.Value {
+	slicePtrVl := reflect.New(reflect.SliceOf(dsc.recTp))
+	db.retrieveResult(slicePtrVl.Interface(), dsc.sel.sfList, 0, nil,
+		fmt.Sprintf("SELECT %s FROM %s ORDER BY id();", dsc.sel.nameStr, dsc.tblStr))
+	return slicePtrVl.Elem()
+}
+
+// mergeKey returns the value that identifies the record recVl in both copies
+// of a database. This is the record's identifier combined, if the type embeds
+// Model or SoftModel, with its creation time.
+func mergeKey(dsc qlDscType, recVl reflect.Value) string {
+	list := append([]reflect.StructField{dsc.idSf}, dsc.auto.createList...)
+	return fmt.Sprint(valList(recVl, list)...)
+}
+
+// mergeNewer is the default merge policy. It keeps the version that was
+// changed more recently according to its UpdatedAt and DeletedAt times, or
+// the first version if the type has neither field.
+func mergeNewer(dsc qlDscType) MergePolicyType {
+	list := append([]reflect.StructField{}, dsc.auto.updateList...)
+	if len(dsc.soft.nameStr) > 0 {
+		list = append(list, dsc.nameMap[dsc.soft.nameStr])
+	}
+	changed := func(recPtr interface{}) (tm time.Time) {
+		for _, val := range valList(reflect.ValueOf(recPtr).Elem(), list) {
+			if val.(time.Time).After(tm) {
+				tm = val.(time.Time)
+			}
+		}
+		return
+	}
+	return func(a, b interface{}) interface{} {
+		if changed(b).After(changed(a)) {
+			return b
+		}
+		return a
+	}
+}
+
+// Merge combines two divergent copies of a database, for example copies that
+// were modified independently on two devices, into a new database at outPath.
+// The copies at aPath and bPath are not modified; outPath must differ from
+// both. The merged database is built in a uniquely named temporary file in
+// the directory of outPath, named like outPath with a leading dot and the
+// suffix ".merge-", which replaces outPath only if the merge succeeds. recPtrs
+// point to records of the types whose tables are merged.
+//
+// Records are matched by identifier and, if the record type embeds Model or
+// SoftModel, by creation time, so records that were inserted independently
+// into both copies are not confused even if ql assigned them the same
+// identifier. Records that are present in only one copy are included. When
+// the two versions of a matching record differ, policy is called to select
+// the version to keep. If policy is nil, the version that was changed more
+// recently, according to its UpdatedAt and DeletedAt fields, is kept; if the
+// type has neither field, the version from aPath is kept.
+//
+// Since deletions leave no trace, a record deleted from only one copy is
+// restored by the merge; record types that embed SoftModel propagate
+// deletions because their DeletedAt field is merged like any other. Records
+// receive new identifiers in the merged database, so fields that refer to
+// other records by identifier are copied unchanged.
+func Merge(aPath, bPath, outPath string, policy MergePolicyType, recPtrs ...interface{}) error {
+	outAbs, err := filepath.Abs(outPath)
+	for _, inPath := range []string{aPath, bPath} {
+		if err == nil {
+			var inAbs string
+			if inAbs, err = filepath.Abs(inPath); err == nil && inAbs == outAbs {
+				err = fmt.Errorf("merge output %s must differ from its inputs", outPath)
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+	// The result is built in a temporary file that replaces outPath only
+	// when the merge succeeds. Its name is unique, so concurrent merges to
+	// the same output do not interfere, and the lock file that ql leaves for
+	// it is removed as well.
+	tmp, err := os.CreateTemp(filepath.Dir(outAbs), "."+filepath.Base(outAbs)+".merge-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	tmp.Close()
+	defer os.Remove(lockFileStr(tmpPath))
+	defer os.Remove(tmpPath)
+	a := DbOpen(aPath)
+	b := DbOpen(bPath)
+	out := DbCreate(tmpPath)
+	for _, recPtr := range recPtrs {
+		dsc := out.dscFromPtr(recPtr)
+		out.TableCreate(recPtr)
+		if out.err != nil {
+			break
+		}
+		aVl := a.mergeLoad(dsc)
+		bVl := b.mergeLoad(dsc)
+		if out.err = a.err; out.err == nil {
+			out.err = b.err
+		}
+		if out.err != nil {
+			break
+		}
+		fn := policy
+		if fn == nil {
+			fn = mergeNewer(dsc)
+		}
+		bMap := make(map[string]int)
+		for j := 0; j < bVl.Len(); j++ {
+			bMap[mergeKey(dsc, bVl.Index(j))] = j
+		}
+		resVl := reflect.MakeSlice(aVl.Type(), 0, aVl.Len()+bVl.Len())
+		for j := 0; j < aVl.Len(); j++ {
+			recVl := aVl.Index(j)
+			keyStr := mergeKey(dsc, recVl)
+			if k, ok := bMap[keyStr]; ok {
+				if !reflect.DeepEqual(recVl.Interface(), bVl.Index(k).Interface()) {
+					recVl = reflect.ValueOf(fn(recVl.Addr().Interface(), bVl.Index(k).Addr().Interface())).Elem()
+				}
+				delete(bMap, keyStr)
+			}
+			resVl = reflect.Append(resVl, recVl)
+		}
+		for j := 0; j < bVl.Len(); j++ {
+			if _, ok := bMap[mergeKey(dsc, bVl.Index(j))]; ok {
+				resVl = reflect.Append(resVl, bVl.Index(j))
+			}
+		}
+		out.Insert(resVl.Interface())
+	}
+	a.Close()
+	b.Close()
+	out.Close()
+	if out.err == nil {
+		out.err = os.Rename(tmpPath, outAbs)
+	}
+	return out.err
+}
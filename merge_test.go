@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+	"io"
+	"os"
+	"time"
+)
+
+func fileCopy(dstStr, srcStr string) (err error) {
+	var src, dst *os.File
+	src, err = os.Open(srcStr)
+	if err == nil {
+		dst, err = os.Create(dstStr)
+		if err == nil {
+			_, err = io.Copy(dst, src)
+			dst.Close()
+		}
+		src.Close()
+	}
+	return
+}
+
+// This example demonstrates the merging of two copies of a database that
+// were modified independently.
+func ExampleMerge() {
+	type siteType struct {
+		qlm.SoftModel `ql_table:"site"`
+		Name          string `ql:"*"`
+		Status        string `ql:"*"`
+	}
+	tm := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	db := qlm.DbCreate("data/base.ql")
+	db.TableCreate(&siteType{})
+	list := []siteType{{Name: "north", Status: "new"}, {Name: "south", Status: "new"}, {Name: "east", Status: "new"}}
+	for j := range list {
+		list[j].CreatedAt = tm
+		list[j].UpdatedAt = tm
+	}
+	db.Insert(list)
+	db.Close()
+	fileCopy("data/a.ql", "data/base.ql")
+	fileCopy("data/b.ql", "data/base.ql")
+	// Device a surveys north and adds west
+	a := qlm.DbOpen("data/a.ql")
+	list[0].Status = "surveyed"
+	a.Update(&list[0], "Status")
+	a.Insert([]siteType{{Name: "west", Status: "new"}})
+	a.Close()
+	// Device b later revises north and removes east
+	time.Sleep(10 * time.Millisecond)
+	b := qlm.DbOpen("data/b.ql")
+	list[0].Status = "revised"
+	b.Update(&list[0], "Status")
+	b.Delete(&siteType{}, "WHERE Name == ?1", "east")
+	b.Close()
+	err := qlm.Merge("data/a.ql", "data/b.ql", "data/example.ql", nil, &siteType{})
+	if err == nil {
+		db = qlm.DbOpen("data/example.ql")
+		list = nil
+		db.Retrieve(&list, "ORDER BY Name")
+		for _, rec := range list {
+			fmt.Printf("%-5s %s\n", rec.Name, rec.Status)
+		}
+		db.Close()
+		err = db.Error()
+	}
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(qlm.Merge("data/a.ql", "data/b.ql", "data/../data/a.ql", nil, &siteType{}))
+	a = qlm.DbOpen("data/a.ql")
+	list = nil
+	a.Retrieve(&list, "")
+	a.Close()
+	fmt.Println(len(list), a.Error())
+	// Output:
+	// north revised
+	// south new
+	// west  new
+	// merge output data/../data/a.ql must differ from its inputs
+	// 4 <nil>
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"sort"
+	"time"
+)
+
+// statLimit is the maximum number of statements whose statistics are kept
+// individually if the statement cache is not limited; see CacheLimit().
+const statLimit = 1000
+
+// StatOther is the Cmd of the entry of Stats() that accumulates the
+// statistics of statements that are not kept individually because the
+// number of distinct statements has reached the limit.
+const StatOther = "(other statements)"
+
+// StmtStatType holds the execution statistics of a single statement.
+type StmtStatType struct {
+	Cmd    string        // Statement text, after the expansion of named parameters
+	Count  int64         // Number of executions
+	Errors int64         // Number of executions that resulted in an error
+	Dur    time.Duration // Cumulative time taken to compile and execute the statement
+}
+
+// StatsType is a snapshot of the statement statistics of a database. It is
+// returned by Stats().
+type StatsType struct {
+	Count  int64          // Number of executions of all statements
+	Errors int64          // Number of executions that resulted in an error
+	Dur    time.Duration  // Cumulative time taken by all statements
+	List   []StmtStatType // Statistics of each statement, slowest first
+}
+
+// statRecord adds an execution of cmdStr that took dur to the statement
+// statistics of db.
+func (db *DbType) statRecord(cmdStr string, dur time.Duration, err error) {
+	db.shr.mu.Lock()
+	defer db.shr.mu.Unlock()
+	st, ok := db.shr.statMap[cmdStr]
+	if !ok {
+		limit := db.shr.cache.limit
+		if limit <= 0 {
+			limit = statLimit
+		}
+		if len(db.shr.statMap) >= limit {
+			cmdStr = StatOther
+			st, ok = db.shr.statMap[cmdStr]
+		}
+	}
+	if !ok {
+		st = &StmtStatType{Cmd: cmdStr}
+		db.shr.statMap[cmdStr] = st
+	}
+	st.Count++
+	st.Dur += dur
+	if err != nil {
+		st.Errors++
+	}
+}
+
+// Stats returns a snapshot of the execution statistics of every statement
+// that has been submitted to ql by db, whether by Exec() or by another qlm
+// method such as Retrieve() or Insert(). Statements are distinguished by
+// their text, so the statistics of a statement generated from a given tail
+// clause accumulate over the calls that use it regardless of parameter
+// values. The statements are sorted by descending cumulative duration, which
+// places the most expensive ones at the head of the list. The number of
+// statements kept individually is bounded by the limit established with
+// CacheLimit() or, if the cache is not limited, by 1000; once the bound is
+// reached, new statements are accumulated in a single entry whose Cmd is
+// StatOther, so the totals remain accurate. Statistics are kept regardless of
+// the error state of the database; see also StatsReset().
+func (db *DbType) Stats() (stats StatsType) {
+	if db.shr == nil {
+		return
+	}
+	db.shr.mu.Lock()
+	for _, st := range db.shr.statMap {
+		stats.Count += st.Count
+		stats.Errors += st.Errors
+		stats.Dur += st.Dur
+		stats.List = append(stats.List, *st)
+	}
+	db.shr.mu.Unlock()
+	sort.Slice(stats.List, func(a, b int) bool {
+		if stats.List[a].Dur != stats.List[b].Dur {
+			return stats.List[a].Dur > stats.List[b].Dur
+		}
+		return stats.List[a].Cmd < stats.List[b].Cmd
+	})
+	return
+}
+
+// StatsReset discards the statement statistics accumulated by db.
+func (db *DbType) StatsReset() {
+	if db.shr != nil {
+		db.shr.mu.Lock()
+		db.shr.statMap = make(map[string]*StmtStatType)
+		db.shr.mu.Unlock()
+	}
+}
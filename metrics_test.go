@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the statement statistics.
+func ExampleDbType_Stats() {
+	type noteType struct {
+		ID   int64  `ql_table:"note"`
+		Text string `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&noteType{})
+	db.StatsReset()
+	var list []noteType
+	for _, str := range []string{"alpha", "beta", "gamma"} {
+		db.Retrieve(&list, "WHERE Text == ?1", str)
+	}
+	db.Exec("SELECT * FROM missing;")
+	db.ClearError()
+	stats := db.Stats()
+	fmt.Println(stats.Count, stats.Errors, len(stats.List))
+	for _, st := range stats.List {
+		if st.Errors == 0 {
+			fmt.Println(st.Count, st.Cmd)
+		}
+	}
+	db.RetrievePage(&list, qlm.PageType{Number: 1, Size: 1}, "ORDER BY Text")
+	n := len(db.Stats().List)
+	for page := int64(2); page <= 3; page++ {
+		db.RetrievePage(&list, qlm.PageType{Number: page, Size: 1}, "ORDER BY Text")
+	}
+	fmt.Println(len(db.Stats().List) == n)
+	db.CacheLimit(2)
+	db.StatsReset()
+	for _, str := range []string{"alpha", "beta", "gamma"} {
+		db.Retrieve(&list, fmt.Sprintf("WHERE Text == %q", str))
+	}
+	for _, st := range db.Stats().List {
+		if st.Cmd == qlm.StatOther {
+			fmt.Println(st.Count, st.Cmd)
+		}
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// 4 1 2
+	// 3 SELECT id(), Text FROM note WHERE Text == ?1;
+	// true
+	// 1 (other statements)
+}
@@ -0,0 +1,153 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// MigrationType describes one step in the evolution of a database schema.
+// Each step is identified by a unique positive version number. The changes
+// made by a step are specified either with the Up function or with the UpSQL
+// script (see ExecScript); if both are present, the script is executed
+// first. Down and DownSQL reverse the changes and are needed only if the
+// migration is to be undone with MigrateDown.
+type MigrationType struct {
+	Version int64
+	Name    string
+	Up      func(db *DbType) error
+	UpSQL   string
+	Down    func(db *DbType) error
+	DownSQL string
+}
+
+// schemaVersionType records a migration that has been applied.
+type schemaVersionType struct {
+	ID      int64     `ql_table:"qlm_schema_version"`
+	Version int64     `ql:"*"`
+	Name    string    `ql:"*"`
+	Tm      time.Time `ql:"*"`
+}
+
+// migrateSort returns a copy of list sorted by version. An error is set if a
+// version is not positive or appears more than once.
+func (db *DbType) migrateSort(list []MigrationType) (sorted []MigrationType) {
+	sorted = append(sorted, list...)
+	sort.Slice(sorted, func(a, b int) bool {
+		return sorted[a].Version < sorted[b].Version
+	})
+	for j, m := range sorted {
+		if db.err == nil {
+			if m.Version < 1 {
+				db.SetErrorf("migration version must be positive, got %d", m.Version)
+			} else if j > 0 && sorted[j-1].Version == m.Version {
+				db.SetErrorf("duplicate migration version %d", m.Version)
+			}
+		}
+	}
+	return
+}
+
+// migrateApplied returns the versions of the migrations that have been
+// applied to the database.
+func (db *DbType) migrateApplied() (verMap map[int64]bool) {
+	verMap = make(map[int64]bool)
+	var list []schemaVersionType
+	db.TableEnsure(&schemaVersionType{})
+	db.Retrieve(&list, "")
+	for _, rec := range list {
+		verMap[rec.Version] = true
+	}
+	return
+}
+
+// migrateStep runs fn and then scriptStr within a transaction, and then
+// records the change of version with ver. The migration is identified in any
+// resulting error by m.
+func (db *DbType) migrateStep(m MigrationType, fn func(*DbType) error, scriptStr string, ver func()) {
+	err := db.Transact(func(tx *DbType) error {
+		if len(scriptStr) > 0 {
+			tx.ExecScript(scriptStr)
+		}
+		if fn != nil && tx.err == nil {
+			tx.err = fn(tx)
+		}
+		if tx.err == nil {
+			ver()
+		}
+		return nil
+	})
+	if err != nil {
+		db.err = fmt.Errorf("migration %d (%s): %s", m.Version, m.Name, err)
+	}
+}
+
+// Migrate applies, in order of version, each migration in list that has not
+// yet been applied to the database. Each migration runs in its own
+// transaction together with the recording of its version in the table
+// qlm_schema_version, so a migration is either applied completely or not at
+// all. Migration stops at the first failure; the database error identifies
+// the failed migration. Because applied migrations are skipped, the complete
+// list can be passed to Migrate each time an application opens its database.
+func (db *DbType) Migrate(list []MigrationType) {
+	if db.err != nil {
+		return
+	}
+	list = db.migrateSort(list)
+	verMap := db.migrateApplied()
+	for _, m := range list {
+		if db.err == nil && !verMap[m.Version] {
+			m := m
+			db.migrateStep(m, m.Up, m.UpSQL, func() {
+				db.Insert([]schemaVersionType{{Version: m.Version, Name: m.Name, Tm: time.Now()}})
+			})
+		}
+	}
+}
+
+// MigrateDown undoes, in descending order of version, each applied migration
+// in list whose version is greater than toVersion. Each migration is undone in
+// its own transaction by running DownSQL and then Down. Pass zero to undo all
+// migrations.
+func (db *DbType) MigrateDown(list []MigrationType, toVersion int64) {
+	if db.err != nil {
+		return
+	}
+	list = db.migrateSort(list)
+	verMap := db.migrateApplied()
+	for j := len(list) - 1; j >= 0 && db.err == nil; j-- {
+		m := list[j]
+		if m.Version > toVersion && verMap[m.Version] {
+			db.migrateStep(m, m.Down, m.DownSQL, func() {
+				db.Delete(&schemaVersionType{}, "WHERE Version == ?1", m.Version)
+			})
+		}
+	}
+}
+
+// SchemaVersion returns the highest version of the migrations that have been
+// applied to the database, or zero if none have been applied.
+func (db *DbType) SchemaVersion() (ver int64) {
+	for v := range db.migrateApplied() {
+		if v > ver {
+			ver = v
+		}
+	}
+	return
+}
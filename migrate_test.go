@@ -0,0 +1,72 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the versioned evolution of a database schema.
+func ExampleDbType_Migrate() {
+	type userType struct {
+		ID    int64  `ql_table:"user"`
+		Name  string `ql:"*"`
+		Email string `ql:"*"`
+	}
+	list := []qlm.MigrationType{
+		{Version: 1, Name: "create user",
+			UpSQL:   "CREATE TABLE user (Name string);",
+			DownSQL: "DROP TABLE user;"},
+		{Version: 2, Name: "add email",
+			UpSQL:   "ALTER TABLE user ADD Email string;",
+			DownSQL: "ALTER TABLE user DROP COLUMN Email;"},
+		{Version: 3, Name: "seed",
+			Up: func(db *qlm.DbType) error {
+				db.Insert([]userType{{0, "ann", "ann@example.com"}})
+				return nil
+			},
+			Down: func(db *qlm.DbType) error {
+				db.Truncate(&userType{})
+				return nil
+			}},
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.Migrate(list[:2])
+	fmt.Printf("version %d\n", db.SchemaVersion())
+	db.Migrate(list)
+	fmt.Printf("version %d\n", db.SchemaVersion())
+	var users []userType
+	db.Retrieve(&users, "")
+	fmt.Printf("%d user(s)\n", len(users))
+	bad := append(list, qlm.MigrationType{Version: 4, Name: "broken", UpSQL: "ALTER TABLE nosuch ADD x int;"})
+	db.Migrate(bad)
+	fmt.Println(db.Error())
+	db.ClearError()
+	db.MigrateDown(list, 1)
+	fmt.Printf("version %d\n", db.SchemaVersion())
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// version 2
+	// version 3
+	// 1 user(s)
+	// migration 4 (broken): statement 1 at line 1: ALTER TABLE: table nosuch does not exist
+	// version 1
+}
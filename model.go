@@ -0,0 +1,114 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"reflect"
+	"time"
+)
+
+// Model can be embedded in a record structure to supply the ID field and
+// timestamp columns that are common to most tables. The "ql_table" tag (and
+// optionally the "ql_index" tag) that would otherwise be associated with the
+// ID field is placed on the embedded Model field itself, for example
+//
+//	type recType struct {
+//		qlm.Model `ql_table:"rec"`
+//		Name      string `ql:"*"`
+//	}
+//
+// CreatedAt is assigned the current time by Insert if it is zero. UpdatedAt
+// is assigned the current time by Insert if it is zero and by every call to
+// Update.
+type Model struct {
+	ID        int64
+	CreatedAt time.Time `ql:"*"`
+	UpdatedAt time.Time `ql:"*"`
+}
+
+// SoftModel is like Model but adds the DeletedAt column. Records of a type
+// that embeds SoftModel are not removed by Delete; instead, their DeletedAt
+// field is set to the current time. Retrieve excludes records that have been
+// marked in this way.
+type SoftModel struct {
+	ID        int64
+	CreatedAt time.Time `ql:"*"`
+	UpdatedAt time.Time `ql:"*"`
+	DeletedAt time.Time `ql:"*"`
+}
+
+var modelMap = map[reflect.Type]bool{
+	reflect.TypeOf(Model{}):     true,
+	reflect.TypeOf(SoftModel{}): true,
+}
+
+// modelFields returns the fields of the embedded model field sf with offsets
+// and indexes adjusted to be relative to the enclosing structure. The tags of
+// the embedding field are transferred to the ID field.
+func modelFields(sf reflect.StructField) (list []reflect.StructField) {
+	for j := 0; j < sf.Type.NumField(); j++ {
+		sub := sf.Type.Field(j)
+		sub.Offset += sf.Offset
+		sub.Index = append(append([]int{}, sf.Index...), sub.Index...)
+		if sub.Name == "ID" {
+			sub.Tag = sf.Tag
+		}
+		list = append(list, sub)
+	}
+	return
+}
+
+// embedFields returns the fields of the structure type tp. The fields of
+// anonymous embedded structures that do not have a "ql" tag of their own are
+// promoted in place of the embedded field, recursively, with offsets and
+// indexes adjusted to be relative to tp. This allows a set of columns, such
+// as an address block, to be shared by several record types.
+func embedFields(tp reflect.Type) (list []reflect.StructField) {
+	for j := 0; j < tp.NumField(); j++ {
+		sf := tp.Field(j)
+		switch {
+		case sf.Anonymous && modelMap[sf.Type]:
+			list = append(list, modelFields(sf)...)
+		case sf.Anonymous && sf.Type.Kind() == reflect.Struct && len(qlTag(tp, sf)) == 0 &&
+			!typeMap[qlTypeStr(sf.Type)]:
+			for _, sub := range embedFields(sf.Type) {
+				sub.Offset += sf.Offset
+				sub.Index = append(append([]int{}, sf.Index...), sub.Index...)
+				list = append(list, sub)
+			}
+		default:
+			list = append(list, sf)
+		}
+	}
+	return
+}
+
+// modelAssign sets the automatic timestamp fields of the record recVl. If
+// insert is true, zero-valued creation and update timestamps are assigned;
+// otherwise, only the update timestamps are assigned.
+func modelAssign(dsc qlDscType, recVl reflect.Value, insert bool, tm time.Time) {
+	var list []reflect.StructField
+	if insert {
+		list = append(list, dsc.auto.createList...)
+	}
+	list = append(list, dsc.auto.updateList...)
+	for _, fldVl := range valueList(recVl, list) {
+		if !insert || fldVl.Interface().(time.Time).IsZero() {
+			fldVl.Set(reflect.ValueOf(tm))
+		}
+	}
+}
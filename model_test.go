@@ -0,0 +1,186 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+	"time"
+)
+
+// This example demonstrates the embedding of SoftModel in a record structure.
+// The table name is specified with the "ql_table" tag on the embedded field.
+// The timestamps are assigned automatically by Insert and Update. Delete marks
+// records rather than removing them, and Retrieve excludes marked records.
+func ExampleSoftModel() {
+	type recType struct {
+		qlm.SoftModel `ql_table:"rec"`
+		Name          string `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&recType{})
+	list := []recType{{Name: "Athos"}, {Name: "Porthos"}, {Name: "Aramis"}}
+	db.Insert(list)
+	fmt.Println(list[0].ID > 0, !list[0].CreatedAt.IsZero(), list[0].DeletedAt.IsZero())
+	db.Delete(&recType{}, "WHERE Name == ?1", "Porthos")
+	list = nil
+	db.Retrieve(&list, "ORDER BY Name")
+	for _, r := range list {
+		fmt.Println(r.Name)
+	}
+	var count int64
+	rs, _ := db.Exec("SELECT count(*) FROM rec WHERE DeletedAt IS NOT NULL;")
+	if db.OK() {
+		row, _ := rs[0].FirstRow()
+		count = row[0].(int64)
+	}
+	fmt.Printf("%d marked as deleted\n", count)
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// true true true
+	// Aramis
+	// Athos
+	// 1 marked as deleted
+}
+
+// This example demonstrates the sharing of a group of columns by embedding
+// a structure in more than one record type.
+func ExampleModel_embedded() {
+	type addrType struct {
+		Street string `ql:"*"`
+		City   string `ql:"*" ql_index:"*"`
+	}
+	type custType struct {
+		qlm.Model `ql_table:"cust"`
+		Name      string `ql:"*"`
+		addrType
+	}
+	type siteType struct {
+		ID int64 `ql_table:"site"`
+		addrType
+		Code string `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&custType{})
+	db.TableCreate(&siteType{})
+	db.Insert([]custType{{Name: "ann", addrType: addrType{"1 Elm St", "Reno"}}})
+	db.Insert([]siteType{{Code: "W1", addrType: addrType{"9 Oak Ave", "Reno"}},
+		{Code: "E1", addrType: addrType{"4 Pine Rd", "Elko"}}})
+	var custList []custType
+	var siteList []siteType
+	db.Retrieve(&custList, "WHERE City == ?1", "Reno")
+	db.Retrieve(&siteList, "WHERE City == ?1", "Reno")
+	for _, cust := range custList {
+		fmt.Println(cust.Name, cust.Street, cust.City, !cust.CreatedAt.IsZero())
+	}
+	for _, site := range siteList {
+		fmt.Println(site.Code, site.Street, site.City)
+	}
+	for _, fld := range db.Describe(&siteType{}).Fields {
+		fmt.Print(fld.Column, " ", fld.Index, "; ")
+	}
+	fmt.Println()
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// ann 1 Elm St Reno true
+	// W1 9 Oak Ave Reno
+	// Street false; City true; Code false;
+}
+
+// This example demonstrates timestamp fields that are maintained by Insert
+// and Update.
+func ExampleDbType_TableCreate_auto() {
+	type noteType struct {
+		ID      int64     `ql_table:"note"`
+		Text    string    `ql:"*"`
+		Created time.Time `ql:"created,autocreate"`
+		Updated time.Time `ql:"updated,autoupdate"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&noteType{})
+	list := []noteType{{Text: "draft"}}
+	db.Insert(list)
+	rec := list[0]
+	fmt.Println(!rec.Created.IsZero(), rec.Updated.Equal(rec.Created))
+	time.Sleep(2 * time.Millisecond)
+	rec.Text = "final"
+	db.Update(&rec, "Text")
+	list = nil
+	db.Retrieve(&list, "")
+	fmt.Println(list[0].Text, list[0].Created.Equal(rec.Created), list[0].Updated.After(rec.Created))
+	type badType struct {
+		ID      int64  `ql_table:"bad"`
+		Created string `ql:"*,autocreate"`
+	}
+	db.TableCreate(&badType{})
+	fmt.Println(db.Error())
+	db.ClearError()
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// true true
+	// final true true
+	// autocreate and autoupdate options require field Created to be of type time.Time
+}
+
+// This example demonstrates a soft-delete column declared with a tag option
+// and the functions that reach past it.
+func ExampleDbType_RetrieveDeleted() {
+	type itemType struct {
+		ID      int64     `ql_table:"item"`
+		Name    string    `ql:"*"`
+		Deleted time.Time `ql:"deleted_at,softdelete"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&itemType{})
+	db.Insert([]itemType{{Name: "awl"}, {Name: "brad"}, {Name: "clamp"}, {Name: "dowel"}})
+	db.Delete(&itemType{}, "WHERE Name == ?1 || Name == ?2", "brad", "dowel")
+	show := func(label string, list []itemType) {
+		fmt.Print(label)
+		for _, item := range list {
+			fmt.Print(" ", item.Name, strIf(item.Deleted.IsZero(), "", "*"))
+		}
+		fmt.Println()
+	}
+	var list []itemType
+	db.Retrieve(&list, "ORDER BY Name")
+	show("live:", list)
+	list = nil
+	db.RetrieveDeleted(&list, "ORDER BY Name")
+	show("deleted:", list)
+	db.DeleteHard(&itemType{}, "WHERE Name == ?1 || Name == ?2", "awl", "brad")
+	list = nil
+	db.Retrieve(&list, "ORDER BY Name")
+	db.RetrieveDeleted(&list, "ORDER BY Name")
+	show("remaining:", list)
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// live: awl clamp
+	// deleted: brad* dowel*
+	// remaining: clamp dowel*
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Args holds the values of named parameters. When an Args value is passed
+// as one of the parameters of a qlm function, each token of the form ":name"
+// in the statement or tail clause is replaced with a positional parameter
+// that refers to the value of name, for example
+//
+//	db.Retrieve(&list, "WHERE Num > :min && Num < :max", qlm.Args{"min": 10, "max": 20})
+//
+// Named and positional parameters can be mixed. The Args value occupies a
+// position of its own, so a positional parameter that follows it is referred
+// to with "?2" rather than "?1". Tokens within string literals are not
+// changed.
+type Args map[string]interface{}
+
+// argsExpand returns cmdStr and prms with the named parameter tokens in
+// cmdStr replaced by positional ones. The values of the Args in prms are
+// appended to the returned parameter list and the Args themselves are
+// replaced with nil so that existing positions are preserved. cmdStr and prms
+// are returned unchanged if prms contains no Args.
+func argsExpand(cmdStr string, prms []interface{}) (outStr string, list []interface{}, err error) {
+	var args Args
+	for j, prm := range prms {
+		if a, ok := prm.(Args); ok {
+			if args == nil {
+				args = make(Args)
+				list = append(list, prms...)
+			}
+			for key, val := range a {
+				args[key] = val
+			}
+			list[j] = nil
+		}
+	}
+	if args == nil {
+		return cmdStr, prms, nil
+	}
+	posMap := make(map[string]int)
+	var buf strings.Builder
+	var quote byte
+	isName := func(ch byte, first bool) bool {
+		return ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' ||
+			!first && ch >= '0' && ch <= '9'
+	}
+	for j := 0; j < len(cmdStr) && err == nil; j++ {
+		ch := cmdStr[j]
+		switch {
+		case quote != 0:
+			if ch == '\\' && quote == '"' && j+1 < len(cmdStr) {
+				buf.WriteByte(ch)
+				j++
+				ch = cmdStr[j]
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '`':
+			quote = ch
+		case ch == ':' && j+1 < len(cmdStr) && isName(cmdStr[j+1], true):
+			k := j + 1
+			for k < len(cmdStr) && isName(cmdStr[k], false) {
+				k++
+			}
+			nameStr := cmdStr[j+1 : k]
+			pos, ok := posMap[nameStr]
+			if !ok {
+				var val interface{}
+				if val, ok = args[nameStr]; ok {
+					list = append(list, val)
+					pos = len(list)
+					posMap[nameStr] = pos
+				} else {
+					err = fmt.Errorf("named parameter :%s not found in arguments", nameStr)
+				}
+			}
+			fmt.Fprintf(&buf, "?%d", pos)
+			j = k - 1
+			continue
+		}
+		buf.WriteByte(ch)
+	}
+	outStr = buf.String()
+	return
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the use of named parameters.
+func ExampleArgs() {
+	type numType struct {
+		ID   int64  `ql_table:"num"`
+		Num  int64  `ql:"*"`
+		Name string `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&numType{})
+	db.Insert([]numType{{0, 12, "twelve"}, {0, 5, "five"}, {0, 15, "fifteen"},
+		{0, 25, "twenty-five"}, {0, 18, "eighteen"}})
+	var list []numType
+	db.Retrieve(&list, "WHERE Num > :min && Num < :max && Name != \":min\" ORDER BY Num",
+		qlm.Args{"min": int64(10), "max": int64(20)})
+	for _, rec := range list {
+		fmt.Println(rec.Num, rec.Name)
+	}
+	db.Delete(&numType{}, "WHERE Num >= :min && Name != ?2",
+		qlm.Args{"min": int64(15)}, "fifteen")
+	list = nil
+	db.Query(&numType{}).Where("Num < :max", qlm.Args{"max": int64(100)}).OrderBy("Num").Fetch(&list)
+	for _, rec := range list {
+		fmt.Println(rec.Num, rec.Name)
+	}
+	db.Retrieve(&list, "WHERE Num > :lo", qlm.Args{"min": int64(1)})
+	fmt.Println(db.Error())
+	db.ClearError()
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// 12 twelve
+	// 15 fifteen
+	// 18 eighteen
+	// 5 five
+	// 12 twelve
+	// 15 fifteen
+	// named parameter :lo not found in arguments
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// Namer converts the name of a structure field to the name of the column in
+// which the field is stored. It is applied to fields whose "ql" tag specifies
+// the column name as "*"; see SetNamer().
+type Namer func(fldStr string) string
+
+// SnakeCase is a Namer that converts a field name in mixed case to lower case
+// with words separated by underscores. For example, GroupNum becomes
+// group_num, UserID becomes user_id and HTTPServer becomes http_server.
+func SnakeCase(fldStr string) string {
+	var buf strings.Builder
+	rs := []rune(fldStr)
+	for j, r := range rs {
+		if unicode.IsUpper(r) {
+			if j > 0 && (!unicode.IsUpper(rs[j-1]) && rs[j-1] != '_' ||
+				j+1 < len(rs) && unicode.IsLower(rs[j+1])) {
+				buf.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		buf.WriteRune(r)
+	}
+	return buf.String()
+}
+
+// qlmPkgStr is the import path of this package. The tables that qlm
+// maintains for its own purposes, such as the journal, are described by types
+// declared here; their column names are not subject to a Namer.
+var qlmPkgStr = reflect.TypeOf(DbType{}).PkgPath()
+
+// SetNamer establishes fn as the means by which the column name of a field
+// with the tag `ql:"*"` is derived from the field name. By default, the column
+// name is the field name itself. For example, after
+//
+//	db.SetNamer(qlm.SnakeCase)
+//
+// the field GroupNum with the tag `ql:"*"` is stored in the column group_num.
+// Column names that are spelled out in tags are used unaltered. A value of
+// nil for fn restores the default. SetNamer should be called before any record
+// types are used with db, since tables created with one naming strategy are
+// not found under another. Like other settings, the naming strategy of a
+// session is independent of that of the database it was made from.
+func (db *DbType) SetNamer(fn Namer) {
+	if db.err == nil {
+		db.namer = fn
+		db.dscCache = dscCacheNew() // Rebuild descriptors with fn for db alone
+	}
+}
+
+// colName returns the name of the column of the field sf, a member of the
+// record type recTp, whose "ql" tag specifies the column name as "*".
+func (db *DbType) colName(recTp reflect.Type, sf reflect.StructField) string {
+	if db.namer == nil || recTp.PkgPath() == qlmPkgStr {
+		return sf.Name
+	}
+	return db.namer(sf.Name)
+}
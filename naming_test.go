@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+	"strings"
+)
+
+// This example demonstrates the derivation of column names with a naming
+// strategy.
+func ExampleDbType_SetNamer() {
+	type recType struct {
+		qlm.Model `ql_table:"rec"`
+		GroupNum  int64  `ql:"*"`
+		UserID    string `ql:"*"`
+		Label     string `ql:"Lbl"`
+	}
+	var strList []string
+	for _, str := range []string{"GroupNum", "UserID", "HTTPServer", "Field2Name", "already_snake"} {
+		strList = append(strList, qlm.SnakeCase(str))
+	}
+	fmt.Println(strings.Join(strList, " "))
+	db := qlm.DbCreate("data/example.ql")
+	db.SetNamer(qlm.SnakeCase)
+	db.TableCreate(&recType{})
+	db.Insert([]recType{{GroupNum: 7, UserID: "kj", Label: "first"}})
+	strList = strList[:0]
+	for _, col := range db.Columns("rec") {
+		strList = append(strList, col.Name)
+	}
+	fmt.Println(strings.Join(strList, " "))
+	var list []recType
+	db.Retrieve(&list, "WHERE group_num == ?1", int64(7))
+	fmt.Println(len(list), list[0].UserID, list[0].Label)
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// group_num user_id http_server field2_name already_snake
+	// created_at updated_at group_num user_id Lbl
+	// 1 kj first
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+// notifyType is a change that awaits the commit of the transaction in which
+// it was made.
+type notifyType struct {
+	nest   int    // Transaction level at which the change was made
+	tblStr string // Table of the changed record
+	opStr  string // ChangeInsert, ChangeUpdate, ChangeDelete or ChangeTruncate
+	id     int64  // Identifier of the changed record; zero for ChangeTruncate
+}
+
+// Subscribe registers fn to be called after each committed change to the
+// table of the record type pointed to by recPtr. op is one of ChangeInsert,
+// ChangeUpdate, ChangeDelete or ChangeTruncate, and id identifies the changed
+// record (zero for ChangeTruncate). Changes made by Insert, Update, Delete,
+// Truncate and the other record methods of qlm are reported; changes made with
+// Exec or ExecScript are not. Changes that are rolled back are not reported.
+//
+// Notifications are delivered in order on a separate goroutine, so fn must
+// not call methods of db. Close waits until all pending notifications have
+// been delivered.
+func (db *DbType) Subscribe(recPtr interface{}, fn func(op string, id int64)) {
+	dsc := db.dscFromPtr(recPtr)
+	if db.err != nil {
+		return
+	}
+	if db.notify.subMap == nil {
+		db.notify.subMap = make(map[string][]func(string, int64))
+		db.notify.ch = make(chan []func(), 64)
+		db.notify.done = make(chan struct{})
+		go func(ch chan []func(), done chan struct{}) {
+			for list := range ch {
+				for _, fn := range list {
+					fn()
+				}
+			}
+			close(done)
+		}(db.notify.ch, db.notify.done)
+	}
+	db.notify.subMap[dsc.tblStr] = append(db.notify.subMap[dsc.tblStr], fn)
+}
+
+// tracked returns true if changes to the records described by dsc are to be
+// recorded in the journal or the audit trail, or reported to subscribers.
+func (db *DbType) tracked(dsc qlDscType) bool {
+	return db.journaled(dsc) || db.audited(dsc) || db.err == nil && len(db.notify.subMap[dsc.tblStr]) > 0
+}
+
+// notifyQueue sets aside the operation opStr on the records described by dsc
+// that have the identifiers in idList for delivery to subscribers when the
+// current transaction is committed.
+func (db *DbType) notifyQueue(dsc qlDscType, opStr string, idList ...int64) {
+	if db.err == nil && len(db.notify.subMap[dsc.tblStr]) > 0 {
+		for _, id := range idList {
+			db.notify.pending = append(db.notify.pending,
+				notifyType{nest: db.transact.nest, tblStr: dsc.tblStr, opStr: opStr, id: id})
+		}
+	}
+}
+
+// notifyEnd resolves the pending changes of the transaction at the current
+// level when it ends. Changes that are rolled back are discarded. Changes
+// that are committed pass to the enclosing transaction or, at the outermost
+// level, are delivered to subscribers.
+func (db *DbType) notifyEnd(ok bool) {
+	nest := db.transact.nest
+	list := db.notify.pending[:0]
+	for _, nt := range db.notify.pending {
+		if nt.nest >= nest {
+			if !ok {
+				continue
+			}
+			nt.nest = nest - 1
+		}
+		list = append(list, nt)
+	}
+	db.notify.pending = list
+	if nest == 1 && len(list) > 0 {
+		var fnList []func()
+		for _, nt := range list {
+			nt := nt
+			for _, fn := range db.notify.subMap[nt.tblStr] {
+				fn := fn
+				fnList = append(fnList, func() { fn(nt.opStr, nt.id) })
+			}
+		}
+		db.notify.pending = nil
+		db.notify.ch <- fnList
+	}
+}
+
+// notifyClose waits for the delivery of all committed changes.
+func (db *DbType) notifyClose() {
+	if db.notify.ch != nil {
+		close(db.notify.ch)
+		<-db.notify.done
+		db.notify.ch = nil
+		db.notify.subMap = nil
+	}
+}
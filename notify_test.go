@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates notification of committed changes.
+func ExampleDbType_Subscribe() {
+	type itemType struct {
+		ID   int64  `ql_table:"item"`
+		Name string `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&itemType{})
+	var list []string
+	var idList []int64
+	db.Subscribe(&itemType{}, func(op string, id int64) {
+		list = append(list, op)
+		idList = append(idList, id)
+	})
+	items := []itemType{{Name: "awl"}, {Name: "brad"}}
+	db.Insert(items)
+	items[0].Name = "auger"
+	db.Update(&items[0], "Name")
+	db.TransactBegin()
+	db.Delete(&itemType{}, "WHERE Name == ?1", "brad")
+	db.TransactRollback()
+	db.Delete(&itemType{}, "WHERE Name == ?1", "auger")
+	db.Truncate(&itemType{})
+	db.Close()
+	fmt.Println(list)
+	fmt.Println(idList[0] == items[0].ID, idList[1] == items[1].ID,
+		idList[2] == items[0].ID, idList[3] == items[0].ID, idList[4])
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// [insert insert update delete truncate]
+	// true true true true 0
+}
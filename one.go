@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// ErrNotFound is the error that is set by RetrieveOne() when no record
+// satisfies the specified conditions.
+var ErrNotFound = errors.New("record not found")
+
+// RetrieveOne fills the record pointed to by recPtr with the first record of
+// its type that satisfies tailStr and its parameters, which are handled as
+// they are in Retrieve(). If tailStr has no LIMIT clause, "LIMIT 1" is
+// added to it, ahead of any OFFSET clause, so that at most one record is
+// loaded. True is returned if a
+// record is found. Otherwise, the record is left unchanged and the qlm error
+// is set to ErrNotFound; call ClearError() if the absence of a record is not
+// an error in the context of the call.
+func (db *DbType) RetrieveOne(recPtr interface{}, tailStr string, prms ...interface{}) (found bool) {
+	if db.err != nil {
+		return
+	}
+	ptrVl := reflect.ValueOf(recPtr)
+	if ptrVl.Kind() != reflect.Ptr || ptrVl.Elem().Kind() != reflect.Struct {
+		db.SetErrorf("function RetrieveOne expecting record pointer, got %v", ptrVl.Kind())
+		return
+	}
+	limit := false
+	offsetPos := -1
+	wordList, posList := tailWordList(tailStr)
+	for j, word := range wordList {
+		switch strings.ToUpper(word) {
+		case "LIMIT":
+			limit = true
+		case "OFFSET":
+			offsetPos = posList[j]
+		}
+	}
+	switch {
+	case limit:
+	case offsetPos >= 0:
+		// ql requires LIMIT to precede OFFSET
+		tailStr = tailStr[:offsetPos] + "LIMIT 1 " + tailStr[offsetPos:]
+	default:
+		tailStr = strings.TrimSpace(tailStr + " LIMIT 1")
+	}
+	listPtrVl := reflect.New(reflect.SliceOf(ptrVl.Elem().Type()))
+	db.Retrieve(listPtrVl.Interface(), tailStr, prms...)
+	if db.err == nil {
+		listVl := listPtrVl.Elem()
+		if listVl.Len() > 0 {
+			ptrVl.Elem().Set(listVl.Index(0))
+			found = true
+		} else {
+			db.SetError(ErrNotFound)
+		}
+	}
+	return
+}
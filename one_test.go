@@ -0,0 +1,55 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the retrieval of a single record by key.
+func ExampleDbType_RetrieveOne() {
+	type userType struct {
+		ID    int64  `ql_table:"user"`
+		Email string `ql:"*,unique"`
+		Name  string `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&userType{})
+	db.Insert([]userType{{0, "ann@example.com", "Ann"}, {0, "bob@example.com", "Bob"}})
+	var user userType
+	if db.RetrieveOne(&user, "WHERE Email == ?1", "bob@example.com") {
+		fmt.Println(user.Name)
+	}
+	if !db.RetrieveOne(&user, "WHERE Email == ?1", "cy@example.com") {
+		fmt.Println(db.Error() == qlm.ErrNotFound, user.Name)
+		db.ClearError()
+	}
+	db.RetrieveOne(&user, "ORDER BY Name DESC")
+	fmt.Println(user.Name)
+	db.RetrieveOne(&user, "ORDER BY Name DESC OFFSET 1")
+	fmt.Println(user.Name, db.Error())
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// Bob
+	// true Bob
+	// Bob
+	// Ann <nil>
+}
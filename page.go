@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// PageType identifies a page of records. Number is one-based and Size is the
+// maximum number of records on a page.
+type PageType struct {
+	Number int64
+	Size   int64
+}
+
+// PageResultType describes the page of records loaded by RetrievePage().
+type PageResultType struct {
+	Number  int64 // One-based number of the page
+	Size    int64 // Maximum number of records on a page
+	Count   int64 // Number of records on this page
+	Total   int64 // Number of records on all pages
+	Pages   int64 // Number of pages
+	HasPrev bool  // True if a page precedes this one
+	HasNext bool  // True if a page follows this one
+}
+
+// RetrievePage appends the records on the specified page to the slice
+// pointed to by slicePtr. tailStr and prms are handled as they are in
+// Retrieve() except that tailStr must not include a LIMIT or OFFSET clause;
+// these are appended based on page. The records that satisfy the WHERE
+// clause of tailStr are counted in the same transaction so that the returned
+// totals are consistent with the page.
+func (db *DbType) RetrievePage(slicePtr interface{}, page PageType, tailStr string,
+	prms ...interface{}) (res PageResultType) {
+	if db.err != nil {
+		return
+	}
+	if page.Number < 1 || page.Size < 1 {
+		db.SetErrorf("invalid page %d of size %d", page.Number, page.Size)
+		return
+	}
+	wordList, _ := tailWordList(tailStr)
+	for _, word := range wordList {
+		switch strings.ToUpper(word) {
+		case "LIMIT", "OFFSET":
+			db.SetErrorf("function RetrievePage does not accept %s clause", strings.ToUpper(word))
+			return
+		}
+	}
+	recTp := db.slicePtrCheck(slicePtr, "RetrievePage")
+	if db.err != nil {
+		return
+	}
+	dsc := db.dscFromType(recTp)
+	if db.err != nil {
+		return
+	}
+	countStr := tailStr
+	if len(dsc.soft.nameStr) > 0 {
+		countStr = whereAnd(countStr, dsc.soft.nameStr+" IS NULL")
+	}
+	whereStr, _ := tailSplit(countStr)
+	if len(whereStr) > 0 {
+		whereStr = " WHERE " + whereStr
+	}
+	sliceVl := reflect.Indirect(reflect.ValueOf(slicePtr))
+	start := sliceVl.Len()
+	db.TransactBegin()
+	row := db.firstRow(fmt.Sprintf("SELECT count(*) FROM %s%s;", dsc.fromStr(), whereStr), prms...)
+	if db.err == nil && len(row) > 0 {
+		res.Total = row[0].(int64)
+	}
+	// The limit and offset are bound so that every page shares one statement
+	db.Retrieve(slicePtr, fmt.Sprintf("%s LIMIT ?%d OFFSET ?%d", tailStr, len(prms)+1, len(prms)+2),
+		append(prms[:len(prms):len(prms)], page.Size, (page.Number-1)*page.Size)...)
+	db.transactEnd(db.err == nil)
+	if db.err == nil {
+		res.Number = page.Number
+		res.Size = page.Size
+		res.Count = int64(reflect.Indirect(reflect.ValueOf(slicePtr)).Len() - start)
+		res.Pages = (res.Total + page.Size - 1) / page.Size
+		res.HasPrev = page.Number > 1
+		res.HasNext = page.Number < res.Pages
+	}
+	return
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the retrieval of records one page at a time.
+func ExampleDbType_RetrievePage() {
+	type numType struct {
+		ID  int64 `ql_table:"num"`
+		Num int64 `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&numType{})
+	var list []numType
+	for j := int64(1); j <= 12; j++ {
+		list = append(list, numType{Num: j})
+	}
+	db.Insert(list)
+	for pg := int64(1); pg <= 3; pg++ {
+		list = nil
+		res := db.RetrievePage(&list, qlm.PageType{Number: pg, Size: 4},
+			"WHERE Num > ?1 ORDER BY Num DESC", int64(2))
+		fmt.Printf("%+v", res)
+		for _, rec := range list {
+			fmt.Printf(" %d", rec.Num)
+		}
+		fmt.Println()
+	}
+	db.RetrievePage(&list, qlm.PageType{Number: 1, Size: 4}, "LIMIT 2")
+	fmt.Println(db.Error())
+	db.ClearError()
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// {Number:1 Size:4 Count:4 Total:10 Pages:3 HasPrev:false HasNext:true} 12 11 10 9
+	// {Number:2 Size:4 Count:4 Total:10 Pages:3 HasPrev:true HasNext:true} 8 7 6 5
+	// {Number:3 Size:4 Count:2 Total:10 Pages:3 HasPrev:true HasNext:false} 4 3
+	// function RetrievePage does not accept LIMIT clause
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"reflect"
+)
+
+// WithTable calls fn with tblStr established as the table of the record type
+// pointed to by recPtr, in place of the table named by its "ql_table" tag.
+// This allows one record type to be used with several tables of the same
+// structure, such as the monthly partitions of an event log. Every qlm
+// method called by fn, including TableCreate(), uses tblStr for this type;
+// other record types are not affected. For example,
+//
+//	db.WithTable(&eventType{}, "event_2024_01", func() {
+//		db.TableCreate(&eventType{})
+//		db.Insert(list)
+//	})
+//
+// A prefix established with SetTablePrefix() is applied to tblStr. Calls to
+// WithTable may be nested. The previous table, if any, is restored
+// when fn returns.
+func (db *DbType) WithTable(recPtr interface{}, tblStr string, fn func()) {
+	if db.err != nil {
+		return
+	}
+	recTp := reflect.TypeOf(recPtr)
+	if recTp == nil || recTp.Kind() != reflect.Ptr {
+		db.SetErrorf("expecting record pointer, got %v", recTp)
+		return
+	}
+	recTp = recTp.Elem()
+	db.identCheck("table", tblStr, "function WithTable")
+	if db.err != nil {
+		return
+	}
+	prevStr, prevOK := db.tblMap[recTp]
+	if db.tblMap == nil {
+		db.tblMap = make(map[reflect.Type]string)
+	}
+	db.tblMap[recTp] = tblStr
+	defer func() {
+		if prevOK {
+			db.tblMap[recTp] = prevStr
+		} else {
+			delete(db.tblMap, recTp)
+		}
+	}()
+	fn()
+}
+
+// SetTablePrefix establishes prefixStr as a prefix of the name of every table
+// that qlm uses, including the tables that qlm maintains for its own
+// purposes, such as qlm_journal. This allows several applications, or several
+// test suites, to share one database file without their tables colliding.
+// For example, after
+//
+//	db.SetTablePrefix("app1_")
+//
+// records of a type with the tag `ql_table:"cust"` are stored in the table
+// app1_cust. Methods that accept a table name rather than a record type, such
+// as Columns() and Exec(), expect the full name. An empty prefixStr, the
+// default, removes the prefix. SetTablePrefix should be called before any
+// record types are used with db. The prefix applies to db alone; sessions
+// already made from db keep the prefix they were made with.
+func (db *DbType) SetTablePrefix(prefixStr string) {
+	if db.err != nil {
+		return
+	}
+	if len(prefixStr) > 0 {
+		db.identCheck("table prefix", prefixStr, "function SetTablePrefix")
+	}
+	if db.err == nil {
+		db.tblPrefix = prefixStr
+		db.dscCache = dscCacheNew() // Rebuild descriptors with prefix for db alone
+	}
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+	"strings"
+	"time"
+)
+
+// This example demonstrates the storage of one record type in several
+// tables.
+func ExampleDbType_WithTable() {
+	type eventType struct {
+		ID   int64  `ql_table:"event" ql_index:"*"`
+		Kind string `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&eventType{})
+	for _, month := range []string{"01", "02"} {
+		tblStr := "event_2024_" + month
+		db.WithTable(&eventType{}, tblStr, func() {
+			db.TableCreate(&eventType{})
+			db.Insert([]eventType{{Kind: "open " + month}, {Kind: "close " + month}})
+		})
+	}
+	db.Insert([]eventType{{Kind: "unpartitioned"}})
+	var strList []string
+	for _, tbl := range db.Tables() {
+		strList = append(strList, tbl.Name)
+	}
+	fmt.Println(strings.Join(strList, " "))
+	show := func(tblStr string) {
+		var list []eventType
+		db.WithTable(&eventType{}, tblStr, func() {
+			db.Retrieve(&list, "ORDER BY id()")
+		})
+		strList = strList[:0]
+		for _, rec := range list {
+			strList = append(strList, rec.Kind)
+		}
+		fmt.Printf("%s: %s\n", tblStr, strings.Join(strList, ", "))
+	}
+	show("event_2024_02")
+	show("event")
+	db.WithTable(&eventType{}, "event 2024", func() {})
+	fmt.Println(db.Error())
+	db.ClearError()
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// event event_2024_01 event_2024_02
+	// event_2024_02: open 02, close 02
+	// event: unpartitioned
+	// table name "event 2024" in function WithTable is not a valid ql identifier
+}
+
+// This example demonstrates the separation of the tables of two applications
+// that share a database file. A session may use a prefix other than that of
+// the database it was made from.
+func ExampleDbType_SetTablePrefix() {
+	type custType struct {
+		ID   int64  `ql_table:"cust"`
+		Name string `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	for _, app := range []string{"app1", "app2"} {
+		db.SetTablePrefix(app + "_")
+		db.TableCreate(&custType{})
+		db.Insert([]custType{{Name: "cust of " + app}})
+		q := db.Queue("mail")
+		q.Enqueue([]byte("to " + app))
+		item, _ := q.Dequeue(time.Minute)
+		q.Nack(item.ID)
+	}
+	db.SetTablePrefix("app1_")
+	var list []custType
+	db.Retrieve(&list, "")
+	item, ok := db.Queue("mail").Dequeue(time.Minute)
+	fmt.Println(len(list), list[0].Name, string(item.Data), item.Tries, ok)
+	var strList []string
+	for _, tbl := range db.Tables() {
+		strList = append(strList, tbl.Name)
+	}
+	fmt.Println(strings.Join(strList, " "))
+	db.SetTablePrefix("app-1")
+	fmt.Println(db.Error())
+	db.ClearError()
+	s := db.Session()
+	s.SetTablePrefix("app2_")
+	list = nil
+	s.Retrieve(&list, "")
+	fmt.Println(list[0].Name)
+	s.Close()
+	list = nil
+	db.Retrieve(&list, "")
+	fmt.Println(list[0].Name)
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// 1 cust of app1 to app1 2 true
+	// app1_cust app1_qlm_queue app2_cust app2_qlm_queue
+	// table prefix name "app-1" in function SetTablePrefix is not a valid ql identifier
+	// cust of app2
+	// cust of app1
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"time"
+)
+
+// ProgressType is passed to the function registered with Progress to report
+// the advancement of a bulk operation.
+type ProgressType struct {
+	Op      string        // Name of the operation, for example "Insert"
+	Done    int64         // Number of records processed so far
+	Total   int64         // Total number of records, or -1 if not known in advance
+	Elapsed time.Duration // Time since the operation began
+}
+
+// progressTrack follows the advancement of a single bulk operation.
+type progressTrack struct {
+	every  int64
+	fn     func(ProgressType)
+	pr     ProgressType
+	start  time.Time
+	report int64 // Value of pr.Done at the most recent report
+}
+
+// Progress registers fn to be called periodically during bulk operations such
+// as Insert. fn is called each time another every records have been processed
+// and once more when the operation completes, so command line tools and user
+// interfaces can display progress and estimate the time remaining. fn is
+// called on the goroutine that performs the operation, so it should return
+// promptly. Passing a nil function or a value of every that is less than one
+// disables progress reporting.
+func (db *DbType) Progress(every int64, fn func(ProgressType)) {
+	if db.err == nil {
+		if every < 1 {
+			fn = nil
+		}
+		db.progress.every = every
+		db.progress.fn = fn
+	}
+}
+
+// progressStart returns a tracker for the operation opStr that will process
+// total records, or an unknown number if total is -1. Nil, which is safe to
+// use, is returned if progress reporting is disabled.
+func (db *DbType) progressStart(opStr string, total int64) (trk *progressTrack) {
+	if db.progress.fn != nil {
+		trk = &progressTrack{every: db.progress.every, fn: db.progress.fn, start: time.Now()}
+		trk.pr.Op = opStr
+		trk.pr.Total = total
+	}
+	return
+}
+
+// add records that count more records have been processed and reports
+// progress if the reporting interval has been reached.
+func (trk *progressTrack) add(count int64) {
+	if trk != nil {
+		trk.pr.Done += count
+		if trk.pr.Done-trk.report >= trk.every {
+			trk.send()
+		}
+	}
+}
+
+// end reports the final progress of the operation unless it has just been
+// reported.
+func (trk *progressTrack) end() {
+	if trk != nil && (trk.pr.Done != trk.report || trk.pr.Done == 0) {
+		trk.send()
+	}
+}
+
+func (trk *progressTrack) send() {
+	trk.report = trk.pr.Done
+	trk.pr.Elapsed = time.Since(trk.start)
+	trk.fn(trk.pr)
+}
@@ -0,0 +1,49 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates progress reporting during a bulk insertion.
+func ExampleDbType_Progress() {
+	type numType struct {
+		ID  int64 `ql_table:"num"`
+		Val int64 `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&numType{})
+	db.Progress(400, func(pr qlm.ProgressType) {
+		fmt.Printf("%s: %d of %d (%.0f%%)\n", pr.Op, pr.Done, pr.Total,
+			100*float64(pr.Done)/float64(pr.Total))
+	})
+	list := make([]numType, 1000)
+	for j := range list {
+		list[j].Val = int64(j * j)
+	}
+	db.Insert(list)
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// Insert: 400 of 1000 (40%)
+	// Insert: 800 of 1000 (80%)
+	// Insert: 1000 of 1000 (100%)
+}
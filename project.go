@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"strings"
+)
+
+// project returns a copy of dsc that selects only id() and the columns named
+// in colList. Checksums are not verified for such records since the fields
+// they cover are generally incomplete.
+func (db *DbType) project(dsc qlDscType, colList []string) (out qlDscType) {
+	if db.err != nil {
+		return
+	}
+	posMap := make(map[string]int)
+	for j, nameStr := range dsc.sel.nameList {
+		posMap[nameStr] = j
+	}
+	out = dsc
+	out.sum.nameStr = ""
+	out.sel.sfList, out.sel.typeStrList = nil, nil
+	selList := append([]string{"id()"}, colList...)
+	for _, nameStr := range selList {
+		j, ok := posMap[nameStr]
+		if !ok {
+			db.SetErrorf("table %s has no column %s", dsc.tblStr, nameStr)
+			return
+		}
+		out.sel.sfList = append(out.sel.sfList, dsc.sel.sfList[j])
+		out.sel.typeStrList = append(out.sel.typeStrList, dsc.sel.typeStrList[j])
+	}
+	out.sel.nameStr = strings.Join(selList, ", ")
+	out.sel.nameList = selList
+	return
+}
+
+// RetrieveFields is like Retrieve() except that only the columns named in
+// colList, along with id(), are selected. The other fields of the retrieved
+// records are left with their zero values. Selecting only the columns that
+// are needed reduces the cost of retrieving records from a table with large
+// columns such as blobs. The names in colList are column names as specified
+// in the "ql" tags. Checksums are not verified for records retrieved in this
+// way, and AfterRetrieve hooks are passed the partial records.
+func (db *DbType) RetrieveFields(slicePtr interface{}, colList []string, tailStr string, prms ...interface{}) {
+	if colList == nil {
+		colList = []string{}
+	}
+	db.retrieve(slicePtr, false, colList, tailStr, prms...)
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the retrieval of selected columns.
+func ExampleDbType_RetrieveFields() {
+	type personType struct {
+		First string `ql:"first_name"`
+		Last  string `ql:"last_name"`
+		Photo []byte `ql:"*"`
+		ID    int64  `ql_table:"person"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&personType{})
+	db.Insert([]personType{{"Ann", "Lee", make([]byte, 4096), 0}, {"Bob", "Ray", make([]byte, 4096), 0}})
+	var list []personType
+	db.RetrieveFields(&list, []string{"last_name"}, "ORDER BY last_name")
+	for _, rec := range list {
+		fmt.Printf("[%s] [%s] %d %v\n", rec.First, rec.Last, len(rec.Photo), rec.ID > 0)
+	}
+	db.RetrieveFields(&list, []string{"Name"}, "")
+	fmt.Println(db.Error())
+	db.ClearError()
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// [] [Lee] 0 true
+	// [] [Ray] 0 true
+	// table person has no column Name
+}
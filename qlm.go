@@ -17,13 +17,15 @@
 package qlm
 
 import (
+	"context"
 	"fmt"
 	"github.com/cznic/ql"
 	"os"
 	"path/filepath"
 	"reflect"
 	"strings"
-	"unsafe"
+	"sync"
+	"time"
 )
 
 var typeMap = map[string]bool{
@@ -61,6 +63,7 @@ type transactType struct {
 type idxType struct {
 	nameStr string
 	fldStr  string
+	unique  bool
 }
 
 type qlDscType struct {
@@ -68,7 +71,47 @@ type qlDscType struct {
 	idSf    reflect.StructField
 	recTp   reflect.Type
 	nameMap map[string]reflect.StructField // {"num":@, "name":@, ...}
-	create  struct {
+	typeMap map[string]string              // {"num":"int32", "name":"string", ...}
+	optMap  map[string]map[string]string   // Options from "ql" tag keyed by column name
+	auto    struct {
+		createList []reflect.StructField // Assigned current time on Insert if zero
+		updateList []reflect.StructField // Assigned current time on Insert if zero and on Update
+		updateStr  []string              // {"UpdatedAt"}
+	}
+	geo struct {
+		latStr, lonStr string              // Latitude and longitude columns
+		latSf, lonSf   reflect.StructField // Latitude and longitude fields
+		hashStr        string              // Geohash column, empty if not maintained
+		hashSf         reflect.StructField // Geohash field
+		hashLen        int                 // Geohash precision in characters
+	}
+	ttl struct {
+		nameStr string        // Column compared with the current time to expire records
+		dur     time.Duration // Age after which records are deleted by ExpireNow()
+	}
+	soft struct {
+		nameStr string // Soft-delete column name, empty if records are removed by Delete
+	}
+	view struct {
+		selStr string // SELECT statement registered with View(), empty for tables
+	}
+	collate  []collateType   // Columns ordered by a normalized shadow column
+	json     map[string]bool // Columns stored as JSON-encoded blobs
+	valuer   map[string]bool // Columns converted with Valuer and Scanner
+	defaults []defaultType   // Fields assigned a default value by Insert when zero
+	hook     struct {
+		beforeInsert, afterInsert, beforeUpdate, afterRetrieve bool // Hook interfaces implemented
+	}
+	version struct {
+		nameStr string              // Version column, empty if updates are not versioned
+		sf      reflect.StructField // Version field
+	}
+	sum struct {
+		nameStr string                // Checksum column, empty if not maintained
+		sf      reflect.StructField   // Checksum field
+		sfList  []reflect.StructField // Fields covered by the checksum
+	}
+	create struct {
 		nameTypeStr string    // "num int32, name string, ..."
 		idxList     []idxType // {{"fooID", "id()"}, {"fooName", "Name"}, {"fooNum", "Num"}, ...}
 	}
@@ -80,20 +123,72 @@ type qlDscType struct {
 	}
 	sel struct {
 		nameStr     string                // "id(), num, name, ..."
+		nameList    []string              // {"id()", "num", "name", ...}
 		sfList      []reflect.StructField // Includes ID
-		typeStrList []string              // {"int64", "bigint", "string", ...}
+		typeStrList []string              // {"int64", "bigint", "string", "json", "valuer", ...}
 	}
 }
 
 // DbType facilitates use of the ql database engine. Hnd is the handle to the ql instance.
+//
+// A DbType value holds the current transaction and error of the work done
+// with it, and these are shared by every goroutine that uses the value.
+// DbType is therefore not safe for concurrent use as a unit of work: a
+// transaction begun by one goroutine would include the statements of another,
+// and an error set by one would stop the others. Goroutines should instead
+// each use a session; see Session(). The execution of statements by a single
+// value is serialized, so concurrent calls to Exec() do not corrupt its state,
+// and the caches of table descriptors and compiled statements, and the
+// statement statistics, are guarded internally.
 type DbType struct {
 	Hnd      *ql.DB
 	transact transactType
-	// Cache for table descriptors
-	dscMap map[reflect.Type]qlDscType
-	// Cache for executable commands
-	listMap map[string]ql.List
-	trace   bool
+	// Statement cache and statistics, shared with sessions
+	shr *sharedType
+	// Table descriptors, shared with sessions until one of them changes its
+	// naming settings; see dscCacheType
+	dscCache *dscCacheType
+	// Serializes the execution of statements by this instance; see Exec()
+	execMu *sync.Mutex
+	// Maximum number of records stored by each statement; see InsertChunk()
+	insertChunk int
+	// Statements are executed once, so are neither cached nor counted in
+	// statistics; see Load()
+	transient bool
+	// Databases attached with Attach() and the tables loaded from them
+	attachMap    map[string]*DbType
+	attachTblMap map[string][]string
+	// SELECT statements registered with View()
+	viewMap map[reflect.Type]string
+	// Tables established for record types by WithTable()
+	tblMap map[reflect.Type]string
+	// Prefix of all table names; see SetTablePrefix()
+	tblPrefix string
+	// Tables for which an audit trail is kept; see AuditEnable()
+	auditMap map[string]bool
+	// Automatic expiration of records; see ExpireEvery()
+	expire struct {
+		every time.Duration
+		last  time.Time
+	}
+	// Progress reporting of bulk operations; see Progress()
+	progress struct {
+		every int64
+		fn    func(ProgressType)
+	}
+	// Subscribers to committed changes; see Subscribe()
+	notify struct {
+		subMap  map[string][]func(string, int64)
+		pending []notifyType
+		ch      chan []func()
+		done    chan struct{}
+	}
+	journal bool            // Record changes in qlm_journal; see Journal()
+	ctx     context.Context // Context of the current operation; see RetrieveCtx()
+	logger  Logger          // Recipient of executed statements; see SetLogger()
+	namer   Namer           // Derivation of column names from field names; see SetNamer()
+	autoMap bool            // Store untagged fields; see AutoMap()
+	slow    time.Duration   // Minimum duration of logged statements; see SlowQueryThreshold()
 	err     error
 	tested  bool
 }
@@ -152,8 +247,16 @@ func (db *DbType) String() string {
 
 func (db *DbType) init() {
 	if db.err == nil {
-		db.dscMap = make(map[reflect.Type]qlDscType)
-		db.listMap = make(map[string]ql.List)
+		db.shr = new(sharedType)
+		db.execMu = new(sync.Mutex)
+		db.dscCache = dscCacheNew()
+		db.shr.cache.init()
+		db.shr.statMap = make(map[string]*StmtStatType)
+		db.insertChunk = 500
+		db.attachMap = make(map[string]*DbType)
+		db.attachTblMap = make(map[string][]string)
+		db.viewMap = make(map[reflect.Type]string)
+		db.auditMap = make(map[string]bool)
 	}
 }
 
@@ -173,39 +276,86 @@ func DbSetHandle(hnd *ql.DB) (db *DbType) {
 // DbOpen and DbCreate should be called to initialize the qlm instance. After
 // use, Close() should be called to free resources.
 func DbOpen(dbFileStr string) (db *DbType) {
+	return DbOpenOptions(dbFileStr, nil)
+}
+
+// DbOpenOptions is like DbOpen but opens the database with the ql options
+// pointed to by opts. A value of nil for opts specifies default options. If
+// the CanCreate field of opts is true, the directory path to the file will be
+// created if needed.
+func DbOpenOptions(dbFileStr string, opts *ql.Options) (db *DbType) {
 	db = new(DbType)
-	db.Hnd, db.err = ql.OpenFile(dbFileStr, &ql.Options{})
-	db.init()
+	if opts == nil {
+		opts = &ql.Options{}
+	}
+	if opts.CanCreate {
+		db.err = dirEnsure(dbFileStr)
+	}
+	if db.err == nil {
+		db.Hnd, db.err = ql.OpenFile(dbFileStr, opts)
+		db.init()
+	}
 	return
 }
 
 // DbCreate creates a new ql database with default options or overwrites an
 // existing one. The directory path to the file will be created if needed. Only
 // one of DbSetHandle, DbOpen and DbCreate should be called to initialize the
-// qlm instance. After use, Close() should be called to free resources.
+// qlm instance. After use, Close() should be called to free resources. Any
+// existing database at dbFileStr is removed; use DbCreateIfMissing() to
+// preserve it.
 func DbCreate(dbFileStr string) (db *DbType) {
-	var err error
+	return DbCreateOptions(dbFileStr, nil)
+}
+
+// DbCreateOptions is like DbCreate but creates the database with the ql
+// options pointed to by opts. A value of nil for opts specifies default
+// options. The CanCreate field is set in a copy of opts regardless of its
+// value in opts.
+func DbCreateOptions(dbFileStr string, opts *ql.Options) (db *DbType) {
 	db = new(DbType)
-	dir := filepath.Dir(dbFileStr)
-	_, err = os.Stat(dir)
-	if err != nil {
-		db.err = os.MkdirAll(dir, 0755)
+	var createOpts ql.Options
+	if opts != nil {
+		createOpts = *opts
 	}
+	createOpts.CanCreate = true
+	db.err = dirEnsure(dbFileStr)
 	if db.err == nil {
 		_, err := os.Stat(dbFileStr)
 		if err == nil {
 			db.err = os.Remove(dbFileStr)
 		}
 		if db.err == nil {
-			db.Hnd, db.err = ql.OpenFile(dbFileStr, &ql.Options{CanCreate: true})
+			db.Hnd, db.err = ql.OpenFile(dbFileStr, &createOpts)
 			db.init()
 		}
 	}
 	return
 }
 
+// DbCreateIfMissing opens the ql database dbFileStr with default options if it
+// exists, and otherwise creates it. Unlike DbCreate(), an existing database is
+// never removed. The directory path to the file will be created if needed.
+// TableEnsure() can be used to create the tables of a new database while
+// preserving those of an existing one.
+func DbCreateIfMissing(dbFileStr string) (db *DbType) {
+	return DbOpenOptions(dbFileStr, &ql.Options{CanCreate: true})
+}
+
+// dirEnsure creates the directory path to the file dbFileStr if it does not
+// exist.
+func dirEnsure(dbFileStr string) (err error) {
+	dir := filepath.Dir(dbFileStr)
+	_, err = os.Stat(dir)
+	if err != nil {
+		err = os.MkdirAll(dir, 0755)
+	}
+	return
+}
+
 // Close closes the qlm instance.
 func (db *DbType) Close() {
+	db.notifyClose()
 	if db.Hnd != nil {
 		db.Hnd.Close()
 		db.Hnd = nil
@@ -215,10 +365,14 @@ func (db *DbType) Close() {
 // Trace sets or unsets trace mode in which commands are printed to standard
 // out. Statements that are submitted to ql for execution are printed with a
 // three character flag indicating whether the command was cached (C), whether
-// a transaction is pending (T), and whether an error has occurred (E).
+// a transaction is pending (T), and whether an error has occurred (E). Trace
+// is a shorthand for establishing, or removing, a simple logger; see
+// SetLogger().
 func (db *DbType) Trace(on bool) {
-	if db.err == nil {
-		db.trace = on
+	if on {
+		db.SetLogger(traceLogger{})
+	} else {
+		db.SetLogger(nil)
 	}
 }
 
@@ -230,7 +384,12 @@ func (db *DbType) TransactBegin() {
 		if db.transact.ctx == nil {
 			db.transact.ctx = ql.NewRWCtx()
 		}
+		// The context of the operation, if any, is set aside so that every
+		// transaction that is begun can be ended with transactEnd().
+		ctx := db.ctx
+		db.ctx = nil
 		_, _ = db.Exec("BEGIN TRANSACTION;")
+		db.ctx = ctx
 		if db.err == nil {
 			db.transact.nest++
 		}
@@ -248,13 +407,25 @@ func (db *DbType) transactEnd(ok bool) {
 		str = "rollback"
 	}
 	if db.transact.nest > 0 && db.transact.ctx != nil {
+		// A rollback is typically requested because an error has occurred. The
+		// error is set aside so that the statement is not suppressed, and then
+		// restored as the first error.
+		// The context of the operation, if any, is likewise set aside so that a
+		// canceled operation can still be rolled back.
+		err, ctx := db.err, db.ctx
+		db.err, db.ctx = nil, nil
 		_, _ = db.Exec(cmd)
+		db.ctx = ctx
 		if db.err == nil {
+			db.notifyEnd(ok)
 			db.transact.nest--
 			if db.transact.nest == 0 {
 				db.transact.ctx = nil
 			}
 		}
+		if err != nil {
+			db.err = err
+		}
 	} else {
 		if db.err == nil {
 			db.SetErrorf("no transaction to %s", str)
@@ -285,30 +456,62 @@ func (db *DbType) TransactRollback() {
 
 // Exec compiles and executes a ql statement. This function is typically not
 // needed by applications because various data management operations are
-// handled by other qlm methods.
+// handled by other qlm methods. Named parameters can be passed in an Args
+// value. Calls made with the same DbType value by several goroutines are
+// executed one at a time.
 func (db *DbType) Exec(cmdStr string, prms ...interface{}) (rs []ql.Recordset, index int) {
+	if db.execMu != nil { // Nil only if the database failed to open
+		db.execMu.Lock()
+		defer db.execMu.Unlock()
+	}
 	if db.err != nil {
 		return
 	}
-	list, ok := db.listMap[cmdStr]
+	if cmdStr, prms, db.err = argsExpand(cmdStr, prms); db.err != nil {
+		return
+	}
+	if db.ctx != nil {
+		if db.err = db.ctx.Err(); db.err != nil {
+			return
+		}
+	}
+	start := time.Now()
+	list, ok := db.shr.stmtGet(cmdStr)
+	schema := false
 	if !ok {
-		// Caveat: cached commands may become obsolete as different execution paths
-		// result from changing database.
 		list, db.err = ql.Compile(cmdStr)
 		if db.err == nil {
-			db.listMap[cmdStr] = list
+			schema = schemaStmt(list)
+			if !schema && !db.transient {
+				db.shr.stmtPut(cmdStr, list)
+			}
 		}
 	}
 	if db.err == nil {
 		rs, index, db.err = db.Hnd.Execute(db.transact.ctx, list, prms...)
+		db.err = duplicateCheck(db.err)
+		if schema && db.err == nil {
+			db.shr.stmtClear()
+		}
+	}
+	dur := time.Since(start)
+	if !db.transient {
+		db.statRecord(cmdStr, dur, db.err)
 	}
-	if db.trace {
-		// fmt.Fprintf(os.Stderr, "QL [%s%s%s] %s\n",
-		fmt.Printf("QL [%s%s%s] %s\n",
-			strIf(ok, "C", "-"),
-			strIf(db.transact.ctx != nil, "T", "-"),
-			strIf(db.err != nil, "E", "-"),
-			cmdStr)
+	if db.logger != nil && (db.slow == 0 || dur > db.slow) {
+		db.logger.Log(LogEntryType{Cmd: cmdStr, Prms: prms, Cached: ok,
+			Transact: db.transact.ctx != nil, Dur: dur, Err: db.err, Slow: db.slow > 0})
+	}
+	return
+}
+
+// LastInsertID returns the id() of the record most recently inserted within
+// the pending transaction, or zero if no transaction is pending. This function
+// is typically not needed by applications; it supports code that inserts
+// records with Exec(), such as the functions generated by qlmgen.
+func (db *DbType) LastInsertID() (id int64) {
+	if db.transact.ctx != nil {
+		id = db.transact.ctx.LastInsertID
 	}
 	return
 }
@@ -329,12 +532,91 @@ func prePad(str string) string {
 	return str
 }
 
+// tailWordList returns the words of tailStr that are not enclosed in
+// parentheses or string literals. Each word is returned with its starting
+// position in tailStr.
+func tailWordList(tailStr string) (wordList []string, posList []int) {
+	depth := 0
+	start := -1
+	var quote byte
+	flush := func(j int) {
+		if start >= 0 {
+			if depth == 0 {
+				wordList = append(wordList, tailStr[start:j])
+				posList = append(posList, start)
+			}
+			start = -1
+		}
+	}
+	for j := 0; j < len(tailStr); j++ {
+		ch := tailStr[j]
+		switch {
+		case quote != 0:
+			if ch == '\\' && quote == '"' {
+				j++
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '`':
+			flush(j)
+			quote = ch
+		case ch == '(':
+			flush(j)
+			depth++
+		case ch == ')':
+			flush(j)
+			depth--
+		case ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9':
+			if start < 0 {
+				start = j
+			}
+		default:
+			flush(j)
+		}
+	}
+	flush(len(tailStr))
+	return
+}
+
+// tailSplit separates tailStr into the expression of its WHERE clause (without
+// the keyword) and the remainder that begins with GROUP BY, ORDER BY, LIMIT or
+// OFFSET.
+func tailSplit(tailStr string) (whereStr, restStr string) {
+	wordList, posList := tailWordList(tailStr)
+	restPos := len(tailStr)
+	wherePos := -1
+	for j, word := range wordList {
+		switch strings.ToUpper(word) {
+		case "WHERE":
+			if wherePos < 0 {
+				wherePos = posList[j] + len(word)
+			}
+		case "GROUP", "ORDER", "LIMIT", "OFFSET":
+			if posList[j] < restPos {
+				restPos = posList[j]
+			}
+		}
+	}
+	if wherePos >= 0 && wherePos <= restPos {
+		whereStr = strings.TrimSpace(tailStr[wherePos:restPos])
+	}
+	restStr = strings.TrimSpace(tailStr[restPos:])
+	return
+}
+
+// whereAnd returns tailStr with condStr conjoined to its WHERE clause. A WHERE
+// clause is introduced if tailStr does not have one.
+func whereAnd(tailStr, condStr string) string {
+	whereStr, restStr := tailSplit(tailStr)
+	if len(whereStr) > 0 {
+		condStr = fmt.Sprintf("%s && (%s)", condStr, whereStr)
+	}
+	return "WHERE " + condStr + prePad(restStr)
+}
+
 func valueList(recVl reflect.Value, sfList []reflect.StructField) (list []reflect.Value) {
-	addr := recVl.UnsafeAddr()
-	var fldVl reflect.Value
 	for _, sf := range sfList {
-		fldVl = reflect.Indirect(reflect.NewAt(sf.Type, unsafe.Pointer(addr+sf.Offset)))
-		list = append(list, fldVl)
+		list = append(list, fieldValue(recVl, sf))
 	}
 	return
 }
@@ -347,8 +629,133 @@ func valList(recVl reflect.Value, sfList []reflect.StructField) (list []interfac
 	return
 }
 
+// tagOptMap contains the options that may follow the column name in a "ql"
+// tag.
+var tagOptMap = map[string]bool{
+	"autocreate": true,
+	"autoupdate": true,
+	"checksum":   true,
+	"collate":    true,
+	"default":    true,
+	"geohash":    true,
+	"json":       true,
+	"lat":        true,
+	"lon":        true,
+	"readonly":   true,
+	"softdelete": true,
+	"ttl":        true,
+	"type":       true,
+	"unique":     true,
+	"version":    true,
+}
+
+// tagParse splits the value of a "ql" tag into the column name and a map of
+// options. For example, "created,ttl=720h" results in "created" and
+// {"ttl":"720h"}. Commas within parentheses or string literals do not separate
+// options.
+func tagParse(tagStr string) (nameStr string, optMap map[string]string) {
+	optMap = make(map[string]string)
+	var partList []string
+	depth := 0
+	start := 0
+	var quote byte
+	for j := 0; j < len(tagStr); j++ {
+		ch := tagStr[j]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+		case ch == '(':
+			depth++
+		case ch == ')':
+			depth--
+		case ch == ',' && depth == 0:
+			partList = append(partList, tagStr[start:j])
+			start = j + 1
+		}
+	}
+	partList = append(partList, tagStr[start:])
+	nameStr = strings.TrimSpace(partList[0])
+	for _, optStr := range partList[1:] {
+		optStr = strings.TrimSpace(optStr)
+		if len(optStr) > 0 {
+			keyStr, valStr := optStr, ""
+			if pos := strings.Index(optStr, "="); pos >= 0 {
+				keyStr, valStr = optStr[:pos], optStr[pos+1:]
+			}
+			optMap[keyStr] = valStr
+		}
+	}
+	return
+}
+
+// idList returns the identifiers of the records in the table described by dsc
+// that satisfy tailStr and its parameters.
+func (db *DbType) idList(dsc qlDscType, tailStr string, prms ...interface{}) (list []int64) {
+	if db.err != nil {
+		return
+	}
+	rs, _ := db.Exec(fmt.Sprintf("SELECT id() FROM %s%s;", dsc.fromStr(), prePad(tailStr)), prms...)
+	for _, res := range rs {
+		if db.err == nil {
+			db.err = res.Do(false, func(data []interface{}) (bool, error) {
+				list = append(list, data[0].(int64))
+				return true, nil
+			})
+		}
+	}
+	return
+}
+
+// deleteIDs removes, in a single transaction, the records in the table
+// described by dsc that have the specified identifiers.
+func (db *DbType) deleteIDs(dsc qlDscType, idList []int64) {
+	if !db.writable(dsc) || len(idList) == 0 {
+		return
+	}
+	condStr, prms := inCond("id()", idList, 0)
+	db.TransactBegin()
+	if db.err == nil {
+		_, _ = db.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s;", dsc.tblStr, condStr), prms...)
+		db.journalIDs(dsc, ChangeDelete, idList)
+	}
+	db.transactEnd(db.err == nil)
+}
+
+// firstRow returns the values of the first row that results from the SELECT
+// statement cmdStr and its parameters. Nil is returned if there are no rows.
+func (db *DbType) firstRow(cmdStr string, prms ...interface{}) (row []interface{}) {
+	rs, _ := db.Exec(cmdStr, prms...)
+	if db.err == nil && len(rs) > 0 {
+		row, db.err = rs[len(rs)-1].FirstRow()
+	}
+	return
+}
+
+// qlTypeStr returns the name of the ql type that corresponds to the Go type
+// tp, for example "time" for time.Time.
+func qlTypeStr(tp reflect.Type) (typeStr string) {
+	typeStr = fmt.Sprintf("%v", tp)
+	switch typeStr {
+	case "time.Time":
+		typeStr = "time"
+	case "time.Duration":
+		typeStr = "duration"
+	case "big.Rat":
+		typeStr = "bigrat"
+	case "big.Int":
+		typeStr = "bigint"
+	case "[]uint8":
+		typeStr = "blob"
+	}
+	return
+}
+
 func idxListAppend(listPtr *[]idxType, nameStr, fldStr string) {
-	*listPtr = append(*listPtr, idxType{nameStr, fldStr})
+	*listPtr = append(*listPtr, idxType{nameStr: nameStr, fldStr: fldStr})
 }
 
 // dscFromType collects meta information, for example field types and SQL
@@ -357,21 +764,28 @@ func (db *DbType) dscFromType(recTp reflect.Type) (dsc qlDscType) {
 	if db.err != nil {
 		return
 	}
-	if recTp.Kind() == reflect.Struct {
+	if recTp != nil && recTp.Kind() == reflect.Struct {
 		var ok bool
-		dsc, ok = db.dscMap[recTp]
+		dsc, ok = db.dscCache.get(recTp)
+		if !ok && db.registryUsable() {
+			if dsc, ok = registryGet(recTp); ok {
+				dsc.view.selStr = db.viewMap[recTp]
+				db.dscCache.put(recTp, dsc) // cache
+			}
+		}
 		if !ok {
 			dsc.recTp = recTp
-			var sfList []reflect.StructField
 			var sqlStr, tblStr, typeStr string
+			var optMap map[string]string
 			var fldTp reflect.Type
 			var selList, qmList, createList []string
 			dsc.nameMap = make(map[string]reflect.StructField)
-			for j := 0; j < recTp.NumField(); j++ {
-				sfList = append(sfList, recTp.Field(j))
-			}
+			dsc.typeMap = make(map[string]string)
+			dsc.optMap = make(map[string]map[string]string)
+			dsc.json = make(map[string]bool)
+			dsc.valuer = make(map[string]bool)
 			var indexed bool
-			for _, sf := range sfList {
+			for _, sf := range embedFields(recTp) {
 				if db.err == nil {
 					indexed = len(sf.Tag.Get("ql_index")) > 0
 					// Note on indexes. In the future, if ql gains support for multi-field
@@ -381,47 +795,124 @@ func (db *DbType) dscFromType(recTp reflect.Type) (dsc qlDscType) {
 					// of the key will be determined by sorting the following text (here, "01"
 					// and "02", but any text could be used).
 					fldTp = sf.Type
-					sqlStr = sf.Tag.Get("ql")
-					if len(sqlStr) > 0 {
+					sqlStr, optMap = tagParse(qlTag(recTp, sf))
+					sqlStr = db.autoName(recTp, sf, sqlStr)
+					if strings.HasPrefix(sqlStr, exprTagPrefix) {
+						exprStr := strings.TrimSpace(sqlStr[len(exprTagPrefix):])
+						if db.exprSet(&dsc, sf, exprStr, optMap); db.err == nil {
+							selList = append(selList, exprStr)
+						}
+					} else if len(sqlStr) > 0 {
 						if sqlStr == "*" {
-							sqlStr = sf.Name
+							sqlStr = db.colName(recTp, sf)
 						}
-						typeStr = fmt.Sprintf("%v", fldTp)
-						switch typeStr {
-						case "time.Time":
-							typeStr = "time"
-						case "time.Duration":
-							typeStr = "duration"
-						case "big.Rat":
-							typeStr = "bigrat"
-						case "big.Int":
-							typeStr = "bigint"
-						case "[]uint8":
-							typeStr = "blob"
+						db.identCheck("column", sqlStr, "ql tag of field "+sf.Name)
+						for optStr := range optMap {
+							if !tagOptMap[optStr] {
+								db.SetErrorf("unknown option %s in ql tag of field %s", optStr, sf.Name)
+							}
+						}
+						if ttlStr, ok := optMap["ttl"]; ok {
+							db.ttlSet(&dsc, sf, sqlStr, ttlStr)
+						}
+						db.geoSet(&dsc, sf, sqlStr, optMap)
+						if modeStr, ok := optMap["collate"]; ok {
+							db.collateSet(&dsc, sf, sqlStr, modeStr)
+						}
+						if _, ok := optMap["checksum"]; ok {
+							db.sumSet(&dsc, sf, sqlStr)
+						}
+						if _, ok := optMap["version"]; ok {
+							db.versionSet(&dsc, sf, sqlStr)
+						}
+						if _, ok := optMap["readonly"]; ok {
+							db.readonlySet(sf, optMap)
+						}
+						typeStr = qlTypeStr(fldTp)
+						selTypeStr := typeStr
+						if _, ok := optMap["json"]; ok {
+							typeStr, selTypeStr = "blob", "json"
+							dsc.json[sqlStr] = true
+						} else if !typeMap[typeStr] {
+							if str := db.valuerSet(&dsc, sf, sqlStr, optMap); len(str) > 0 {
+								typeStr, selTypeStr = str, "valuer"
+							}
+						}
+						if _, ok := dsc.nameMap[sqlStr]; ok {
+							db.SetErrorf("duplicate column name %s", sqlStr)
+						} else if len(sf.PkgPath) > 0 {
+							db.SetErrorf("field %s must be exported", sf.Name)
 						}
 						dsc.nameMap[sqlStr] = sf
+						dsc.typeMap[sqlStr] = typeStr
+						if len(optMap) > 0 {
+							dsc.optMap[sqlStr] = optMap
+						}
 						strListAppend(&createList, "%s %s", sqlStr, typeStr)
-						if indexed {
+						if defStr, ok := optMap["default"]; ok {
+							if litStr := db.defaultSet(&dsc, sf, defStr); len(litStr) > 0 {
+								createList[len(createList)-1] += " DEFAULT " + litStr
+							}
+						}
+						if _, unique := optMap["unique"]; unique {
+							idxListAppend(&dsc.create.idxList, sf.Name, sqlStr)
+							dsc.create.idxList[len(dsc.create.idxList)-1].unique = true
+						} else if indexed {
 							idxListAppend(&dsc.create.idxList, sf.Name, sqlStr)
 						}
 						dsc.insert.sfList = append(dsc.insert.sfList, sf)
 						strListAppend(&dsc.insert.nameList, "%s", sqlStr)
 						strListAppend(&qmList, "?%d", len(dsc.insert.sfList))
-						strListAppend(&dsc.sel.typeStrList, "%s", typeStr)
+						strListAppend(&dsc.sel.typeStrList, "%s", selTypeStr)
 						strListAppend(&selList, "%s", sqlStr)
 						dsc.sel.sfList = append(dsc.sel.sfList, sf)
+						if len(sf.Index) > 1 && modelMap[recTp.FieldByIndex(sf.Index[:len(sf.Index)-1]).Type] {
+							switch sf.Name {
+							case "CreatedAt":
+								dsc.auto.createList = append(dsc.auto.createList, sf)
+							case "UpdatedAt":
+								dsc.auto.updateList = append(dsc.auto.updateList, sf)
+								dsc.auto.updateStr = append(dsc.auto.updateStr, sqlStr)
+							case "DeletedAt":
+								dsc.soft.nameStr = sqlStr
+							}
+						}
+						_, autoCreate := optMap["autocreate"]
+						_, autoUpdate := optMap["autoupdate"]
+						switch {
+						case (autoCreate || autoUpdate) && fldTp != reflect.TypeOf(time.Time{}):
+							db.SetErrorf("autocreate and autoupdate options require field %s to be of type time.Time", sf.Name)
+						case autoUpdate:
+							dsc.auto.updateList = append(dsc.auto.updateList, sf)
+							dsc.auto.updateStr = append(dsc.auto.updateStr, sqlStr)
+						case autoCreate:
+							dsc.auto.createList = append(dsc.auto.createList, sf)
+						}
+						if _, ok := optMap["softdelete"]; ok {
+							switch {
+							case fldTp != reflect.TypeOf(time.Time{}):
+								db.SetErrorf("softdelete option requires field %s to be of type time.Time", sf.Name)
+							case len(dsc.soft.nameStr) > 0:
+								db.SetErrorf("multiple soft-delete fields")
+							default:
+								dsc.soft.nameStr = sqlStr
+							}
+						}
 						if !typeMap[typeStr] {
 							db.SetErrorf("database does not support fields of type %s", typeStr)
 						}
 					} else {
 						tblStr = sf.Tag.Get("ql_table")
 						if len(tblStr) > 0 {
+							db.identCheck("table", tblStr, "ql_table tag of field "+sf.Name)
 							if len(dsc.tblStr) == 0 {
-								if fldTp.Kind() == reflect.Int64 {
+								if len(sf.PkgPath) > 0 && !fieldUnexported {
+									db.SetErrorf("ID field %s must be exported when built with the qlm_safe tag", sf.Name)
+								} else if fldTp.Kind() == reflect.Int64 {
 									strListAppend(&selList, "id()")
 									dsc.sel.sfList = append(dsc.sel.sfList, sf)
 									strListAppend(&dsc.sel.typeStrList, "%v", sf.Type.Kind())
-									dsc.tblStr = tblStr
+									dsc.tblStr = db.tblPrefix + tblStr
 									dsc.idSf = sf
 									if indexed {
 										idxListAppend(&dsc.create.idxList, sf.Name, "id()")
@@ -441,12 +932,32 @@ func (db *DbType) dscFromType(recTp reflect.Type) (dsc qlDscType) {
 					db.SetErrorf(`no structure fields have "ql" tag`)
 				} else if len(dsc.tblStr) == 0 {
 					db.SetErrorf(`missing "ql_table" tag`)
+				} else if (len(dsc.geo.latStr) > 0) != (len(dsc.geo.lonStr) > 0) ||
+					len(dsc.geo.hashStr) > 0 && len(dsc.geo.latStr) == 0 {
+					db.SetErrorf("lat and lon options must be used together")
 				} else {
+					for _, col := range dsc.collate {
+						strListAppend(&createList, "%s string", col.keyStr)
+						for _, idx := range dsc.create.idxList {
+							if idx.fldStr == col.nameStr {
+								idxListAppend(&dsc.create.idxList, col.sf.Name+"Collate", col.keyStr)
+								break
+							}
+						}
+					}
 					dsc.insert.qmStr = strings.Join(qmList, ", ")
 					dsc.insert.nameStr = strings.Join(dsc.insert.nameList, ", ")
 					dsc.create.nameTypeStr = strings.Join(createList, ", ")
 					dsc.sel.nameStr = strings.Join(selList, ", ")
-					db.dscMap[recTp] = dsc // cache
+					dsc.sel.nameList = selList
+					dsc.view.selStr = db.viewMap[recTp]
+					for _, sf := range dsc.insert.sfList {
+						if len(dsc.sum.nameStr) > 0 && sf.Offset != dsc.sum.sf.Offset {
+							dsc.sum.sfList = append(dsc.sum.sfList, sf)
+						}
+					}
+					dsc.hookSet()
+					db.dscCache.put(recTp, dsc) // cache
 					// dump(dsc)
 				}
 			}
@@ -455,6 +966,9 @@ func (db *DbType) dscFromType(recTp reflect.Type) (dsc qlDscType) {
 		db.SetErrorf(`specified address must be of structure with ` +
 			`one or more fields that have a "ql" tag`)
 	}
+	if tblStr, ok := db.tblMap[recTp]; ok && db.err == nil {
+		dsc.tblStr = db.tblPrefix + tblStr
+	}
 	return
 }
 
@@ -474,10 +988,52 @@ func strListAppend(listPtr *[]string, fmtStr string, args ...interface{}) {
 	*listPtr = append(*listPtr, fmt.Sprintf(fmtStr, args...))
 }
 
+// strListMerge returns aList with the elements of bList that are not already
+// in aList appended to it. aList itself is not modified.
+func strListMerge(aList, bList []string) (list []string) {
+	list = append(list, aList...)
+	for _, bStr := range bList {
+		found := false
+		for _, aStr := range aList {
+			found = found || aStr == bStr
+		}
+		if !found {
+			list = append(list, bStr)
+		}
+	}
+	return
+}
+
 // TableCreate creates a table and its associated indexes based strictly on the
 // "ql", "ql_table", and "ql_index" tags in the type definition of the
 // specified record. The table and indexes are overwritten if they already
 // exist.
+//
+// A field with the unique option in its tag, for example `ql:"*,unique"`, is
+// given a unique index whether or not it is named in the "ql_index" tag. An
+// insertion or update that would duplicate a value in such a column sets the
+// database error to a DuplicateError; see IsDuplicate.
+//
+// A field of a type that ql does not support, such as a map, a slice or a
+// nested structure, can be stored in a blob column by including the json
+// option in its tag, for example `ql:"payload,json"`. The field is encoded as
+// JSON when it is inserted or updated and decoded when it is retrieved.
+// Alternatively, a field type can convert itself by implementing Valuer, with
+// a pointer to it implementing Scanner. The ql type of such a column is that
+// of the value returned by the zero value of the field type unless it is
+// given with the type option, for example `ql:"*,type=string"`. The null
+// wrapper types of database/sql, such as sql.NullString and sql.NullTime, are
+// supported in this way; a value that is not valid is stored as NULL.
+//
+// A string, boolean or numeric field with the default option in its tag, for
+// example `ql:"status,default=active"`, is assigned the default value by
+// Insert if it is zero. The default is also recorded in the column
+// definition, so it applies to rows inserted into the table by other means.
+//
+// The autocreate and autoupdate options maintain time.Time fields in the
+// manner of the CreatedAt and UpdatedAt fields of Model, for example
+// `ql:"created,autocreate"` and `ql:"updated,autoupdate"`. Insert assigns the
+// current time to both if they are zero, and Update assigns it to the latter.
 func (db *DbType) TableCreate(recPtr interface{}) {
 	if db.err != nil {
 		return
@@ -490,7 +1046,7 @@ func (db *DbType) TableCreate(recPtr interface{}) {
 	// CREATE INDEX fooDate ON foo (Date);
 	var dsc qlDscType
 	dsc = db.dscFromPtr(recPtr)
-	if db.err == nil {
+	if db.writable(dsc) {
 		// Consider supporting flag that controls how existing table is handled
 		// (function fail or table overwritten)
 		db.TransactBegin()
@@ -501,66 +1057,122 @@ func (db *DbType) TableCreate(recPtr interface{}) {
 				cmd = fmt.Sprintf("DROP INDEX IF EXISTS %s%s;", dsc.tblStr, idx.nameStr)
 				_, _ = db.Exec(cmd)
 			}
-			if db.err == nil {
-				cmd = fmt.Sprintf("CREATE TABLE %s (%s);", dsc.tblStr, dsc.create.nameTypeStr)
-				// fmt.Printf("QL [%s]\n", cmd)
-				_, _ = db.Exec(cmd)
-				for _, idx := range dsc.create.idxList {
-					cmd = fmt.Sprintf("CREATE INDEX %s%s ON %s (%s);",
-						dsc.tblStr, idx.nameStr, dsc.tblStr, idx.fldStr)
-					// fmt.Printf("QL [%s]\n", cmd)
-					_, _ = db.Exec(cmd)
-				}
-			}
+			db.tableMake(dsc, false)
 		}
 		db.transactEnd(db.err == nil)
 	}
 	return
 }
 
+// tableMake issues the statements that create the table described by dsc and
+// its indexes. If ifNotExists is true, existing tables and indexes are left
+// intact. This method must be called within a transaction.
+func (db *DbType) tableMake(dsc qlDscType, ifNotExists bool) {
+	if db.err != nil {
+		return
+	}
+	ifStr := strIf(ifNotExists, " IF NOT EXISTS", "")
+	cmd := fmt.Sprintf("CREATE TABLE%s %s (%s);", ifStr, dsc.tblStr, dsc.create.nameTypeStr)
+	_, _ = db.Exec(cmd)
+	for _, idx := range dsc.create.idxList {
+		cmd = fmt.Sprintf("CREATE %sINDEX%s %s%s ON %s (%s);", strIf(idx.unique, "UNIQUE ", ""),
+			ifStr, dsc.tblStr, idx.nameStr, dsc.tblStr, idx.fldStr)
+		_, _ = db.Exec(cmd)
+	}
+}
+
+// TableEnsure creates the table and indexes associated with the type of the
+// record pointed to by recPtr if they do not already exist. Unlike
+// TableCreate, an existing table and the records it contains are left intact,
+// so this function can be called each time an application opens its database.
+// Note that an existing table is not altered to match the type definition.
+func (db *DbType) TableEnsure(recPtr interface{}) {
+	if db.err != nil {
+		return
+	}
+	dsc := db.dscFromPtr(recPtr)
+	if db.writable(dsc) {
+		db.TransactBegin()
+		db.tableMake(dsc, true)
+		db.transactEnd(db.err == nil)
+	}
+}
+
+// beforeInsert assigns the fields of the record recVl that are maintained by
+// qlm prior to its insertion. tm is the time of the insertion.
+func (db *DbType) beforeInsert(dsc qlDscType, recVl reflect.Value, tm time.Time) {
+	dsc.defaultAssign(recVl)
+	modelAssign(dsc, recVl, true, tm)
+	db.geoAssign(dsc, recVl)
+	dsc.sumAssign(recVl)
+}
+
+// beforeUpdate assigns the fields of the record recVl that are maintained by
+// qlm prior to an update of the columns in fldNames. The list of columns,
+// extended with the maintained columns that need to be stored, is returned.
+func (db *DbType) beforeUpdate(dsc qlDscType, recVl reflect.Value, fldNames []string) []string {
+	modelAssign(dsc, recVl, false, time.Now())
+	fldNames = strListMerge(fldNames, dsc.auto.updateStr)
+	if len(dsc.geo.hashStr) > 0 {
+		for _, nm := range fldNames {
+			if nm == dsc.geo.latStr || nm == dsc.geo.lonStr {
+				db.geoAssign(dsc, recVl)
+				fldNames = strListMerge(fldNames, []string{dsc.geo.hashStr})
+				break
+			}
+		}
+	}
+	if len(dsc.sum.nameStr) > 0 {
+		dsc.sumAssign(recVl)
+		fldNames = strListMerge(fldNames, []string{dsc.sum.nameStr})
+	}
+	return fldNames
+}
+
+// storeVal returns the value to be passed to ql for the column nameStr of the
+// table described by dsc given the field value val.
+func (db *DbType) storeVal(dsc qlDscType, nameStr string, val interface{}) interface{} {
+	if nameStr == dsc.soft.nameStr && val.(time.Time).IsZero() {
+		return nil // Stored as NULL
+	}
+	if dsc.json[nameStr] {
+		return db.jsonStore(nameStr, val)
+	}
+	if dsc.valuer[nameStr] {
+		return db.valuerStore(nameStr, val)
+	}
+	return val
+}
+
 // Update updates the specified record in the database. The ID field (tagged
 // with "ql_table" in the structure definition) is used to identify the record
 // in the table. It must have the same value as it had when the record was
 // retrieved from the database using Retrieve. fldNames specify the fields that
 // will be updated. The field names are the ones used in the database, that is,
 // the names identified with the "ql" tag in the structure definition. If the
-// first string is "*", all fields are updated. Unmatched field names result in
-// an error.
+// first string is "*", all fields are updated except those with the readonly
+// option in their tag, for example `ql:"*,readonly"`; naming such a field
+// explicitly is an error. Unmatched field names result in an error.
+//
+// If the record type has an int64 field with the version option in its tag,
+// for example `ql:"ver,version"`, the field is incremented and stored with the
+// other fields, but only if the stored version matches the version of the
+// record before the increment. Otherwise, nothing is updated, the field is
+// left unchanged, and the qlm error is set to ErrStaleRecord.
 func (db *DbType) Update(recPtr interface{}, fldNames ...string) {
 	if db.err != nil {
 		return
 	}
-	// UPDATE foo name = ?1, num = ?2 WHERE id() == ?3;
 	if len(fldNames) > 0 {
 		var dsc qlDscType
 		dsc = db.dscFromPtr(recPtr)
-		if db.err == nil {
-			recVl := reflect.ValueOf(recPtr).Elem()
-			addr := recVl.UnsafeAddr()
-			var args []interface{}
-			var eqList []string
-			var sf reflect.StructField
-			if fldNames[0] == "*" {
-				fldNames = dsc.insert.nameList
-			}
-			pos := 0
-			for _, nm := range fldNames {
-				// fmt.Printf("sf.Name [%s], %v\n", sf.Name, fldMap[sf.Name])
-				pos++
-				sf = dsc.nameMap[nm]
-				strListAppend(&eqList, "%s = ?%d", nm, pos)
-				args = append(args, reflect.Indirect(
-					reflect.NewAt(sf.Type, unsafe.Pointer(addr+sf.Offset))).Interface())
-			}
-			args = append(args, reflect.Indirect(
-				reflect.NewAt(dsc.idSf.Type, unsafe.Pointer(addr+dsc.idSf.Offset))).Interface())
+		if db.writable(dsc) {
 			db.TransactBegin()
-			if db.err == nil {
-				cmd := fmt.Sprintf("UPDATE %s %s WHERE id() == ?%d;", dsc.tblStr,
-					strings.Join(eqList, ", "), pos+1)
-				_, _ = db.Exec(cmd, args...)
-			}
+			restore := db.updateRec(dsc, reflect.ValueOf(recPtr).Elem(), fldNames)
 			db.transactEnd(db.err == nil)
+			if db.err != nil {
+				restore()
+			}
 		}
 	} else {
 		db.SetErrorf("at least one field name expected in function Update")
@@ -568,9 +1180,65 @@ func (db *DbType) Update(recPtr interface{}, fldNames ...string) {
 	return
 }
 
+// updateRec stores the fields named in fldNames of the record recVl described
+// by dsc. The returned function restores the fields that were changed by qlm
+// in preparation for the update, such as the version field, in case the
+// transaction is rolled back. This method must be called within a
+// transaction.
+func (db *DbType) updateRec(dsc qlDscType, recVl reflect.Value, fldNames []string) (restore func()) {
+	restore = func() {}
+	if db.err != nil {
+		return
+	}
+	// UPDATE foo name = ?1, num = ?2 WHERE id() == ?3;
+	var args []interface{}
+	var eqList []string
+	var sf reflect.StructField
+	if fldNames = db.updateNames(dsc, fldNames, "Update"); db.err != nil {
+		return
+	}
+	if db.hookBeforeUpdate(dsc, recVl); db.err != nil {
+		return
+	}
+	restore = dsc.versionNext(recVl)
+	if len(dsc.version.nameStr) > 0 {
+		fldNames = strListMerge(fldNames, []string{dsc.version.nameStr})
+	}
+	fldNames = db.beforeUpdate(dsc, recVl, fldNames)
+	pos := 0
+	for _, nm := range fldNames {
+		// fmt.Printf("sf.Name [%s], %v\n", sf.Name, fldMap[sf.Name])
+		pos++
+		sf = dsc.nameMap[nm]
+		strListAppend(&eqList, "%s = ?%d", nm, pos)
+		args = append(args, db.storeVal(dsc, nm, fieldValue(recVl, sf).Interface()))
+	}
+	eqList, args = dsc.collateUpdate(recVl, fldNames, eqList, args)
+	pos = len(args)
+	args = append(args, fieldValue(recVl, dsc.idSf).Interface())
+	var verStr string
+	if len(dsc.version.nameStr) > 0 {
+		// ... WHERE id() == ?3 && ver == ?4;
+		args = append(args, valList(recVl, []reflect.StructField{dsc.version.sf})[0].(int64)-1)
+		verStr = fmt.Sprintf(" && %s == ?%d", dsc.version.nameStr, len(args))
+	}
+	cmd := fmt.Sprintf("UPDATE %s %s WHERE id() == ?%d%s;", dsc.tblStr,
+		strings.Join(eqList, ", "), pos+1, verStr)
+	_, _ = db.Exec(cmd, args...)
+	if db.err == nil && len(verStr) > 0 && db.transact.ctx.RowsAffected == 0 {
+		db.SetError(ErrStaleRecord)
+	}
+	db.journalIDs(dsc, ChangeUpdate, []int64{args[pos].(int64)}, fldNames...)
+	return
+}
+
 // Delete removes all records from the database that satisfy the specified tail
 // clause and its arguments. For example, if tailStr is empty, all records from
-// the table will be deleted.
+// the table will be deleted. If the record type embeds SoftModel, the records
+// are not removed; instead, their DeletedAt field is set to the current time.
+// A time.Time field with the softdelete option in its tag, for example
+// `ql:"deleted_at,softdelete"`, is used in the same way. Use DeleteHard() to
+// remove the records of such a type.
 func (db *DbType) Delete(recPtr interface{}, tailStr string, prms ...interface{}) {
 	if db.err != nil {
 		return
@@ -578,11 +1246,55 @@ func (db *DbType) Delete(recPtr interface{}, tailStr string, prms ...interface{}
 	// DELETE FROM foo WHERE a > ?1 AND b < ?2
 	var dsc qlDscType
 	dsc = db.dscFromPtr(recPtr)
-	if db.err == nil {
+	if db.writable(dsc) {
 		db.TransactBegin()
 		if db.err == nil {
-			cmd := fmt.Sprintf("DELETE FROM %s%s;", dsc.tblStr, prePad(tailStr))
+			var cmd string
+			var idList []int64
+			if db.tracked(dsc) || len(dsc.soft.nameStr) > 0 && len(dsc.sum.nameStr) > 0 {
+				idList = db.idList(dsc, strIf(len(dsc.soft.nameStr) > 0,
+					whereAnd(tailStr, dsc.soft.nameStr+" IS NULL"), tailStr), prms...)
+			}
+			if len(dsc.soft.nameStr) > 0 {
+				// UPDATE foo DeletedAt = ?3 WHERE DeletedAt IS NULL && (a > ?1 AND b < ?2)
+				prms = append(prms, time.Now())
+				cmd = fmt.Sprintf("UPDATE %s %s = ?%d %s;", dsc.tblStr, dsc.soft.nameStr,
+					len(prms), whereAnd(tailStr, dsc.soft.nameStr+" IS NULL"))
+			} else {
+				cmd = fmt.Sprintf("DELETE FROM %s%s;", dsc.tblStr, prePad(tailStr))
+			}
 			_, _ = db.Exec(cmd, prms...)
+			if len(dsc.soft.nameStr) > 0 {
+				db.derivedRepair(dsc, idList)
+			}
+			db.journalIDs(dsc, ChangeDelete, idList)
+		}
+		db.transactEnd(db.err == nil)
+	}
+}
+
+// DeleteHard removes all records from the database that satisfy the specified
+// tail clause and its arguments, including the records of a soft-delete
+// table that have or have not been marked as deleted. For other tables, it is
+// equivalent to Delete().
+func (db *DbType) DeleteHard(recPtr interface{}, tailStr string, prms ...interface{}) {
+	if db.err != nil {
+		return
+	}
+	dsc := db.dscFromPtr(recPtr)
+	if db.writable(dsc) {
+		if len(dsc.soft.nameStr) == 0 {
+			db.Delete(recPtr, tailStr, prms...)
+			return
+		}
+		db.TransactBegin()
+		if db.err == nil {
+			var idList []int64
+			if db.tracked(dsc) {
+				idList = db.idList(dsc, tailStr, prms...)
+			}
+			_, _ = db.Exec(fmt.Sprintf("DELETE FROM %s%s;", dsc.tblStr, prePad(tailStr)), prms...)
+			db.journalIDs(dsc, ChangeDelete, idList)
 		}
 		db.transactEnd(db.err == nil)
 	}
@@ -597,11 +1309,18 @@ func (db *DbType) Truncate(recPtr interface{}) {
 	// TRUNCATE TABLE foo;
 	var dsc qlDscType
 	dsc = db.dscFromPtr(recPtr)
-	if db.err == nil {
+	if db.writable(dsc) {
 		db.TransactBegin()
 		if db.err == nil {
 			cmd := fmt.Sprintf("TRUNCATE TABLE %s;", dsc.tblStr)
 			_, _ = db.Exec(cmd)
+			if db.journaled(dsc) {
+				db.journalRec(dsc, ChangeTruncate, 0, reflect.Value{})
+			}
+			if db.audited(dsc) {
+				db.auditRec(dsc, ChangeTruncate, 0, reflect.Value{}, nil)
+			}
+			db.notifyQueue(dsc, ChangeTruncate, 0)
 		}
 		db.transactEnd(db.err == nil)
 	}
@@ -610,11 +1329,16 @@ func (db *DbType) Truncate(recPtr interface{}) {
 // Insert stores in the database the records included in the specified slice.
 // The value of the ID field that is tagged with "ql_table" is ignored. After
 // this function returns, the ID field of each inserted record will contain the
-// indentifier assigned by the database.
+// identifier assigned by the database. Since the elements of the slice are
+// modified in place, the identifiers are available to the caller without a
+// subsequent call to Retrieve. The records are inserted within a single
+// transaction using statements that each store up to the number of records
+// established with InsertChunk().
 func (db *DbType) Insert(slice interface{}) {
 	if db.err != nil {
 		return
 	}
+	db.expireCheck()
 	var dsc qlDscType
 	var vList []interface{}
 	sliceVl := reflect.ValueOf(slice)
@@ -623,83 +1347,155 @@ func (db *DbType) Insert(slice interface{}) {
 		count := sliceVl.Len()
 		recTp := sliceTp.Elem()
 		dsc = db.dscFromType(recTp)
-		if db.err == nil {
-			cmdStr := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s);",
-				dsc.tblStr, dsc.insert.nameStr, dsc.insert.qmStr)
-			// fmt.Printf("QL [%s]\n", cmdStr)
-			var idVal, recVl reflect.Value
+		if db.writable(dsc) {
+			var recVl reflect.Value
+			tm := time.Now()
+			trk := db.progressStart("Insert", int64(count))
 			db.TransactBegin()
-			for recJ := 0; recJ < count && db.err == nil; recJ++ { // Record loop
-				recVl = sliceVl.Index(recJ)
-				vList = valList(recVl, dsc.insert.sfList)
-				_, _ = db.Exec(cmdStr, vList...)
-				idVal = reflect.Indirect(reflect.NewAt(dsc.idSf.Type,
-					unsafe.Pointer(recVl.UnsafeAddr()+dsc.idSf.Offset)))
-				idVal.SetInt(db.transact.ctx.LastInsertID)
+			for startJ := 0; startJ < count && db.err == nil; startJ += db.insertChunk { // Chunk loop
+				endJ := startJ + db.insertChunk
+				if endJ > count {
+					endJ = count
+				}
+				vList = vList[:0]
+				for recJ := startJ; recJ < endJ && db.err == nil; recJ++ {
+					recVl = sliceVl.Index(recJ)
+					if db.hookBeforeInsert(dsc, recVl); db.err == nil {
+						db.beforeInsert(dsc, recVl, tm)
+						for j, v := range valList(recVl, dsc.insert.sfList) {
+							if dsc.readonlyInsert(dsc.insert.nameList[j]) {
+								v = nil // Assigned by the database
+							} else {
+								v = db.storeVal(dsc, dsc.insert.nameList[j], v)
+							}
+							vList = append(vList, v)
+						}
+						vList = append(vList, dsc.collateVals(recVl)...)
+					}
+				}
+				if db.err != nil {
+					break
+				}
+				_, _ = db.Exec(insertCmd(dsc, endJ-startJ), vList...)
+				// ql assigns consecutive identifiers to the records of a statement
+				id := db.transact.ctx.LastInsertID - int64(endJ-startJ)
+				for recJ := startJ; recJ < endJ && db.err == nil; recJ++ { // Record loop
+					recVl = sliceVl.Index(recJ)
+					id++
+					fieldValue(recVl, dsc.idSf).SetInt(id)
+					if db.journaled(dsc) {
+						db.journalRec(dsc, ChangeInsert, id, recVl)
+					}
+					if db.audited(dsc) {
+						db.auditRec(dsc, ChangeInsert, id, recVl, nil)
+					}
+					db.notifyQueue(dsc, ChangeInsert, id)
+					db.hookAfterInsert(dsc, recVl)
+					if db.err == nil {
+						trk.add(1)
+					}
+				}
 			}
 			db.transactEnd(db.err == nil)
+			if db.err == nil {
+				trk.end()
+			}
 		}
 	} else {
 		db.SetErrorf("function Insert requires slice as first argument")
 	}
 }
 
+// insertCmd returns the statement that inserts count records described by
+// dsc. The parameters of each record are numbered consecutively, beginning
+// with the insertion columns followed by the collation columns.
+func insertCmd(dsc qlDscType, count int) string {
+	nameStr := dsc.insert.nameStr
+	for _, col := range dsc.collate {
+		nameStr += ", " + col.keyStr
+	}
+	colCount := len(dsc.insert.nameList) + len(dsc.collate)
+	rowList := make([]string, count)
+	qmList := make([]string, colCount)
+	for recJ := range rowList {
+		for j := range qmList {
+			qmList[j] = fmt.Sprintf("?%d", recJ*colCount+j+1)
+		}
+		rowList[recJ] = "(" + strings.Join(qmList, ", ") + ")"
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES %s;", dsc.tblStr, nameStr, strings.Join(rowList, ", "))
+}
+
+// InsertChunk sets to count the maximum number of records that Insert()
+// stores with each statement. Storing many records with one statement reduces
+// the overhead of bulk loads considerably. The default is 500. A value of 1
+// inserts each record with its own statement.
+func (db *DbType) InsertChunk(count int) {
+	if db.err == nil {
+		if count < 1 {
+			count = 1
+		}
+		db.insertChunk = count
+	}
+}
+
 // Retrieve selects zero or more records of the type pointed to by slicePtr
 // from the database. The retrieved records are appended to the slice. If the
 // retrieved records are to repopulate the slice instead, assign nil to the
 // slice prior to calling this function. tailStr is intended to include a WHERE
 // clause. For every parameter token ("?1", "?2", etc) in the string, a
 // suitable expression list (one-based) after the tail string should be passed.
+// Alternatively, parameters can be named; see Args. Records that have been
+// deleted from a table whose type embeds SoftModel, or has a field with the
+// softdelete option, are excluded; see RetrieveDeleted().
+//
+// ql orders strings byte by byte. A string field can instead be ordered
+// without regard to case, or with embedded numbers compared by value, by
+// including the collate option in its tag, for example `ql:"*,collate=fold"`,
+// `ql:"*,collate=natural"` or `ql:"*,collate=fold+natural"`. qlm maintains a
+// normalized copy of such a column and uses it wherever the column appears in
+// the ORDER BY clause of tailStr. In the WHERE clause of tailStr, comparisons
+// such as "Name == ?1" or "Name < \"m\"" of such a column with a string
+// parameter or literal use the normalized copy too, so they respect the
+// collation. Any other use of the column in the WHERE clause, for example in
+// a LIKE expression, sets an error. Locale-aware collation, which would order
+// "ä" with "a" in German, for example, is not supported.
 func (db *DbType) Retrieve(slicePtr interface{}, tailStr string, prms ...interface{}) {
+	db.retrieve(slicePtr, false, nil, tailStr, prms...)
+}
+
+// RetrieveDeleted is like Retrieve() except that it selects only the records
+// that have been marked as deleted by Delete() in a table whose type embeds
+// SoftModel or has a field with the softdelete option. No records are
+// selected from other tables.
+func (db *DbType) RetrieveDeleted(slicePtr interface{}, tailStr string, prms ...interface{}) {
+	db.retrieve(slicePtr, true, nil, tailStr, prms...)
+}
+
+// retrieve implements Retrieve() and, if deleted is true, RetrieveDeleted().
+// If colList is not nil, only the columns it names are selected; see
+// RetrieveFields().
+func (db *DbType) retrieve(slicePtr interface{}, deleted bool, colList []string, tailStr string, prms ...interface{}) {
 	if db.err != nil {
 		return
 	}
-	var dsc qlDscType
 	slicePtrVl := reflect.ValueOf(slicePtr)
 	kd := slicePtrVl.Kind()
 	if kd == reflect.Ptr {
 		sliceVl := reflect.Indirect(slicePtrVl)
 		kd = sliceVl.Kind()
 		if kd == reflect.Slice {
-			sliceTp := sliceVl.Type()
-			recTp := sliceTp.Elem()
-			dsc = db.dscFromType(recTp)
+			dsc := db.dscFromType(sliceVl.Type().Elem())
+			if colList != nil {
+				dsc = db.project(dsc, colList)
+			}
+			db.retrieveEach(dsc, deleted, func(recVl reflect.Value) error {
+				sliceVl = reflect.Append(sliceVl, recVl)
+				return nil
+			}, tailStr, prms...)
 			if db.err == nil {
-				cmdStr := fmt.Sprintf("SELECT %s FROM %s%s;",
-					dsc.sel.nameStr, dsc.tblStr, prePad(tailStr))
-				// fmt.Printf("QL [%s]\n", cmdStr)
-				var rs []ql.Recordset
-				rs, _ = db.Exec(cmdStr, prms...)
-				if db.err == nil {
-					recVl := reflect.Indirect(reflect.New(recTp)) // Buffer
-					vList := valueList(recVl, dsc.sel.sfList)
-					var v reflect.Value
-					load := func(data []interface{}) (more bool, err error) {
-						for j, f := range data {
-							switch dsc.sel.typeStrList[j] {
-							case "bigrat", "bigint":
-								v = reflect.Indirect(reflect.ValueOf(f))
-							default:
-								v = reflect.ValueOf(f)
-							}
-							// fmt.Printf("%2d: %s [%v] %v\n", j, dsc.fld.nameList[j], vList[j], f)
-							vList[j].Set(v)
-						}
-						// dump("result", data)
-						sliceVl = reflect.Append(sliceVl, recVl)
-						more = true
-						return
-					}
-					for _, res := range rs {
-						if db.err == nil {
-							db.err = res.Do(false, load)
-						}
-					}
-					if db.err == nil {
-						// Assign sliceVl back to *slicePtr
-						reflect.Indirect(slicePtrVl).Set(sliceVl)
-					}
-				}
+				// Assign sliceVl back to *slicePtr
+				reflect.Indirect(slicePtrVl).Set(sliceVl)
 			}
 		} else {
 			db.SetErrorf("function Retrieve expecting pointer to slice, got pointer to %v", kd)
@@ -710,3 +1506,80 @@ func (db *DbType) Retrieve(slicePtr interface{}, tailStr string, prms ...interfa
 	}
 	return
 }
+
+// retrieveEach selects the records described by dsc that satisfy tailStr and
+// its parameters and passes each one in turn to fn. The record passed to fn
+// is a buffer that is overwritten by the next record, so fn must copy it if
+// it is to be retained. If fn returns an error, no further records are loaded
+// and the error is assigned to the database. The deleted argument is handled
+// as it is in retrieve().
+func (db *DbType) retrieveEach(dsc qlDscType, deleted bool, fn func(recVl reflect.Value) error,
+	tailStr string, prms ...interface{}) {
+	if db.err != nil {
+		return
+	}
+	switch {
+	case len(dsc.soft.nameStr) > 0:
+		tailStr = whereAnd(tailStr, dsc.soft.nameStr+strIf(deleted, " IS NOT NULL", " IS NULL"))
+	case deleted:
+		tailStr = whereAnd(tailStr, "false")
+	}
+	tailStr, prms = db.collateTail(dsc, tailStr, prms)
+	cmdStr := fmt.Sprintf("SELECT %s%s FROM %s%s;", dsc.sel.nameStr,
+		dsc.collateSel(), dsc.fromStr(), prePad(tailStr))
+	// fmt.Printf("QL [%s]\n", cmdStr)
+	var rs []ql.Recordset
+	rs, _ = db.Exec(cmdStr, prms...)
+	if db.err == nil {
+		recVl := reflect.Indirect(reflect.New(dsc.recTp)) // Buffer
+		vList := valueList(recVl, dsc.sel.sfList)
+		var v reflect.Value
+		load := func(data []interface{}) (more bool, err error) {
+			if db.ctx != nil {
+				if err = db.ctx.Err(); err != nil {
+					return
+				}
+			}
+			for j, f := range data[:len(vList)] {
+				switch {
+				case dsc.sel.typeStrList[j] == "expr":
+					if err = assignVal(vList[j], f); err != nil {
+						return
+					}
+					continue
+				case dsc.sel.typeStrList[j] == "valuer":
+					if _, err = scannerLoad(vList[j], f); err != nil {
+						return
+					}
+					continue
+				case f == nil: // NULL
+					v = reflect.Zero(vList[j].Type())
+				case dsc.sel.typeStrList[j] == "bigrat", dsc.sel.typeStrList[j] == "bigint":
+					v = reflect.Indirect(reflect.ValueOf(f))
+				case dsc.sel.typeStrList[j] == "json":
+					if v, err = jsonLoad(vList[j].Type(), f); err != nil {
+						return
+					}
+				default:
+					v = reflect.ValueOf(f)
+				}
+				// fmt.Printf("%2d: %s [%v] %v\n", j, dsc.fld.nameList[j], vList[j], f)
+				vList[j].Set(v)
+			}
+			// dump("result", data)
+			if err = dsc.sumVerify(recVl); err == nil {
+				err = dsc.hookAfterRetrieve(recVl)
+			}
+			if err == nil {
+				err = fn(recVl)
+			}
+			more = err == nil
+			return
+		}
+		for _, res := range rs {
+			if db.err == nil {
+				db.err = res.Do(false, load)
+			}
+		}
+	}
+}
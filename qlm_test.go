@@ -24,6 +24,7 @@ import (
 	"io/ioutil"
 	"math/big"
 	"os"
+	"strings"
 	"time"
 )
 
@@ -436,6 +437,7 @@ func ExampleDbType_09() {
 	}
 	db.TableCreate(&eType{})
 	report()
+	db.Close()
 	// Output:
 	// application error
 	// application error
@@ -455,3 +457,161 @@ func ExampleDbType_09() {
 	// multiple occurrence of ql_table tag
 	// missing "ql_table" tag
 }
+
+// This example demonstrates that Insert assigns the identifiers of the new
+// records to the elements of the passed-in slice.
+func ExampleDbType_10() {
+	type recType struct {
+		ID   int64  `ql_table:"rec"`
+		Name string `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&recType{})
+	list := []recType{{0, "alpha"}, {0, "beta"}, {0, "gamma"}}
+	db.Insert(list)
+	for _, r := range list {
+		var rs []recType
+		db.Retrieve(&rs, "WHERE id() == ?1", r.ID)
+		if len(rs) == 1 {
+			fmt.Printf("%v %s\n", r.ID > 0, rs[0].Name)
+		}
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// true alpha
+	// true beta
+	// true gamma
+}
+
+// This example demonstrates that TableEnsure, unlike TableCreate, preserves
+// an existing table.
+func ExampleDbType_11() {
+	type recType struct {
+		ID   int64  `ql_table:"rec" ql_index:"*"`
+		Name string `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableEnsure(&recType{})
+	db.Insert([]recType{{0, "alpha"}, {0, "beta"}})
+	db.Close()
+	db = qlm.DbOpen("data/example.ql")
+	db.TableEnsure(&recType{})
+	var list []recType
+	db.Retrieve(&list, "ORDER BY Name")
+	for _, r := range list {
+		fmt.Println(r.Name)
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// alpha
+	// beta
+}
+
+// This example demonstrates the insertion of many records with multi-row
+// statements.
+func ExampleDbType_12() {
+	type recType struct {
+		ID   int64  `ql_table:"rec"`
+		Name string `ql:"*"`
+		Num  int64  `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&recType{})
+	db.InsertChunk(500)
+	list := make([]recType, 1200)
+	for j := range list {
+		list[j].Name = fmt.Sprintf("rec %04d", j)
+		list[j].Num = int64(j)
+	}
+	db.StatsReset()
+	db.Insert(list)
+	var count int64
+	for _, st := range db.Stats().List {
+		if strings.HasPrefix(st.Cmd, "INSERT INTO rec ") {
+			count += st.Count
+		}
+	}
+	fmt.Println(count)
+	var rec recType
+	db.RetrieveOne(&rec, "WHERE id() == ?1", list[777].ID)
+	fmt.Println(rec.Name, rec.Num, list[777].ID-list[0].ID)
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// 3
+	// rec 0777 777 777
+}
+
+// This example demonstrates opening a database with ql options.
+func ExampleDbOpenOptions() {
+	type recType struct {
+		ID   int64  `ql_table:"rec"`
+		Name string `ql:"*"`
+	}
+	fileStr := "data/options/example.ql"
+	os.RemoveAll("data/options")
+	db := qlm.DbOpenOptions(fileStr, &ql.Options{CanCreate: true, RemoveEmptyWAL: true})
+	db.TableCreate(&recType{})
+	db.Insert([]recType{{0, "alpha"}})
+	db.Close()
+	db = qlm.DbOpenOptions(fileStr, &ql.Options{RemoveEmptyWAL: true})
+	var list []recType
+	db.Retrieve(&list, "")
+	fmt.Println(len(list), list[0].Name)
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	db = qlm.DbCreateOptions(fileStr, &ql.Options{RemoveEmptyWAL: true})
+	db.TableEnsure(&recType{})
+	list = nil
+	db.Retrieve(&list, "")
+	fmt.Println(len(list))
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// 1 alpha
+	// 0
+}
+
+// This example demonstrates the creation of a database only if it does not
+// already exist.
+func ExampleDbCreateIfMissing() {
+	type recType struct {
+		ID   int64  `ql_table:"rec"`
+		Name string `ql:"*"`
+	}
+	os.RemoveAll("data/missing")
+	for _, nameStr := range []string{"alpha", "beta"} {
+		db := qlm.DbCreateIfMissing("data/missing/example.ql")
+		db.TableEnsure(&recType{})
+		db.Insert([]recType{{0, nameStr}})
+		db.Close()
+		if db.Err() {
+			fmt.Println(db.Error())
+		}
+	}
+	db := qlm.DbOpen("data/missing/example.ql")
+	var list []recType
+	db.Retrieve(&list, "ORDER BY Name")
+	for _, rec := range list {
+		fmt.Println(rec.Name)
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// alpha
+	// beta
+}
@@ -0,0 +1,189 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// QueryType assembles the tail clause and parameter list of a retrieval. It
+// is obtained with DbType.Query() and configured with chained calls, for
+// example
+//
+//	db.Query(&recType{}).Where("Num > ?", n).OrderBy("Name").Limit(10).Fetch(&list)
+type QueryType struct {
+	db        *DbType
+	recPtr    interface{}
+	whereList []string
+	prms      []interface{}
+	orderList []string
+	limit     int64
+	offset    int64
+}
+
+// Query returns a query on the table of the record type pointed to by
+// recPtr. Statements that are not limited to the columns of a single table,
+// such as joins, can be run with RawRetrieve().
+func (db *DbType) Query(recPtr interface{}) *QueryType {
+	return &QueryType{db: db, recPtr: recPtr, limit: -1}
+}
+
+// prmRenumber returns condStr with its parameter tokens renumbered to follow
+// the base parameters already in use. A bare "?" refers to the next
+// parameter of the condition and "?n" refers to its nth (one-based)
+// parameter. Tokens within string literals are not changed. The number of
+// parameters referenced by the condition is returned in count.
+func prmRenumber(condStr string, base int) (outStr string, count int) {
+	var buf strings.Builder
+	var quote byte
+	next := 0
+	for j := 0; j < len(condStr); j++ {
+		ch := condStr[j]
+		switch {
+		case quote != 0:
+			if ch == '\\' && quote == '"' && j+1 < len(condStr) {
+				buf.WriteByte(ch)
+				j++
+				ch = condStr[j]
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '`':
+			quote = ch
+		case ch == '?':
+			k := j + 1
+			for k < len(condStr) && condStr[k] >= '0' && condStr[k] <= '9' {
+				k++
+			}
+			var n int
+			if k > j+1 {
+				n, _ = strconv.Atoi(condStr[j+1 : k])
+				j = k - 1
+			} else {
+				next++
+				n = next
+			}
+			if n > count {
+				count = n
+			}
+			fmt.Fprintf(&buf, "?%d", base+n)
+			continue
+		}
+		buf.WriteByte(ch)
+	}
+	outStr = buf.String()
+	return
+}
+
+// Where adds the condition condStr to the query. Conditions from multiple
+// calls are joined with "&&". Each "?" in condStr refers to the next value in
+// prms; "?1", "?2", etc. may be used instead to refer to the values of prms
+// by position. In either case, the tokens are renumbered as needed when the
+// tail clause is assembled. Named parameters (see Args) may be used as well.
+func (q *QueryType) Where(condStr string, prms ...interface{}) *QueryType {
+	condStr, count := prmRenumber(condStr, len(q.prms))
+	named := 0
+	for _, prm := range prms {
+		if _, ok := prm.(Args); ok {
+			named++
+		}
+	}
+	if count+named != len(prms) {
+		q.db.SetErrorf("condition \"%s\" expects %d parameter(s), got %d", condStr, count, len(prms))
+	}
+	q.whereList = append(q.whereList, condStr)
+	q.prms = append(q.prms, prms...)
+	return q
+}
+
+// OrderBy adds the specified fields to the ORDER BY clause of the query. A
+// field may be followed by "DESC", for example "Name DESC".
+func (q *QueryType) OrderBy(fldList ...string) *QueryType {
+	q.orderList = append(q.orderList, fldList...)
+	return q
+}
+
+// Limit restricts the query to at most count records.
+func (q *QueryType) Limit(count int64) *QueryType {
+	q.limit = count
+	return q
+}
+
+// Offset skips the first count records that satisfy the query.
+func (q *QueryType) Offset(count int64) *QueryType {
+	q.offset = count
+	return q
+}
+
+// Tail returns the tail clause and parameter list that have been assembled
+// for the query. These are suitable for passing to the functions of DbType
+// that accept a tail clause.
+func (q *QueryType) Tail() (tailStr string, prms []interface{}) {
+	var list []string
+	switch len(q.whereList) {
+	case 0:
+	case 1:
+		list = append(list, "WHERE "+q.whereList[0])
+	default:
+		list = append(list, "WHERE ("+strings.Join(q.whereList, ") && (")+")")
+	}
+	if len(q.orderList) > 0 {
+		list = append(list, "ORDER BY "+strings.Join(q.orderList, ", "))
+	}
+	if q.limit >= 0 {
+		list = append(list, fmt.Sprintf("LIMIT %d", q.limit))
+	}
+	if q.offset > 0 {
+		list = append(list, fmt.Sprintf("OFFSET %d", q.offset))
+	}
+	return strings.Join(list, " "), q.prms
+}
+
+// Fetch appends the records that satisfy the query to the slice pointed to by
+// slicePtr; see DbType.Retrieve().
+func (q *QueryType) Fetch(slicePtr interface{}) {
+	tailStr, prms := q.Tail()
+	q.db.Retrieve(slicePtr, tailStr, prms...)
+}
+
+// First fills the record pointed to by recPtr with the first record that
+// satisfies the query; see DbType.RetrieveOne(). The query is limited to one
+// record, after any offset, without modifying q.
+func (q *QueryType) First(recPtr interface{}) bool {
+	fq := *q
+	if fq.limit < 0 || fq.limit > 1 {
+		fq.limit = 1
+	}
+	tailStr, prms := fq.Tail()
+	return q.db.RetrieveOne(recPtr, tailStr, prms...)
+}
+
+// Exists returns true if at least one record satisfies the query; see
+// DbType.Exists().
+func (q *QueryType) Exists() bool {
+	tailStr, prms := q.Tail()
+	return q.db.Exists(q.recPtr, tailStr, prms...)
+}
+
+// Delete deletes the records that satisfy the conditions of the query; see
+// DbType.Delete().
+func (q *QueryType) Delete() {
+	tailStr, prms := q.Tail()
+	q.db.Delete(q.recPtr, tailStr, prms...)
+}
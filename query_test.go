@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the assembly of a retrieval with a query.
+func ExampleDbType_Query() {
+	type numType struct {
+		ID   int64  `ql_table:"num"`
+		Num  int64  `ql:"*"`
+		Name string `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&numType{})
+	db.Insert([]numType{{0, 3, "three"}, {0, 9, "nine"}, {0, 5, "five"},
+		{0, 7, "seven"}, {0, 1, "one"}, {0, 8, "eight?"}})
+	q := db.Query(&numType{}).Where("Num > ? && Num < ?", int64(2), int64(9)).
+		Where("Name != \"seven\" && Name != ?1", "three").OrderBy("Num DESC").Limit(2)
+	fmt.Println(q.Tail())
+	var list []numType
+	q.Fetch(&list)
+	for _, rec := range list {
+		fmt.Println(rec.Num, rec.Name)
+	}
+	fmt.Println(db.Query(&numType{}).Where("Name == \"eight?\"").Exists())
+	var rec numType
+	found := db.Query(&numType{}).OrderBy("Num").Offset(1).First(&rec)
+	fmt.Println(found, rec.Num, rec.Name, db.Error())
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// WHERE (Num > ?1 && Num < ?2) && (Name != "seven" && Name != ?3) ORDER BY Num DESC LIMIT 2 [2 9 three]
+	// 8 eight?
+	// 5 five
+	// true
+	// true 3 three <nil>
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"time"
+)
+
+// QueueItemType is a single entry in a durable work queue managed by
+// QueueType. Visible is the time after which the item may be claimed by
+// Dequeue(); Tries counts the number of times it has been claimed.
+type QueueItemType struct {
+	ID      int64     `ql_table:"qlm_queue"`
+	Queue   string    `ql:"*" ql_index:"*"`
+	Data    []byte    `ql:"*"`
+	Visible time.Time `ql:"*"`
+	Tries   int64     `ql:"*"`
+}
+
+// QueueType provides a durable first-in, first-out work queue. Items of all
+// queues are stored in the table qlm_queue. An item that has been claimed by
+// Dequeue() is hidden from other consumers until it is acknowledged with
+// Ack(), returned with Nack(), or its visibility timeout elapses. Errors are
+// reported by means of the qlm instance from which the queue was obtained.
+type QueueType struct {
+	db      *DbType
+	nameStr string
+}
+
+// Queue returns the work queue identified by nameStr. The queue table is
+// created if it does not already exist.
+func (db *DbType) Queue(nameStr string) (q *QueueType) {
+	q = &QueueType{db: db, nameStr: nameStr}
+	db.TableEnsure(&QueueItemType{})
+	return
+}
+
+// Enqueue adds an item with the specified data to the end of the queue and
+// returns its identifier.
+func (q *QueueType) Enqueue(data []byte) (id int64) {
+	list := []QueueItemType{{Queue: q.nameStr, Data: data, Visible: time.Now()}}
+	q.db.Insert(list)
+	return list[0].ID
+}
+
+// Dequeue claims the oldest item in the queue that is not currently claimed
+// by another consumer. The item is hidden from other consumers for the
+// duration of timeout; if it is not acknowledged within that time, it becomes
+// available again. The search and the claim take place within a single
+// transaction, so an item is never claimed by two consumers at once. ok is
+// false if no item is available.
+func (q *QueueType) Dequeue(timeout time.Duration) (item QueueItemType, ok bool) {
+	db := q.db
+	if db.err != nil {
+		return
+	}
+	var list []QueueItemType
+	tm := time.Now()
+	db.TransactBegin()
+	db.Retrieve(&list, "WHERE Queue == ?1 && Visible <= ?2 ORDER BY id() LIMIT 1", q.nameStr, tm)
+	if db.err == nil && len(list) > 0 {
+		item = list[0]
+		item.Visible = tm.Add(timeout)
+		item.Tries++
+		db.Update(&item, "Visible", "Tries")
+		ok = db.err == nil
+	}
+	db.transactEnd(db.err == nil)
+	return
+}
+
+// Ack removes the claimed item identified by id from the queue after it has
+// been processed successfully.
+func (q *QueueType) Ack(id int64) {
+	q.db.Delete(&QueueItemType{}, "WHERE id() == ?1 && Queue == ?2", id, q.nameStr)
+}
+
+// Nack releases the claimed item identified by id so that it is immediately
+// available to other consumers.
+func (q *QueueType) Nack(id int64) {
+	q.db.UpdateWhere(&QueueItemType{}, map[string]interface{}{"Visible": time.Now()},
+		"WHERE id() == ?1 && Queue == ?2", id, q.nameStr)
+}
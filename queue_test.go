@@ -0,0 +1,56 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+	"time"
+)
+
+// This example demonstrates a durable work queue. The first item is claimed,
+// returned with Nack() and claimed again. Once acknowledged, it is removed
+// from the queue.
+func ExampleQueueType() {
+	db := qlm.DbCreate("data/example.ql")
+	q := db.Queue("mail")
+	q.Enqueue([]byte("to athos"))
+	q.Enqueue([]byte("to porthos"))
+	item, ok := q.Dequeue(time.Minute)
+	fmt.Println(string(item.Data), item.Tries, ok)
+	other, ok := q.Dequeue(time.Minute)
+	fmt.Println(string(other.Data), other.Tries, ok)
+	_, ok = q.Dequeue(time.Minute)
+	fmt.Println(ok)
+	q.Nack(item.ID)
+	item, ok = q.Dequeue(time.Minute)
+	fmt.Println(string(item.Data), item.Tries, ok)
+	q.Ack(item.ID)
+	q.Ack(other.ID)
+	_, ok = q.Dequeue(time.Minute)
+	fmt.Println(ok)
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// to athos 1 true
+	// to porthos 1 true
+	// false
+	// to athos 2 true
+	// false
+}
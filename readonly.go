@@ -0,0 +1,74 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"reflect"
+)
+
+// readonlySet validates the readonly option of the field sf. The option
+// excludes the column of the field from the columns stored by Update() when
+// "*" is specified, and makes it an error to name the column explicitly in
+// Update() or UpdateWhere(). With the value "insert", for example
+// `ql:"*,readonly=insert"`, the column is also stored as NULL by Insert(), so
+// that a DEFAULT expression of the column, if any, applies. The column is
+// retrieved like any other.
+func (db *DbType) readonlySet(sf reflect.StructField, optMap map[string]string) {
+	switch optMap["readonly"] {
+	case "", "insert":
+	default:
+		db.SetErrorf("readonly option of field %s must be empty or insert, got %s", sf.Name, optMap["readonly"])
+	}
+}
+
+// readonly returns true if the column nameStr of the table described by dsc
+// has the readonly option.
+func (dsc qlDscType) readonly(nameStr string) bool {
+	_, ok := dsc.optMap[nameStr]["readonly"]
+	return ok
+}
+
+// readonlyInsert returns true if the column nameStr of the table described by
+// dsc is stored as NULL by Insert().
+func (dsc qlDscType) readonlyInsert(nameStr string) bool {
+	return dsc.optMap[nameStr]["readonly"] == "insert"
+}
+
+// updateNames returns the names of the columns that are stored by Update()
+// given the names passed to it in fldNames. "*" selects every column that
+// does not have the readonly option. An error is set if a column with the
+// readonly option is named explicitly, or if no columns remain.
+func (db *DbType) updateNames(dsc qlDscType, fldNames []string, fncStr string) (list []string) {
+	if fldNames[0] == "*" {
+		for _, nameStr := range dsc.insert.nameList {
+			if !dsc.readonly(nameStr) {
+				list = append(list, nameStr)
+			}
+		}
+		if len(list) == 0 {
+			db.SetErrorf("table %s has no columns that can be updated", dsc.tblStr)
+		}
+		return
+	}
+	for _, nameStr := range fldNames {
+		if dsc.readonly(nameStr) {
+			db.SetErrorf("column %s is read-only and cannot be assigned by %s", nameStr, fncStr)
+			return nil
+		}
+	}
+	return fldNames
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates columns that are maintained outside of the
+// application and are therefore not stored by Update.
+func ExampleDbType_readonly() {
+	type acctType struct {
+		ID      int64  `ql_table:"acct"`
+		Name    string `ql:"*"`
+		Balance int64  `ql:"*,readonly"`
+		Status  string `ql:"*,readonly=insert,default=new"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&acctType{})
+	rec := acctType{Name: "Ann", Balance: 100, Status: "ignored"}
+	list := []acctType{rec}
+	show := func() {
+		list = nil
+		db.Retrieve(&list, "")
+		fmt.Println(list[0].Name, list[0].Balance, list[0].Status)
+	}
+	db.Insert(list)
+	rec = list[0]
+	show()
+	db.TransactBegin()
+	db.Exec(`UPDATE acct Balance = 250, Status = "open";`)
+	db.TransactCommit()
+	rec.Name, rec.Balance, rec.Status = "Anne", 0, "closed"
+	db.Update(&rec, "*")
+	show()
+	db.Update(&rec, "Name", "Balance")
+	fmt.Println(db.Error())
+	db.ClearError()
+	db.UpdateWhere(&acctType{}, map[string]interface{}{"Status": "closed"}, "")
+	fmt.Println(db.Error())
+	db.ClearError()
+	show()
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// Ann 100 new
+	// Anne 250 open
+	// column Balance is read-only and cannot be assigned by Update
+	// column Status is read-only and cannot be assigned by UpdateWhere
+	// Anne 250 open
+}
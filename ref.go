@@ -0,0 +1,112 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"reflect"
+)
+
+// Preload loads the child records of each record in the slice pointed to by
+// slicePtr. Child records are held in slice fields of the parent type that
+// have a "ql_ref" tag. The tag names the column of the child table that holds
+// the identifier of the parent record, for example
+//
+//	type custType struct {
+//		ID     int64       `ql_table:"cust"`
+//		Name   string      `ql:"*"`
+//		Orders []orderType `ql_ref:"CustID"`
+//	}
+//	db.Retrieve(&list, "WHERE Name == ?1", nameStr)
+//	db.Preload(&list, "Orders")
+//
+// fldNames identifies the slice fields to load by their Go names; if none are
+// specified, all fields with a "ql_ref" tag are loaded. The children of all
+// parents are retrieved with a single query for each field, ordered by
+// identifier, and replace the current contents of the field. Retrieve itself
+// ignores fields with a "ql_ref" tag.
+func (db *DbType) Preload(slicePtr interface{}, fldNames ...string) {
+	if db.err != nil {
+		return
+	}
+	recTp := db.slicePtrCheck(slicePtr, "Preload")
+	dsc := db.dscFromType(recTp)
+	if db.err != nil {
+		return
+	}
+	var refList []reflect.StructField
+	for j := 0; j < recTp.NumField(); j++ {
+		if sf := recTp.Field(j); len(sf.Tag.Get("ql_ref")) > 0 && len(fldNames) == 0 {
+			refList = append(refList, sf)
+		}
+	}
+	for _, nameStr := range fldNames {
+		if sf, ok := recTp.FieldByName(nameStr); ok && len(sf.Tag.Get("ql_ref")) > 0 {
+			refList = append(refList, sf)
+		} else {
+			db.SetErrorf(`field %s with "ql_ref" tag not found in type %v`, nameStr, recTp)
+			return
+		}
+	}
+	sliceVl := reflect.ValueOf(slicePtr).Elem()
+	count := sliceVl.Len()
+	if count == 0 {
+		return
+	}
+	var idList []int64
+	posMap := make(map[int64][]int)
+	for j := 0; j < count; j++ {
+		id := fieldValue(sliceVl.Index(j), dsc.idSf).Int()
+		if _, ok := posMap[id]; !ok {
+			idList = append(idList, id)
+		}
+		posMap[id] = append(posMap[id], j)
+	}
+	for _, sf := range refList {
+		if sf.Type.Kind() != reflect.Slice {
+			db.SetErrorf(`field %s with "ql_ref" tag must be a slice`, sf.Name)
+			return
+		}
+		child := db.dscFromType(sf.Type.Elem())
+		if db.err != nil {
+			return
+		}
+		refStr := sf.Tag.Get("ql_ref")
+		refSf, ok := child.nameMap[refStr]
+		if !ok || refSf.Type.Kind() != reflect.Int64 {
+			db.SetErrorf("int64 field %s not found in table %s", refStr, child.tblStr)
+			return
+		}
+		childPtrVl := reflect.New(sf.Type)
+		condStr, prms := inCond(refStr, idList, 0)
+		db.Retrieve(childPtrVl.Interface(), "WHERE "+condStr+" ORDER BY id()", prms...)
+		if db.err != nil {
+			return
+		}
+		for j := 0; j < count; j++ {
+			sliceVl.Index(j).FieldByIndex(sf.Index).Set(reflect.Zero(sf.Type))
+		}
+		childVl := childPtrVl.Elem()
+		for k := 0; k < childVl.Len(); k++ {
+			recVl := childVl.Index(k)
+			id := fieldValue(recVl, refSf).Int()
+			for _, j := range posMap[id] {
+				fldVl := sliceVl.Index(j).FieldByIndex(sf.Index)
+				fldVl.Set(reflect.Append(fldVl, recVl))
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the loading of child records for a list of
+// parent records.
+func ExampleDbType_Preload() {
+	type orderType struct {
+		ID     int64   `ql_table:"orders"`
+		CustID int64   `ql:"*"`
+		Amt    float64 `ql:"*"`
+	}
+	type custType struct {
+		ID     int64       `ql_table:"cust"`
+		Name   string      `ql:"*"`
+		Orders []orderType `ql_ref:"CustID"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&custType{})
+	db.TableCreate(&orderType{})
+	custList := []custType{{Name: "ann"}, {Name: "bob"}, {Name: "cy"}}
+	db.Insert(custList)
+	db.Insert([]orderType{{0, custList[0].ID, 40}, {0, custList[1].ID, 15},
+		{0, custList[0].ID, 85}})
+	custList = nil
+	db.Retrieve(&custList, "ORDER BY Name")
+	db.Preload(&custList)
+	for _, cust := range custList {
+		fmt.Printf("%-3s %d", cust.Name, len(cust.Orders))
+		for _, order := range cust.Orders {
+			fmt.Printf(" %.2f", order.Amt)
+		}
+		fmt.Println()
+	}
+	db.Preload(&custList, "Invoices")
+	fmt.Println(db.Error())
+	db.ClearError()
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// ann 2 40.00 85.00
+	// bob 1 15.00
+	// cy  0
+	// field Invoices with "ql_ref" tag not found in type qlm_test.custType
+}
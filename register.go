@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// registry holds the descriptors of the record types passed to Register().
+// It is shared by all qlm instances.
+var registry = struct {
+	sync.RWMutex
+	dscMap map[reflect.Type]qlDscType
+}{dscMap: make(map[reflect.Type]qlDscType)}
+
+// registryGet returns the registered descriptor of the record type recTp.
+func registryGet(recTp reflect.Type) (dsc qlDscType, ok bool) {
+	registry.RLock()
+	dsc, ok = registry.dscMap[recTp]
+	registry.RUnlock()
+	return
+}
+
+// registryUsable returns true if the descriptors built for db are the same as
+// those built by Register(), that is, if db does not have a naming strategy,
+// automatic mapping or a table prefix.
+func (db *DbType) registryUsable() bool {
+	return db.namer == nil && !db.autoMap && len(db.tblPrefix) == 0
+}
+
+// Register builds the descriptor of each record type pointed to by an
+// element of recPtrs and stores it in a registry that is shared by all qlm
+// instances. Without registration, each instance builds the descriptor of a
+// record type the first time the type is used, and any error in its tags is
+// reported only then. Register panics if a descriptor cannot be built, so
+// calling it from an init function or a package-level variable declaration
+// detects tag mistakes when the program starts. For example,
+//
+//	func init() {
+//		qlm.Register(&custType{}, &orderType{})
+//	}
+//
+// Registered descriptors use the default column names, mapping and table
+// names; an instance for which SetNamer(), AutoMap() or SetTablePrefix() has
+// been called builds its own. Registering a type that is
+// already registered has no effect. It is safe to
+// call Register from more than one goroutine.
+func Register(recPtrs ...interface{}) {
+	for _, recPtr := range recPtrs {
+		recTp := reflect.TypeOf(recPtr)
+		if recTp != nil && recTp.Kind() == reflect.Ptr {
+			if _, ok := registryGet(recTp.Elem()); ok {
+				continue
+			}
+		}
+		var db DbType
+		db.init()
+		dsc := db.dscFromPtr(recPtr)
+		if db.err != nil {
+			panic(fmt.Sprintf("qlm: cannot register %v: %s", recTp, db.err))
+		}
+		registry.Lock()
+		registry.dscMap[dsc.recTp] = dsc
+		registry.Unlock()
+	}
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+type regCustType struct {
+	ID   int64  `ql_table:"cust"`
+	Name string `ql:"*"`
+}
+
+type regBadType struct {
+	ID  int64  `ql_table:"bad"`
+	Str string `ql:"*,colate=fold"`
+}
+
+func init() {
+	qlm.Register(&regCustType{})
+}
+
+// This example demonstrates the registration of record types. Registered
+// descriptors are shared by all qlm instances, and a type with a tag error is
+// rejected when it is registered rather than when it is first used.
+func ExampleRegister() {
+	for j := 0; j < 2; j++ {
+		db := qlm.DbCreate("data/example.ql")
+		db.TableCreate(&regCustType{})
+		db.Insert([]regCustType{{Name: fmt.Sprintf("cust %d", j)}})
+		var list []regCustType
+		db.Retrieve(&list, "")
+		fmt.Println(len(list), list[0].Name)
+		db.Close()
+		if db.Err() {
+			fmt.Println(db.Error())
+		}
+	}
+	func() {
+		defer func() {
+			fmt.Println(recover())
+		}()
+		qlm.Register(&regBadType{})
+	}()
+	// Output:
+	// 1 cust 0
+	// 1 cust 1
+	// qlm: cannot register *qlm_test.regBadType: unknown option colate in ql tag of field Str
+}
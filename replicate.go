@@ -0,0 +1,173 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const replicateBatch = 256
+
+// replicaPosType records, in the follower, the journal position up to which
+// the changes of a source have been applied.
+type replicaPosType struct {
+	ID     int64  `ql_table:"qlm_replica"`
+	Source string `ql:"*"`
+	Seq    int64  `ql:"*"`
+}
+
+// replicaIDType maps the identifier of a record in the source to the
+// identifier of the corresponding record in the follower. Identifiers cannot
+// be preserved because ql assigns them on insertion.
+type replicaIDType struct {
+	ID     int64  `ql_table:"qlm_replica_id"`
+	Source string `ql:"*"`
+	Tbl    string `ql:"*"`
+	SrcID  int64  `ql:"*" ql_index:"*"`
+	DstID  int64  `ql:"*"`
+}
+
+// Replicate applies to the follower database dst the changes that have been
+// recorded in the journal of the source database src since the previous call
+// with the same name. The journal of src must be enabled with Journal. nameStr
+// identifies the source in dst, so a follower can replicate several sources.
+// recPtrs point to records of the types whose tables are replicated; each
+// table that appears in the journal must be represented. The tables are
+// created in dst if needed. The number of changes applied is returned.
+//
+// Changes are applied in batches. The position in the source journal is
+// stored in dst in the same transaction as each batch, so replication can be
+// interrupted and resumed at any time, and a change is never applied twice.
+// Since ql assigns record identifiers on insertion, the identifiers of
+// replicated records generally differ from those in the source; a mapping is
+// maintained in dst to apply updates and deletions to the correct records.
+// Fields that hold identifiers of other records are copied unchanged.
+//
+// Any error, whether it occurs in src or dst, is assigned to dst. Replicate
+// can be called periodically, for example from a goroutine with a ticker, to
+// keep a follower current.
+func Replicate(src, dst *DbType, nameStr string, recPtrs ...interface{}) (count int64) {
+	if dst.err != nil {
+		return
+	}
+	if src.err != nil {
+		dst.err = src.err
+		return
+	}
+	dscMap := make(map[string]qlDscType)
+	for _, recPtr := range recPtrs {
+		dsc := dst.dscFromPtr(recPtr)
+		dst.TableEnsure(recPtr)
+		if dst.err == nil {
+			dscMap[dsc.tblStr] = dsc
+		}
+	}
+	dst.TableEnsure(&replicaPosType{})
+	dst.TableEnsure(&replicaIDType{})
+	var posList []replicaPosType
+	dst.Retrieve(&posList, "WHERE Source == ?1", nameStr)
+	pos := replicaPosType{Source: nameStr}
+	if len(posList) > 0 {
+		pos = posList[0]
+	}
+	for more := true; more && dst.err == nil; {
+		list := src.ChangesSince(pos.Seq, replicateBatch)
+		if src.err != nil {
+			dst.err = src.err
+		}
+		more = len(list) == replicateBatch
+		if dst.err == nil && len(list) > 0 {
+			dst.TransactBegin()
+			for j := 0; j < len(list) && dst.err == nil; j++ {
+				dsc, ok := dscMap[list[j].Table]
+				if ok {
+					dst.replicaApply(nameStr, dsc, list[j])
+				} else {
+					dst.SetErrorf("no record type specified for replicated table %s", list[j].Table)
+				}
+			}
+			pos.Seq = list[len(list)-1].Seq
+			if pos.ID == 0 {
+				posList = []replicaPosType{pos}
+				dst.Insert(posList)
+				pos = posList[0]
+			} else {
+				dst.Update(&pos, "Seq")
+			}
+			dst.transactEnd(dst.err == nil)
+			if dst.err == nil {
+				count += int64(len(list))
+			}
+		}
+	}
+	return
+}
+
+// replicaApply applies the journal entry ch, which concerns a record
+// described by dsc, to db. This method must be called within a transaction.
+func (db *DbType) replicaApply(nameStr string, dsc qlDscType, ch ChangeType) {
+	var idList []replicaIDType
+	db.Retrieve(&idList, "WHERE SrcID == ?1 && Source == ?2 && Tbl == ?3", ch.RecID, nameStr, ch.Table)
+	switch ch.Op {
+	case ChangeInsert, ChangeUpdate:
+		recPtrVl := reflect.New(dsc.recTp)
+		if db.err == nil {
+			db.err = ch.Decode(recPtrVl.Interface())
+		}
+		if db.err != nil {
+			return
+		}
+		if len(idList) > 0 {
+			db.replicaUpdate(dsc, recPtrVl.Elem(), idList[0].DstID)
+		} else {
+			sliceVl := reflect.Append(reflect.MakeSlice(reflect.SliceOf(dsc.recTp), 0, 1), recPtrVl.Elem())
+			db.Insert(sliceVl.Interface())
+			if db.err == nil {
+				dstID := valueList(sliceVl.Index(0), []reflect.StructField{dsc.idSf})[0].Int()
+				db.Insert([]replicaIDType{{Source: nameStr, Tbl: ch.Table, SrcID: ch.RecID, DstID: dstID}})
+			}
+		}
+	case ChangeDelete:
+		if len(idList) > 0 {
+			db.Delete(reflect.New(dsc.recTp).Interface(), "WHERE id() == ?1", idList[0].DstID)
+			db.Delete(&replicaIDType{}, "WHERE id() == ?1", idList[0].ID)
+		}
+	case ChangeTruncate:
+		db.Truncate(reflect.New(dsc.recTp).Interface())
+		db.Delete(&replicaIDType{}, "WHERE Source == ?1 && Tbl == ?2", nameStr, ch.Table)
+	default:
+		db.SetErrorf("unknown journal operation %s", ch.Op)
+	}
+}
+
+// replicaUpdate stores all columns of the record recVl in the record of the
+// table described by dsc that has the identifier id. Unlike Update, columns
+// maintained by qlm, such as UpdatedAt, are copied rather than reassigned.
+func (db *DbType) replicaUpdate(dsc qlDscType, recVl reflect.Value, id int64) {
+	var eqList []string
+	vList := valList(recVl, dsc.insert.sfList)
+	for j, nameStr := range dsc.insert.nameList {
+		strListAppend(&eqList, "%s = ?%d", nameStr, j+1)
+		vList[j] = db.storeVal(dsc, nameStr, vList[j])
+	}
+	eqList, vList = dsc.collateUpdate(recVl, dsc.insert.nameList, eqList, vList)
+	_, _ = db.Exec(fmt.Sprintf("UPDATE %s %s WHERE id() == ?%d;", dsc.tblStr,
+		strings.Join(eqList, ", "), len(vList)+1), append(vList, id)...)
+	db.journalIDs(dsc, ChangeUpdate, []int64{id})
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the incremental replication of a database.
+func ExampleReplicate() {
+	type cityType struct {
+		ID   int64  `ql_table:"city"`
+		Name string `ql:"*"`
+		Pop  int64  `ql:"*"`
+	}
+	show := func(db *qlm.DbType) {
+		var list []cityType
+		db.Retrieve(&list, "ORDER BY Name")
+		for _, rec := range list {
+			fmt.Printf("  %s %d\n", rec.Name, rec.Pop)
+		}
+	}
+	src := qlm.DbCreate("data/example.ql")
+	dst := qlm.DbCreate("data/follower.ql")
+	src.TableCreate(&cityType{})
+	src.Journal(true)
+	list := []cityType{{0, "Oslo", 700000}, {0, "Bergen", 285000}, {0, "Molde", 27000}}
+	src.Insert(list)
+	fmt.Printf("%d changes\n", qlm.Replicate(src, dst, "main", &cityType{}))
+	list[0].Pop = 709000
+	src.Update(&list[0], "Pop")
+	src.Delete(&cityType{}, "WHERE Name == ?1", "Molde")
+	src.Insert([]cityType{{0, "Tromsø", 77000}})
+	fmt.Printf("%d changes\n", qlm.Replicate(src, dst, "main", &cityType{}))
+	fmt.Printf("%d changes\n", qlm.Replicate(src, dst, "main", &cityType{}))
+	show(dst)
+	src.Close()
+	dst.Close()
+	if dst.Err() {
+		fmt.Println(dst.Error())
+	}
+	// Output:
+	// 3 changes
+	// 3 changes
+	// 0 changes
+	//   Bergen 285000
+	//   Oslo 709000
+	//   Tromsø 77000
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+)
+
+// IntegrityType reports the result of verifying one table with
+// VerifyIntegrity().
+type IntegrityType struct {
+	Table string // Name of the table
+	Count int64  // Number of records read from the table
+	Err   error  // First error encountered in the table, or nil
+}
+
+// VerifyIntegrity reads every record of every table in the database and
+// reports, for each table in order of name, the number of records read and
+// the first error encountered. An error indicates a storage failure, such as
+// a corrupt page, in the table. Tables that are associated with a record type
+// that has been used with db are also decoded into records of that type,
+// which reveals values that cannot be converted to their fields as well as
+// records whose checksum does not match their content. Errors found in a
+// table are reported in its IntegrityType value rather than assigned to the
+// database, so that the remaining tables are still examined.
+func (db *DbType) VerifyIntegrity() (list []IntegrityType) {
+	if db.err != nil {
+		return
+	}
+	var tblList []struct {
+		Name string
+	}
+	db.RawRetrieve(&tblList, "SELECT Name FROM __Table ORDER BY Name;")
+	for _, tbl := range tblList {
+		if db.err != nil {
+			break
+		}
+		list = append(list, db.tableVerify(tbl.Name))
+	}
+	return
+}
+
+// tableVerify reads, and if possible decodes, every record of the table
+// tblStr.
+func (db *DbType) tableVerify(tblStr string) (ver IntegrityType) {
+	ver.Table = tblStr
+	rs, _ := db.Exec(fmt.Sprintf("SELECT * FROM %s;", tblStr))
+	if db.err == nil && len(rs) > 0 {
+		db.err = rs[0].Do(false, func(data []interface{}) (bool, error) {
+			ver.Count++
+			return true, nil
+		})
+	}
+	var dscList []qlDscType
+	for _, dsc := range db.dscCache.list() {
+		if dsc.tblStr == tblStr && len(dsc.view.selStr) == 0 {
+			dscList = append(dscList, dsc)
+		}
+	}
+	sort.Slice(dscList, func(a, b int) bool {
+		return dscList[a].recTp.String() < dscList[b].recTp.String()
+	})
+	for _, dsc := range dscList {
+		if db.err == nil {
+			db.RetrieveBatches(reflect.New(dsc.recTp).Interface(), 1000,
+				func(batch interface{}) error { return nil }, "")
+		}
+	}
+	ver.Err = db.errTake()
+	return
+}
+
+// walFileStr returns the name of the write-ahead log that ql maintains for
+// the database file dbFileStr.
+func walFileStr(dbFileStr string) string {
+	base := filepath.Base(filepath.Clean(dbFileStr))
+	return filepath.Join(filepath.Dir(dbFileStr), fmt.Sprintf(".%x", sha1.Sum([]byte(base))))
+}
+
+// lockFileStr returns the name of the lock file that ql creates, and leaves
+// in place after closing, for the database file dbFileStr.
+func lockFileStr(dbFileStr string) string {
+	base := filepath.Base(filepath.Clean(dbFileStr)) + "lockfile"
+	return filepath.Join(filepath.Dir(dbFileStr), fmt.Sprintf(".%x", sha1.Sum([]byte(base))))
+}
+
+// DbRestore replaces the database file liveStr with a copy of the backup
+// file backupStr, for example one made by Backup(), and opens it. The backup
+// is opened and verified with VerifyIntegrity() first; if it cannot be read
+// in its entirety, liveStr is left unchanged and the error is assigned to the
+// returned instance. The write-ahead log of liveStr, if any, is discarded so
+// that changes pending in it are not applied to the restored database. The
+// database at liveStr must not be open. After use, Close() should be called
+// to free resources.
+func DbRestore(backupStr, liveStr string) (db *DbType) {
+	bk := DbOpen(backupStr)
+	for _, ver := range bk.VerifyIntegrity() {
+		if ver.Err != nil {
+			bk.SetErrorf("backup %s failed verification in table %s: %s", backupStr, ver.Table, ver.Err)
+		}
+	}
+	bk.Close()
+	if bk.err != nil {
+		db = new(DbType)
+		db.err = bk.err
+		return
+	}
+	err := os.Remove(walFileStr(liveStr))
+	if err == nil || os.IsNotExist(err) {
+		err = fileCopy(backupStr, liveStr)
+	}
+	if err != nil {
+		db = new(DbType)
+		db.err = err
+		return
+	}
+	return DbOpen(liveStr)
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+	"os"
+)
+
+// This example demonstrates the restoration of a database from a backup and
+// the verification of its content.
+func ExampleDbRestore() {
+	type recType struct {
+		ID   int64  `ql_table:"rec"`
+		Name string `ql:"*"`
+		Sum  string `ql:"*,checksum"`
+	}
+	os.RemoveAll("data/restore")
+	db := qlm.DbCreate("data/restore/live.ql")
+	db.TableCreate(&recType{})
+	db.Insert([]recType{{Name: "alpha"}, {Name: "beta"}})
+	db.Backup("data/restore/backup.ql")
+	db.Insert([]recType{{Name: "gamma"}})
+	// Alter a record without maintaining its checksum
+	db.TransactBegin()
+	db.Exec("UPDATE rec SET Name = ?1 WHERE Name == ?2;", "ALPHA", "alpha")
+	db.TransactCommit()
+	for _, ver := range db.VerifyIntegrity() {
+		fmt.Println(ver.Table, ver.Count, ver.Err != nil)
+	}
+	db.Close()
+	db = qlm.DbRestore("data/restore/backup.ql", "data/restore/live.ql")
+	var list []recType
+	db.Retrieve(&list, "ORDER BY Name")
+	for _, rec := range list {
+		fmt.Println(rec.Name)
+	}
+	for _, ver := range db.VerifyIntegrity() {
+		fmt.Println(ver.Table, ver.Count, ver.Err)
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// rec 3 true
+	// alpha
+	// beta
+	// rec 2 <nil>
+}
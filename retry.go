@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"errors"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// IsTransient returns true if err is, or wraps, an error that may not recur
+// if the operation is attempted again, for example one that reports that the
+// database file is temporarily locked or busy.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	for _, errno := range []syscall.Errno{syscall.EAGAIN, syscall.EBUSY, syscall.EINTR} {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+	str := err.Error()
+	return strings.Contains(str, "already locked") ||
+		strings.Contains(str, "resource temporarily unavailable")
+}
+
+// TransactRetry runs fn within a transaction like Transact(). If the
+// transaction fails with an error for which IsTransient() returns true, the
+// error of the database is cleared and the transaction is attempted again
+// after a delay, up to a total of attempts times. The first delay is backoff
+// and each subsequent delay is twice the one before it. Since fn may be
+// called more than once, it should not have side effects outside of the
+// database. The error of the database is returned.
+func (db *DbType) TransactRetry(attempts int, backoff time.Duration, fn func(tx *DbType) error) error {
+	if db.err != nil {
+		return db.err
+	}
+	for j := 1; ; j++ {
+		err := db.Transact(fn)
+		if err == nil || j >= attempts || !IsTransient(err) {
+			return err
+		}
+		db.ClearError()
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+	"syscall"
+	"time"
+)
+
+// This example demonstrates a transaction that is attempted again after a
+// transient failure.
+func ExampleDbType_TransactRetry() {
+	type recType struct {
+		ID   int64  `ql_table:"rec"`
+		Name string `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&recType{})
+	count := 0
+	err := db.TransactRetry(4, time.Millisecond, func(tx *qlm.DbType) error {
+		count++
+		tx.Insert([]recType{{0, fmt.Sprintf("attempt %d", count)}})
+		if count < 3 {
+			return fmt.Errorf("commit: %w", syscall.EAGAIN)
+		}
+		return nil
+	})
+	fmt.Println(count, err)
+	err = db.TransactRetry(4, time.Millisecond, func(tx *qlm.DbType) error {
+		count++
+		return fmt.Errorf("permanent failure")
+	})
+	fmt.Println(count, err, qlm.IsTransient(err))
+	db.ClearError()
+	var list []recType
+	db.Retrieve(&list, "")
+	for _, rec := range list {
+		fmt.Println(rec.Name)
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// 3 <nil>
+	// 4 permanent failure false
+	// attempt 3
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// inCond returns a condition that is satisfied by the records in which the
+// int64 expression exprStr, for example "id()", has one of the values in
+// idList, along with the parameters to which the condition refers. These are
+// numbered following the first count parameters of the statement. The values
+// are bound rather than written into the condition so that the text of the
+// statement depends only on their number and can be cached.
+func inCond(exprStr string, idList []int64, count int) (condStr string, prms []interface{}) {
+	var qmList []string
+	for j, id := range idList {
+		strListAppend(&qmList, "?%d", count+j+1)
+		prms = append(prms, id)
+	}
+	condStr = fmt.Sprintf("%s IN (%s)", exprStr, strings.Join(qmList, ", "))
+	return
+}
+
+// UpdateReturning assigns new values to the records that satisfy tailStr and
+// appends the updated records to the slice pointed to by slicePtr. setStr is
+// a comma-separated list of assignments such as "Qty = Qty - ?1, Note = ?2";
+// it shares the parameters prms with tailStr. A single record can be updated
+// with the tail "WHERE id() == ?n". The records to update are identified,
+// updated and selected again within one transaction, so the returned records
+// reflect the state that was committed, including columns such as UpdatedAt
+// that are maintained by qlm, and no other writer can intervene. Records
+// remain in the result even if the update causes them to no longer satisfy
+// tailStr. If an error occurs, the update is rolled back and the slice is not
+// modified.
+func (db *DbType) UpdateReturning(slicePtr interface{}, setStr string, tailStr string, prms ...interface{}) {
+	if db.err != nil {
+		return
+	}
+	recTp := db.slicePtrCheck(slicePtr, "UpdateReturning")
+	if db.err != nil {
+		return
+	}
+	dsc := db.dscFromType(recTp)
+	if !db.writable(dsc) {
+		return
+	}
+	if len(dsc.soft.nameStr) > 0 {
+		tailStr = whereAnd(tailStr, dsc.soft.nameStr+" IS NULL")
+	}
+	for _, nameStr := range dsc.auto.updateStr {
+		prms = append(prms[:len(prms):len(prms)], time.Now())
+		setStr = fmt.Sprintf("%s, %s = ?%d", setStr, nameStr, len(prms))
+	}
+	sliceVl := reflect.ValueOf(slicePtr).Elem()
+	origVl := reflect.ValueOf(sliceVl.Interface()) // Restored on failure
+	db.TransactBegin()
+	idList := db.idList(dsc, tailStr, prms...)
+	if db.err == nil && len(idList) > 0 {
+		condStr, idPrms := inCond("id()", idList, len(prms))
+		_, _ = db.Exec(fmt.Sprintf("UPDATE %s %s WHERE %s;", dsc.tblStr, setStr, condStr),
+			append(prms[:len(prms):len(prms)], idPrms...)...)
+		db.derivedRepair(dsc, idList)
+		db.journalIDs(dsc, ChangeUpdate, idList)
+		condStr, idPrms = inCond("id()", idList, 0)
+		db.Retrieve(slicePtr, "WHERE "+condStr+" ORDER BY id()", idPrms...)
+	}
+	db.transactEnd(db.err == nil)
+	if db.err != nil {
+		sliceVl.Set(origVl)
+	}
+}
+
+// DeleteReturning removes the records of the type pointed to by recPtr that
+// satisfy tailStr and its parameters, and appends them, as they were
+// immediately before their removal, to the slice pointed to by slicePtr. The
+// records are selected and deleted within one transaction, so the returned
+// records are exactly the ones removed. This is useful for maintaining audit
+// logs and undo buffers and for moving records to archive tables. As with
+// Delete, records of a type that embeds SoftModel are marked as deleted
+// rather than removed. If an error occurs, the deletion is rolled back and
+// the slice is not modified.
+func (db *DbType) DeleteReturning(recPtr interface{}, slicePtr interface{}, tailStr string, prms ...interface{}) {
+	if db.err != nil {
+		return
+	}
+	recTp := db.slicePtrCheck(slicePtr, "DeleteReturning")
+	if db.err != nil {
+		return
+	}
+	dsc := db.dscFromPtr(recPtr)
+	if db.err == nil && dsc.recTp != recTp {
+		db.SetErrorf("function DeleteReturning expecting slice of %v, got slice of %v", dsc.recTp, recTp)
+	}
+	if !db.writable(dsc) {
+		return
+	}
+	if len(dsc.soft.nameStr) > 0 {
+		tailStr = whereAnd(tailStr, dsc.soft.nameStr+" IS NULL")
+	}
+	sliceVl := reflect.ValueOf(slicePtr).Elem()
+	origVl := reflect.ValueOf(sliceVl.Interface()) // Restored on failure
+	db.TransactBegin()
+	idList := db.idList(dsc, tailStr, prms...)
+	if db.err == nil && len(idList) > 0 {
+		condStr, idPrms := inCond("id()", idList, 0)
+		db.Retrieve(slicePtr, "WHERE "+condStr+" ORDER BY id()", idPrms...)
+		db.Delete(recPtr, "WHERE "+condStr, idPrms...)
+	}
+	db.transactEnd(db.err == nil)
+	if db.err != nil {
+		sliceVl.Set(origVl)
+	}
+}
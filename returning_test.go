@@ -0,0 +1,92 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates an update that returns the modified records.
+func ExampleDbType_UpdateReturning() {
+	type stockType struct {
+		qlm.Model `ql_table:"stock"`
+		Item      string `ql:"*"`
+		Qty       int32  `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&stockType{})
+	db.Insert([]stockType{{Item: "bolt", Qty: 10}, {Item: "nut", Qty: 3}, {Item: "gear", Qty: 7}})
+	var list []stockType
+	db.UpdateReturning(&list, "Qty = Qty - ?1", "WHERE Qty >= ?1", int32(5))
+	for _, rec := range list {
+		fmt.Printf("%d %-4s %d %v\n", rec.ID, rec.Item, rec.Qty, rec.UpdatedAt.After(rec.CreatedAt))
+	}
+	list = nil
+	db.UpdateReturning(&list, "Qty = ?1", "WHERE id() == ?2", int32(99), int64(2))
+	fmt.Printf("%d %s %d\n", list[0].ID, list[0].Item, list[0].Qty)
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// 1 bolt 5 true
+	// 3 gear 2 true
+	// 2 nut 99
+}
+
+// This example demonstrates the archiving of records as they are deleted.
+func ExampleDbType_DeleteReturning() {
+	type taskType struct {
+		ID   int64  `ql_table:"task"`
+		Name string `ql:"*"`
+		Done bool   `ql:"*"`
+	}
+	type archiveType struct {
+		ID   int64  `ql_table:"archive"`
+		Name string `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&taskType{})
+	db.TableCreate(&archiveType{})
+	db.Insert([]taskType{{0, "plan", true}, {0, "build", false}, {0, "test", true}})
+	db.TransactBegin()
+	var done []taskType
+	db.DeleteReturning(&taskType{}, &done, "WHERE Done")
+	var archive []archiveType
+	for _, rec := range done {
+		archive = append(archive, archiveType{Name: rec.Name})
+	}
+	db.Insert(archive)
+	db.TransactCommit()
+	var list []taskType
+	db.Retrieve(&list, "")
+	fmt.Printf("%d archived, %d remaining\n", len(done), len(list))
+	archive = nil
+	db.Retrieve(&archive, "ORDER BY Name")
+	for _, rec := range archive {
+		fmt.Println(rec.Name)
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// 2 archived, 1 remaining
+	// plan
+	// test
+}
@@ -0,0 +1,198 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+)
+
+// qlTypeAlias maps the ql type names that are aliases to the names that ql
+// reports in its system tables.
+var qlTypeAlias = map[string]string{
+	"byte":  "uint8",
+	"float": "float64",
+	"int":   "int64",
+	"rune":  "int32",
+	"uint":  "uint64",
+}
+
+// qlTypeNorm returns the canonical name of the ql type typeStr.
+func qlTypeNorm(typeStr string) string {
+	if str, ok := qlTypeAlias[typeStr]; ok {
+		return str
+	}
+	return typeStr
+}
+
+// tableColumns returns, in order, the columns of the table tblStr as reported
+// by ql. An empty list is returned if the table does not exist.
+func (db *DbType) tableColumns(tblStr string) (list []ColumnType) {
+	db.RawRetrieve(&list, "SELECT Name, Type, Ordinal FROM __Column WHERE TableName == ?1 ORDER BY Ordinal;", tblStr)
+	return
+}
+
+// dscColumns returns, in order, the columns of the table described by dsc,
+// including the shadow columns maintained by qlm.
+func dscColumns(dsc qlDscType) (list []ColumnType) {
+	for _, nameStr := range dsc.insert.nameList {
+		list = append(list, ColumnType{nameStr, qlTypeNorm(dsc.typeMap[nameStr])})
+	}
+	for _, col := range dsc.collate {
+		list = append(list, ColumnType{col.keyStr, "string"})
+	}
+	return
+}
+
+// SchemaSync reconciles the table associated with the type of the record
+// pointed to by recPtr with the type's definition. If the table does not
+// exist, it is created. Otherwise, a column is added for each field that has
+// no corresponding column, and missing indexes are created. A column whose
+// type differs from that of its field cannot be converted by ql; this results
+// in an error and no change to the table. All changes are made in a single
+// transaction. The statements that were executed are returned.
+//
+// Columns that have no corresponding field are retained and returned in
+// extraList; Insert leaves them NULL. They are not dropped because ql does
+// not reliably support adding columns to a table from which columns have
+// been dropped: subsequent queries of existing records can fail. An
+// application that needs to reclaim the space should copy the records to a
+// new table.
+func (db *DbType) SchemaSync(recPtr interface{}) (cmdList []string, extraList []string) {
+	if db.err != nil {
+		return
+	}
+	dsc := db.dscFromPtr(recPtr)
+	if !db.writable(dsc) {
+		return
+	}
+	haveList := db.tableColumns(dsc.tblStr)
+	if db.err != nil {
+		return
+	}
+	if len(haveList) == 0 {
+		db.TableEnsure(recPtr)
+		if db.err == nil {
+			cmdList = append(cmdList, fmt.Sprintf("CREATE TABLE %s (%s);", dsc.tblStr, dsc.create.nameTypeStr))
+		}
+		return
+	}
+	haveMap := make(map[string]string)
+	for _, col := range haveList {
+		haveMap[col.Name] = col.Type
+	}
+	wantList := dscColumns(dsc)
+	wantMap := make(map[string]bool)
+	for _, col := range wantList {
+		wantMap[col.Name] = true
+		typeStr, ok := haveMap[col.Name]
+		if !ok {
+			strListAppend(&cmdList, "ALTER TABLE %s ADD %s %s;", dsc.tblStr, col.Name, col.Type)
+		} else if typeStr != col.Type && db.err == nil {
+			db.SetErrorf("column %s of table %s has type %s, expected %s",
+				col.Name, dsc.tblStr, typeStr, col.Type)
+		}
+	}
+	for _, col := range haveList {
+		if !wantMap[col.Name] {
+			extraList = append(extraList, col.Name)
+		}
+	}
+	if db.err != nil {
+		return nil, nil
+	}
+	db.TransactBegin()
+	for _, cmdStr := range cmdList {
+		_, _ = db.Exec(cmdStr)
+	}
+	db.tableMake(dsc, true) // Indexes
+	db.transactEnd(db.err == nil)
+	if db.err != nil {
+		cmdList = nil
+	}
+	return
+}
+
+// SchemaDiffType describes one difference between a record type and the
+// table in which its records are stored. Kind is "table" if the table does
+// not exist, "missing" if the column for a field does not exist, "extra" if
+// a column has no corresponding field, and "type" if the type of a column
+// differs from that of its field.
+type SchemaDiffType struct {
+	Kind   string // Kind of difference, for example "missing"
+	Column string // Name of the column; empty if Kind is "table"
+	Want   string // ql type required by the record type; empty if Kind is "extra"
+	Have   string // ql type of the stored column; empty if Kind is "missing"
+}
+
+// String returns a description of the difference, for example "column Email
+// of type string is missing".
+func (diff SchemaDiffType) String() (str string) {
+	switch diff.Kind {
+	case "table":
+		str = "table does not exist"
+	case "missing":
+		str = fmt.Sprintf("column %s of type %s is missing", diff.Column, diff.Want)
+	case "extra":
+		str = fmt.Sprintf("column %s of type %s has no field", diff.Column, diff.Have)
+	default:
+		str = fmt.Sprintf("column %s has type %s, expected %s", diff.Column, diff.Have, diff.Want)
+	}
+	return
+}
+
+// ValidateSchema compares the definition of the record type pointed to by
+// recPtr with the table in which its records are stored and returns the
+// differences. Nothing is modified; an application can call this function
+// when it starts to confirm that its types match a deployed database, and
+// SchemaSync() to resolve the differences that can be resolved. An empty
+// list is returned if the table matches the type.
+func (db *DbType) ValidateSchema(recPtr interface{}) (list []SchemaDiffType) {
+	if db.err != nil {
+		return
+	}
+	dsc := db.dscFromPtr(recPtr)
+	if db.err != nil || len(dsc.view.selStr) > 0 {
+		return
+	}
+	haveList := db.tableColumns(dsc.tblStr)
+	if db.err != nil {
+		return
+	}
+	if len(haveList) == 0 {
+		return []SchemaDiffType{{Kind: "table"}}
+	}
+	haveMap := make(map[string]string)
+	for _, col := range haveList {
+		haveMap[col.Name] = col.Type
+	}
+	wantMap := make(map[string]bool)
+	for _, col := range dscColumns(dsc) {
+		wantMap[col.Name] = true
+		typeStr, ok := haveMap[col.Name]
+		if !ok {
+			list = append(list, SchemaDiffType{"missing", col.Name, col.Type, ""})
+		} else if typeStr != col.Type {
+			list = append(list, SchemaDiffType{"type", col.Name, col.Type, typeStr})
+		}
+	}
+	for _, col := range haveList {
+		if !wantMap[col.Name] {
+			list = append(list, SchemaDiffType{"extra", col.Name, "", col.Type})
+		}
+	}
+	return
+}
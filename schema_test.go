@@ -0,0 +1,95 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the reconciliation of an existing table with a
+// revised record type.
+func ExampleDbType_SchemaSync() {
+	type v1Type struct {
+		ID   int64  `ql_table:"person"`
+		Name string `ql:"*"`
+		Fax  string `ql:"*"`
+	}
+	type v2Type struct {
+		ID    int64  `ql_table:"person"`
+		Name  string `ql:"*"`
+		Email string `ql:"*" ql_index:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&v1Type{})
+	db.Insert([]v1Type{{0, "ann", "555-1234"}})
+	cmdList, extraList := db.SchemaSync(&v2Type{})
+	for _, cmdStr := range cmdList {
+		fmt.Println(cmdStr)
+	}
+	fmt.Printf("unused columns: %v\n", extraList)
+	cmdList, _ = db.SchemaSync(&v2Type{})
+	fmt.Printf("%d statement(s)\n", len(cmdList))
+	var list []v2Type
+	db.Retrieve(&list, "")
+	for _, rec := range list {
+		fmt.Printf("%s [%s]\n", rec.Name, rec.Email)
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// ALTER TABLE person ADD Email string;
+	// unused columns: [Fax]
+	// 0 statement(s)
+	// ann []
+}
+
+// This example demonstrates the comparison of a record type with a stored
+// table.
+func ExampleDbType_ValidateSchema() {
+	type v1Type struct {
+		ID   int64  `ql_table:"person"`
+		Name string `ql:"*"`
+		Age  int64  `ql:"*"`
+		Fax  string `ql:"*"`
+	}
+	type v2Type struct {
+		ID    int64  `ql_table:"person"`
+		Name  string `ql:"*"`
+		Age   int32  `ql:"*"`
+		Email string `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	fmt.Println(db.ValidateSchema(&v1Type{}))
+	db.TableCreate(&v1Type{})
+	fmt.Println(len(db.ValidateSchema(&v1Type{})))
+	for _, diff := range db.ValidateSchema(&v2Type{}) {
+		fmt.Println(diff)
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// [table does not exist]
+	// 0
+	// column Age has type int64, expected int32
+	// column Email of type string is missing
+	// column Fax of type string has no field
+}
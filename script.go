@@ -0,0 +1,121 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+	"github.com/cznic/ql"
+	"strings"
+)
+
+// StmtResultType holds the outcome of one statement executed by ExecScript.
+type StmtResultType struct {
+	Stmt         string         // Text of the statement, without the terminating semicolon
+	Line         int            // Line within the script at which the statement begins
+	Rs           []ql.Recordset // Record sets produced by the statement
+	RowsAffected int64          // Number of rows inserted, updated or deleted
+	Err          error          // Error produced by the statement, if any
+}
+
+// scriptSplit divides scriptStr into statements at semicolons that are not
+// contained in string literals or comments. The starting line of each
+// statement is returned in lineList. Empty statements are omitted.
+func scriptSplit(scriptStr string) (stmtList []string, lineList []int) {
+	line, start, startLine := 1, -1, 0
+	flush := func(end int) {
+		if start >= 0 {
+			stmtList = append(stmtList, strings.TrimSpace(scriptStr[start:end]))
+			lineList = append(lineList, startLine)
+			start = -1
+		}
+	}
+	for j := 0; j < len(scriptStr); j++ {
+		ch := scriptStr[j]
+		switch {
+		case ch == '\n':
+			line++
+			continue
+		case ch == ' ' || ch == '\t' || ch == '\r':
+			continue
+		case ch == '/' && j+1 < len(scriptStr) && scriptStr[j+1] == '/':
+			for j < len(scriptStr) && scriptStr[j] != '\n' {
+				j++
+			}
+			j--
+			continue
+		case ch == '/' && j+1 < len(scriptStr) && scriptStr[j+1] == '*':
+			j += 2
+			for j < len(scriptStr) && !(scriptStr[j] == '*' && j+1 < len(scriptStr) && scriptStr[j+1] == '/') {
+				if scriptStr[j] == '\n' {
+					line++
+				}
+				j++
+			}
+			j++
+			continue
+		case ch == ';':
+			flush(j)
+			continue
+		}
+		if start < 0 {
+			start, startLine = j, line
+		}
+		if ch == '"' || ch == '\'' || ch == '`' {
+			for j++; j < len(scriptStr) && scriptStr[j] != ch; j++ {
+				switch scriptStr[j] {
+				case '\\':
+					if ch != '`' {
+						j++
+					}
+				case '\n':
+					line++
+				}
+			}
+		}
+	}
+	flush(len(scriptStr))
+	return
+}
+
+// ExecScript executes the semicolon-separated ql statements in scriptStr, for
+// example a schema definition or a file of seed data. Comments in the script
+// are permitted. The statements are executed in order within a single
+// transaction; transaction statements in the script itself are nested within
+// it. Execution stops at the first statement that fails, in which case the
+// transaction is rolled back and the database error identifies the failing
+// statement and the line at which it begins. The returned list holds one
+// entry for each statement that was executed, including the failing one.
+func (db *DbType) ExecScript(scriptStr string) (list []StmtResultType) {
+	if db.err != nil {
+		return
+	}
+	stmtList, lineList := scriptSplit(scriptStr)
+	db.TransactBegin()
+	for j := 0; j < len(stmtList) && db.err == nil; j++ {
+		res := StmtResultType{Stmt: stmtList[j], Line: lineList[j]}
+		res.Rs, _ = db.Exec(stmtList[j] + ";")
+		res.Err = db.err
+		if db.err == nil {
+			res.RowsAffected = db.transact.ctx.RowsAffected
+		} else {
+			db.err = fmt.Errorf("statement %d at line %d: %w", j+1, lineList[j], db.err)
+		}
+		list = append(list, res)
+	}
+	db.transactEnd(db.err == nil)
+	return
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the execution of a script that creates a table
+// and populates it. The second script fails at its third statement, so none
+// of its changes are retained. The cause of a failure can still be examined,
+// for example with IsDuplicate().
+func ExampleDbType_ExecScript() {
+	db := qlm.DbCreate("data/example.ql")
+	list := db.ExecScript(`
+		// Schema
+		CREATE TABLE color (name string, hex string);
+		/* Seed data; the semicolon in "a;b" does not end the statement */
+		INSERT INTO color VALUES ("red", "#f00"), ("green", "#0f0");
+		INSERT INTO color VALUES ("a;b", "");
+		SELECT count(*) FROM color;
+	`)
+	for _, res := range list {
+		fmt.Printf("line %d: %d row(s) affected\n", res.Line, res.RowsAffected)
+	}
+	list = db.ExecScript(`
+		DELETE FROM color;
+		INSERT INTO color VALUES ("blue", "#00f");
+		INSERT INTO color VALUES (1, 2);
+		INSERT INTO color VALUES ("white", "#fff");`)
+	fmt.Printf("%d statements executed\n", len(list))
+	fmt.Println(db.Error())
+	db.ClearError()
+	var count int64
+	for _, res := range db.ExecScript("SELECT count(*) FROM color") {
+		res.Rs[0].Do(false, func(data []interface{}) (bool, error) {
+			count = data[0].(int64)
+			return false, nil
+		})
+	}
+	fmt.Printf("%d colors\n", count)
+	db.ExecScript(`
+		CREATE UNIQUE INDEX colorName ON color (name);
+		INSERT INTO color VALUES ("red", "#f00");`)
+	fmt.Println(qlm.IsDuplicate(db.Error()))
+	db.ClearError()
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// line 3: 0 row(s) affected
+	// line 5: 2 row(s) affected
+	// line 6: 1 row(s) affected
+	// line 7: 0 row(s) affected
+	// 3 statements executed
+	// statement 3 at line 4: cannot use 1 (type int64) in assignment to, or comparison with, column name (type string)
+	// 3 colors
+	// true
+}
@@ -0,0 +1,214 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+	"github.com/cznic/ql"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// resultFields returns the expressions and fields of the result structure
+// type tp. Each field that has a "ql" tag contributes the tag value as an
+// expression to select. The expression "*" is replaced with the field name.
+// Fields with a "ql_bucket" tag are returned separately.
+func (db *DbType) resultFields(tp reflect.Type) (exprList []string, sfList, bucketList []reflect.StructField) {
+	if tp.Kind() != reflect.Struct {
+		db.SetErrorf("result type must be a structure, got %v", tp.Kind())
+		return
+	}
+	for j := 0; j < tp.NumField(); j++ {
+		sf := tp.Field(j)
+		exprStr := sf.Tag.Get("ql")
+		if exprStr == "*" {
+			exprStr = sf.Name
+		}
+		if len(exprStr) > 0 {
+			exprList = append(exprList, exprStr)
+			sfList = append(sfList, sf)
+		} else if len(sf.Tag.Get("ql_bucket")) > 0 {
+			bucketList = append(bucketList, sf)
+		}
+	}
+	if len(exprList) == 0 {
+		db.SetErrorf(`no fields of result type %v have "ql" tag`, tp)
+	}
+	return
+}
+
+// assignVal assigns the value val retrieved from ql to the field fldVl. A nil
+// value, corresponding to NULL, results in the zero value. Numeric values are
+// converted to the type of the field if necessary. A blob assigned to a map,
+// slice, array, structure or pointer field is decoded as JSON. A field whose
+// address implements Scanner assigns the value itself.
+func assignVal(fldVl reflect.Value, val interface{}) (err error) {
+	if ok, err := scannerLoad(fldVl, val); ok {
+		return err
+	}
+	if val == nil {
+		fldVl.Set(reflect.Zero(fldVl.Type()))
+		return
+	}
+	vl := reflect.ValueOf(val)
+	if vl.Kind() == reflect.Ptr && vl.Type().Elem() == fldVl.Type() {
+		vl = vl.Elem() // *big.Int, *big.Rat
+	}
+	switch {
+	case vl.Type().AssignableTo(fldVl.Type()):
+		fldVl.Set(vl)
+	case vl.Type() == blobType && jsonKindMap[fldVl.Kind()]:
+		if vl, err = jsonLoad(fldVl.Type(), val); err == nil {
+			fldVl.Set(vl)
+		}
+	case vl.Type().ConvertibleTo(fldVl.Type()) && vl.Kind() != reflect.String:
+		fldVl.Set(vl.Convert(fldVl.Type()))
+	default:
+		err = fmt.Errorf("cannot assign value of type %v to field of type %v", vl.Type(), fldVl.Type())
+	}
+	return
+}
+
+// retrieveResult executes the SELECT statement cmdStr and appends a record
+// to the slice pointed to by slicePtr for each resulting row. After the first
+// skip columns, the columns are assigned in order to the fields in sfList. If
+// fn is not nil, it is called with each record and the row before the record
+// is appended.
+func (db *DbType) retrieveResult(slicePtr interface{}, sfList []reflect.StructField, skip int,
+	fn func(recVl reflect.Value, data []interface{}), cmdStr string, prms ...interface{}) {
+	rs, _ := db.Exec(cmdStr, prms...)
+	if db.err == nil {
+		colList := make([]int, len(sfList))
+		for j := range colList {
+			colList[j] = skip + j
+		}
+		db.err = decodeRows(rs, reflect.ValueOf(slicePtr), sfList, colList, fn)
+	}
+}
+
+// decodeRows appends a record to the slice pointed to by slicePtrVl for each
+// row of the record sets in rs. Column colList[j] of each row is assigned to
+// the field sfList[j]. If fn is not nil, it is called with each record and
+// the row before the record is appended.
+func decodeRows(rs []ql.Recordset, slicePtrVl reflect.Value, sfList []reflect.StructField,
+	colList []int, fn func(recVl reflect.Value, data []interface{})) (err error) {
+	sliceVl := slicePtrVl.Elem()
+	recVl := reflect.Indirect(reflect.New(sliceVl.Type().Elem())) // Buffer
+	vList := valueList(recVl, sfList)
+	load := func(data []interface{}) (more bool, err error) {
+		for j, fldVl := range vList {
+			if err == nil && colList[j] < len(data) {
+				err = assignVal(fldVl, data[colList[j]])
+			}
+		}
+		if err == nil {
+			if fn != nil {
+				fn(recVl, data)
+			}
+			sliceVl = reflect.Append(sliceVl, recVl)
+			more = true
+		}
+		return
+	}
+	for _, res := range rs {
+		if err == nil {
+			err = res.Do(false, load)
+		}
+	}
+	if err == nil {
+		slicePtrVl.Elem().Set(sliceVl)
+	}
+	return
+}
+
+// slicePtrCheck verifies that slicePtr is a pointer to a slice of structures.
+func (db *DbType) slicePtrCheck(slicePtr interface{}, fncStr string) (recTp reflect.Type) {
+	tp := reflect.TypeOf(slicePtr)
+	if tp == nil || tp.Kind() != reflect.Ptr || tp.Elem().Kind() != reflect.Slice {
+		db.SetErrorf("function %s expecting pointer to slice, got %v", fncStr, tp)
+	} else {
+		recTp = tp.Elem().Elem()
+	}
+	return
+}
+
+// RetrieveBuckets groups the records of the type pointed to by recPtr into
+// consecutive intervals of the specified duration, based on the time column
+// tmFldStr, and appends a summary of each interval to the slice pointed to by
+// slicePtr. The elements of this slice are result structures whose "ql" tags
+// specify aggregate expressions of the columns of the table. A time.Time field
+// with the tag `ql_bucket:"*"` receives the start of the interval. For
+// example,
+//
+//	type statType struct {
+//		Start time.Time `ql_bucket:"*"`
+//		Count int64     `ql:"count(*)"`
+//		Avg   float64   `ql:"avg(Latency)"`
+//		Max   float64   `ql:"max(Latency)"`
+//	}
+//	db.RetrieveBuckets(&stats, &sampleType{}, "Tm", time.Hour, "WHERE Host == ?1", host)
+//
+// Intervals are aligned to the Unix epoch, on either side of it, and are
+// appended in chronological order. Intervals that contain no records are omitted. Only the WHERE clause
+// of tailStr is used.
+func (db *DbType) RetrieveBuckets(slicePtr interface{}, recPtr interface{}, tmFldStr string,
+	dur time.Duration, tailStr string, prms ...interface{}) {
+	if db.err != nil {
+		return
+	}
+	resTp := db.slicePtrCheck(slicePtr, "RetrieveBuckets")
+	dsc := db.dscFromPtr(recPtr)
+	if db.err != nil {
+		return
+	}
+	exprList, sfList, bucketList := db.resultFields(resTp)
+	if sf, ok := dsc.nameMap[tmFldStr]; !ok || sf.Type != reflect.TypeOf(time.Time{}) {
+		db.SetErrorf("time field %s not found in table %s", tmFldStr, dsc.tblStr)
+	} else if dur <= 0 {
+		db.SetErrorf("bucket duration must be positive")
+	}
+	if db.err == nil {
+		if len(dsc.soft.nameStr) > 0 {
+			tailStr = whereAnd(tailStr, dsc.soft.nameStr+" IS NULL")
+		}
+		whereStr, _ := tailSplit(tailStr)
+		if len(whereStr) > 0 {
+			whereStr = " WHERE " + whereStr
+		}
+		n := len(prms)
+		epoch := time.Unix(0, 0).UTC()
+		// Division truncates toward zero, so the offset from the epoch is first
+		// reduced by its floored remainder to place earlier times correctly:
+		// SELECT qlm_bucket, count(*), avg(Latency) FROM (SELECT
+		// int64(((Tm - ?2) - ((Tm - ?2) % ?3 + ?3) % ?3) / ?3) AS qlm_bucket,
+		// Tm, Latency, ... FROM sample WHERE Host == ?1) GROUP BY qlm_bucket
+		// ORDER BY qlm_bucket;
+		offStr := fmt.Sprintf("(%s - ?%d)", tmFldStr, n+1)
+		cmdStr := fmt.Sprintf("SELECT qlm_bucket, %s FROM (SELECT int64((%s - (%s %% ?%d + ?%d) %% ?%d) / ?%d) "+
+			"AS qlm_bucket, %s FROM %s%s) GROUP BY qlm_bucket ORDER BY qlm_bucket;", strings.Join(exprList, ", "),
+			offStr, offStr, n+2, n+2, n+2, n+2, dsc.insert.nameStr, dsc.fromStr(), whereStr)
+		fn := func(recVl reflect.Value, data []interface{}) {
+			start := epoch.Add(time.Duration(data[0].(int64)) * dur)
+			for _, fldVl := range valueList(recVl, bucketList) {
+				fldVl.Set(reflect.ValueOf(start))
+			}
+		}
+		// The bucket number precedes the result expressions
+		db.retrieveResult(slicePtr, sfList, 1, fn, cmdStr, append(prms, epoch, dur)...)
+	}
+}
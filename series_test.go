@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+	"time"
+)
+
+// This example demonstrates the summary of time-series data in hourly
+// intervals. The result structure specifies aggregate expressions in its
+// "ql" tags and receives the start of each interval in the field tagged with
+// "ql_bucket".
+func ExampleDbType_RetrieveBuckets() {
+	type sampleType struct {
+		ID      int64     `ql_table:"sample"`
+		Host    string    `ql:"*"`
+		Tm      time.Time `ql:"*"`
+		Latency float64   `ql:"*"`
+	}
+	type statType struct {
+		Start time.Time `ql_bucket:"*"`
+		Count int64     `ql:"count(*)"`
+		Avg   float64   `ql:"avg(Latency)"`
+		Max   float64   `ql:"max(Latency)"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&sampleType{})
+	tm := time.Date(2015, 6, 1, 9, 0, 0, 0, time.UTC)
+	var list []sampleType
+	for j := 0; j < 12; j++ {
+		list = append(list, sampleType{0, "alpha", tm.Add(time.Duration(j) * 20 * time.Minute), float64(j * 10)})
+		list = append(list, sampleType{0, "beta", tm.Add(time.Duration(j) * 20 * time.Minute), 1})
+	}
+	db.Insert(list)
+	var stats []statType
+	db.RetrieveBuckets(&stats, &sampleType{}, "Tm", time.Hour, "WHERE Host == ?1", "alpha")
+	for _, s := range stats {
+		fmt.Printf("%s %d %5.1f %5.1f\n", s.Start.Format("15:04"), s.Count, s.Avg, s.Max)
+	}
+	// Times before the epoch fall into the intervals that contain them
+	old := time.Date(1969, 12, 31, 22, 30, 0, 0, time.UTC)
+	db.Insert([]sampleType{{0, "gamma", old, 1}, {0, "gamma", old.Add(time.Hour), 2},
+		{0, "gamma", old.Add(2 * time.Hour), 3}})
+	stats = nil
+	db.RetrieveBuckets(&stats, &sampleType{}, "Tm", time.Hour, "WHERE Host == ?1", "gamma")
+	for _, s := range stats {
+		fmt.Printf("%s %d %5.1f\n", s.Start.Format("2006-01-02 15:04"), s.Count, s.Max)
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// 09:00 3  10.0  20.0
+	// 10:00 3  40.0  50.0
+	// 11:00 3  70.0  80.0
+	// 12:00 3 100.0 110.0
+	// 1969-12-31 22:00 1   1.0
+	// 1969-12-31 23:00 1   2.0
+	// 1970-01-01 00:00 1   3.0
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"errors"
+	"sync"
+)
+
+// SessionType is an independent unit of work on an open database. It has the
+// methods of DbType, such as Insert(), Retrieve() and TransactBegin(), but
+// its own transaction and error state, so that each goroutine that uses the
+// database can be given a session of its own. Sessions share the ql handle,
+// the caches of table descriptors and compiled statements, and the
+// configuration of the database from which they were made. A session that
+// changes its naming settings with SetTablePrefix(), SetNamer() or AutoMap()
+// gets a descriptor cache of its own, so the change does not reach the
+// database or its other sessions.
+type SessionType struct {
+	*DbType
+}
+
+// Session returns a new session on db. The session begins with no error and
+// no pending transaction, regardless of the state of db. Its settings, such
+// as the logger, are copied from db when the session is made. Registrations
+// that are shared, such as those made by View(), AuditEnable(), Subscribe()
+// and Attach(), should be completed with db before sessions are made; they
+// must not be changed while sessions are in use.
+//
+// ql serializes transactions: a session that begins a transaction waits until
+// any transaction pending in another session has ended. Statements executed
+// outside a transaction, such as those of a simple Retrieve(), run
+// concurrently. Close() should be called when the session is no longer
+// needed; it does not close db.
+func (db *DbType) Session() (s *SessionType) {
+	sd := *db
+	sd.transact = transactType{}
+	sd.ctx = nil
+	sd.tblMap = nil
+	sd.execMu = new(sync.Mutex)
+	sd.transient = false
+	sd.notify.pending = nil
+	sd.err = nil
+	if db.Hnd == nil {
+		sd.err = db.err
+		if sd.err == nil {
+			sd.err = errors.New("database is not open")
+		}
+	}
+	return &SessionType{&sd}
+}
+
+// Close ends the session. Any transaction that is pending in the session is
+// rolled back. The database from which the session was made remains open.
+func (s *SessionType) Close() {
+	for s.transact.nest > 0 {
+		nest := s.transact.nest
+		s.transactEnd(false)
+		if s.transact.nest == nest {
+			break
+		}
+	}
+	s.Hnd = nil
+}
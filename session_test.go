@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+	"sync"
+)
+
+// This example demonstrates the use of one database by several goroutines,
+// each with a session of its own.
+func ExampleDbType_Session() {
+	type recType struct {
+		ID     int64  `ql_table:"rec"`
+		Worker int64  `ql:"*"`
+		Name   string `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&recType{})
+	var wg sync.WaitGroup
+	for w := int64(1); w <= 4; w++ {
+		wg.Add(1)
+		go func(w int64) {
+			defer wg.Done()
+			s := db.Session()
+			defer s.Close()
+			for j := 0; j < 25; j++ {
+				s.Insert([]recType{{Worker: w, Name: fmt.Sprintf("rec %d", j)}})
+			}
+			// An error in one session does not affect the others
+			if w == 2 {
+				s.Retrieve(&[]recType{}, "WHERE Missing == 1")
+			}
+			var list []recType
+			s.Retrieve(&list, "WHERE Worker == ?1", w)
+			if s.Err() {
+				if w != 2 {
+					fmt.Println(s.Error())
+				}
+			} else if len(list) != 25 {
+				fmt.Println(w, len(list))
+			}
+		}(w)
+	}
+	wg.Wait()
+	var list []recType
+	db.Retrieve(&list, "")
+	fmt.Println(len(list))
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// 100
+}
+
+// This example demonstrates that the statements executed by goroutines that
+// share one DbType, rather than using sessions, are serialized.
+func ExampleDbType_Exec_concurrent() {
+	type recType struct {
+		ID   int64  `ql_table:"rec"`
+		Name string `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&recType{})
+	db.Insert([]recType{{Name: "alpha"}, {Name: "beta"}})
+	var wg sync.WaitGroup
+	countList := make([]int64, 8)
+	for w := range countList {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				rs, _ := db.Exec("SELECT count(*) FROM rec;")
+				if len(rs) > 0 {
+					row, _ := rs[0].FirstRow()
+					countList[w] += row[0].(int64)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	fmt.Println(countList, db.Error())
+	db.Close()
+	// Output:
+	// [50 50 50 50 50 50 50 50] <nil>
+}
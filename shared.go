@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"github.com/cznic/ql"
+	"reflect"
+	"sync"
+)
+
+// sharedType holds the statement cache and statistics of a database. They are
+// guarded by mu so that they may be used safely by more than one goroutine.
+type sharedType struct {
+	mu sync.Mutex
+	// Cache for executable commands; see CacheLen()
+	cache stmtCacheType
+	// Execution statistics of statements, keyed by text; see Stats()
+	statMap map[string]*StmtStatType
+}
+
+// dscCacheType holds the table descriptors of a database. The descriptors
+// depend on the naming settings of the instance that builds them, so an
+// instance that changes these settings, for example a session that calls
+// SetTablePrefix(), replaces its cache rather than clearing one that other
+// instances share.
+type dscCacheType struct {
+	mu     sync.Mutex
+	dscMap map[reflect.Type]qlDscType
+}
+
+// dscCacheNew returns an empty descriptor cache.
+func dscCacheNew() *dscCacheType {
+	return &dscCacheType{dscMap: make(map[reflect.Type]qlDscType)}
+}
+
+// get returns the cached descriptor of the record type recTp.
+func (dc *dscCacheType) get(recTp reflect.Type) (dsc qlDscType, ok bool) {
+	dc.mu.Lock()
+	dsc, ok = dc.dscMap[recTp]
+	dc.mu.Unlock()
+	return
+}
+
+// put caches dsc as the descriptor of the record type recTp.
+func (dc *dscCacheType) put(recTp reflect.Type, dsc qlDscType) {
+	dc.mu.Lock()
+	dc.dscMap[recTp] = dsc
+	dc.mu.Unlock()
+}
+
+// remove removes the cached descriptor of the record type recTp.
+func (dc *dscCacheType) remove(recTp reflect.Type) {
+	dc.mu.Lock()
+	delete(dc.dscMap, recTp)
+	dc.mu.Unlock()
+}
+
+// list returns the cached descriptors.
+func (dc *dscCacheType) list() (list []qlDscType) {
+	dc.mu.Lock()
+	for _, dsc := range dc.dscMap {
+		list = append(list, dsc)
+	}
+	dc.mu.Unlock()
+	return
+}
+
+// stmtGet returns the compiled statement for cmdStr if it is cached.
+func (shr *sharedType) stmtGet(cmdStr string) (l ql.List, ok bool) {
+	shr.mu.Lock()
+	l, ok = shr.cache.get(cmdStr)
+	shr.mu.Unlock()
+	return
+}
+
+// stmtPut caches the compiled statement l for cmdStr.
+func (shr *sharedType) stmtPut(cmdStr string, l ql.List) {
+	shr.mu.Lock()
+	shr.cache.put(cmdStr, l)
+	shr.mu.Unlock()
+}
+
+// stmtClear discards all cached statements.
+func (shr *sharedType) stmtClear() {
+	shr.mu.Lock()
+	shr.cache.init()
+	shr.mu.Unlock()
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// numericColumn returns the WHERE clause, prefixed with a space if not empty,
+// to be used when scanning the numeric column fldStr of the table described
+// by dsc.
+func (db *DbType) numericColumn(dsc qlDscType, fldStr, tailStr string) (whereStr string) {
+	sf, ok := dsc.nameMap[fldStr]
+	if !ok {
+		db.SetErrorf("field %s not found in table %s", fldStr, dsc.tblStr)
+		return
+	}
+	switch sf.Type.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+	default:
+		db.SetErrorf("numeric field expected, got %v", sf.Type)
+		return
+	}
+	if len(dsc.soft.nameStr) > 0 {
+		tailStr = whereAnd(tailStr, dsc.soft.nameStr+" IS NULL")
+	}
+	whereStr, _ = tailSplit(tailStr)
+	if len(whereStr) > 0 {
+		whereStr = " WHERE " + whereStr
+	}
+	return
+}
+
+// float returns the numeric value val as a float64.
+func float(val interface{}) float64 {
+	vl := reflect.ValueOf(val)
+	switch vl.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(vl.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(vl.Uint())
+	}
+	return vl.Float()
+}
+
+// Histogram counts the values of the numeric column fldStr in the records of
+// the type pointed to by recPtr that satisfy the WHERE clause of tailStr and
+// its parameters. The ascending values in edges divide the number line into
+// len(edges)+1 bins. The returned slice holds the count of values less than
+// edges[0], followed by the counts of values in [edges[j], edges[j+1]), and
+// finally the count of values greater than or equal to the last edge. The
+// records are examined in a single scan without being materialized. NULL
+// values are not counted.
+func (db *DbType) Histogram(recPtr interface{}, fldStr string, edges []float64,
+	tailStr string, prms ...interface{}) (counts []int64) {
+	if db.err != nil {
+		return
+	}
+	if !sort.Float64sAreSorted(edges) {
+		db.SetErrorf("histogram edges must be in ascending order")
+		return
+	}
+	dsc := db.dscFromPtr(recPtr)
+	if db.err == nil {
+		whereStr := db.numericColumn(dsc, fldStr, tailStr)
+		rs, _ := db.Exec(fmt.Sprintf("SELECT %s FROM %s%s;", fldStr, dsc.fromStr(), whereStr), prms...)
+		if db.err == nil {
+			counts = make([]int64, len(edges)+1)
+			for _, res := range rs {
+				if db.err == nil {
+					db.err = res.Do(false, func(data []interface{}) (bool, error) {
+						if data[0] != nil {
+							counts[sort.SearchFloat64s(edges, math.Nextafter(float(data[0]), math.Inf(1)))]++
+						}
+						return true, nil
+					})
+				}
+			}
+		}
+	}
+	return
+}
+
+// Percentiles returns the values of the numeric column fldStr at each of the
+// percentiles (from 0 to 100) in pctList, considering the records of the type
+// pointed to by recPtr that satisfy the WHERE clause of tailStr and its
+// parameters. Values between ranks are linearly interpolated. The records are
+// counted and then scanned in column order only as far as the highest
+// requested percentile, without being materialized. NaN is returned for each
+// percentile if there are no values.
+func (db *DbType) Percentiles(recPtr interface{}, fldStr string, pctList []float64,
+	tailStr string, prms ...interface{}) (vals []float64) {
+	if db.err != nil {
+		return
+	}
+	dsc := db.dscFromPtr(recPtr)
+	if db.err != nil {
+		return
+	}
+	whereStr := db.numericColumn(dsc, fldStr, tailStr)
+	whereStr = whereAnd(whereStr, fldStr+" IS NOT NULL")
+	row := db.firstRow(fmt.Sprintf("SELECT count(*) FROM %s %s;", dsc.fromStr(), whereStr), prms...)
+	if db.err != nil {
+		return
+	}
+	var count int64
+	if len(row) > 0 {
+		count = row[0].(int64)
+	}
+	vals = make([]float64, len(pctList))
+	// Map each needed rank to the positions in vals that depend on it
+	rankMap := make(map[int64][]int)
+	var maxRank int64
+	for j, pct := range pctList {
+		if pct < 0 || pct > 100 {
+			db.SetErrorf("percentile %v is out of range", pct)
+			return
+		}
+		vals[j] = math.NaN()
+		if count > 0 {
+			pos := pct / 100 * float64(count-1)
+			lo := int64(math.Floor(pos))
+			hi := int64(math.Ceil(pos))
+			rankMap[lo] = append(rankMap[lo], j)
+			rankMap[hi] = append(rankMap[hi], j)
+			if hi > maxRank {
+				maxRank = hi
+			}
+		}
+	}
+	if count == 0 {
+		return
+	}
+	rankVal := make(map[int64]float64)
+	rs, _ := db.Exec(fmt.Sprintf("SELECT %s FROM %s %s ORDER BY %s;",
+		fldStr, dsc.fromStr(), whereStr, fldStr), prms...)
+	var rank int64
+	for _, res := range rs {
+		if db.err == nil {
+			db.err = res.Do(false, func(data []interface{}) (bool, error) {
+				if _, ok := rankMap[rank]; ok {
+					rankVal[rank] = float(data[0])
+				}
+				rank++
+				return rank <= maxRank, nil
+			})
+		}
+	}
+	if db.err == nil {
+		for j, pct := range pctList {
+			pos := pct / 100 * float64(count-1)
+			lo := math.Floor(pos)
+			loVal, hiVal := rankVal[int64(lo)], rankVal[int64(math.Ceil(pos))]
+			vals[j] = loVal + (hiVal-loVal)*(pos-lo)
+		}
+	}
+	return
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the distribution statistics of a numeric column.
+// The histogram edges divide the values into bins; the first bin counts values
+// below the first edge and the last bin counts values at or above the last
+// edge.
+func ExampleDbType_Histogram() {
+	type reqType struct {
+		ID      int64  `ql_table:"req"`
+		Path    string `ql:"*"`
+		Latency int64  `ql:"latency_ms"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&reqType{})
+	var list []reqType
+	for j := int64(1); j <= 100; j++ {
+		list = append(list, reqType{0, "/", j})
+	}
+	list = append(list, reqType{0, "/slow", 5000})
+	db.Insert(list)
+	fmt.Println(db.Histogram(&reqType{}, "latency_ms", []float64{10, 50, 100}, "WHERE Path == ?1", "/"))
+	for _, val := range db.Percentiles(&reqType{}, "latency_ms", []float64{0, 50, 90, 99, 100}, "WHERE Path == ?1", "/") {
+		fmt.Printf("%.2f\n", val)
+	}
+	fmt.Println(db.Percentiles(&reqType{}, "latency_ms", []float64{50}, "WHERE Path == ?1", "/none"))
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// [9 40 50 1]
+	// 1.00
+	// 50.50
+	// 90.10
+	// 99.01
+	// 100.00
+	// [NaN]
+}
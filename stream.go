@@ -0,0 +1,122 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"reflect"
+)
+
+// InsertFrom receives records from the channel ch, for example a value of
+// type chan recType or <-chan recType, and inserts them into the database in
+// transactions of up to batchSize records until the channel is closed. This
+// allows records to be produced by another goroutine, such as a parser or a
+// network reader, while they are stored. The number of records inserted is
+// returned. If an error occurs, it is assigned to the database and the
+// remaining records are received and discarded so that the producer is not
+// blocked; the producer can call Err() to stop early. Progress, if
+// registered, is reported for the operation as a whole.
+func (db *DbType) InsertFrom(ch interface{}, batchSize int) (count int64) {
+	if db.err != nil {
+		return
+	}
+	chVl := reflect.ValueOf(ch)
+	if chVl.Kind() != reflect.Chan || chVl.Type().ChanDir()&reflect.RecvDir == 0 {
+		db.SetErrorf("function InsertFrom expecting receivable channel, got %v", chVl.Kind())
+		return
+	}
+	if batchSize < 1 {
+		db.SetErrorf("batch size must be positive, got %d", batchSize)
+		return
+	}
+	trk := db.progressStart("InsertFrom", -1)
+	fn := db.progress.fn
+	db.progress.fn = nil // Suppress reports of individual batches
+	sliceVl := reflect.MakeSlice(reflect.SliceOf(chVl.Type().Elem()), 0, batchSize)
+	flush := func() {
+		if sliceVl.Len() > 0 && db.err == nil {
+			db.Insert(sliceVl.Interface())
+			if db.err == nil {
+				count += int64(sliceVl.Len())
+				trk.add(int64(sliceVl.Len()))
+			}
+		}
+		sliceVl = sliceVl.Slice(0, 0)
+	}
+	for {
+		recVl, ok := chVl.Recv()
+		if !ok {
+			break
+		}
+		if db.err == nil {
+			sliceVl = reflect.Append(sliceVl, recVl)
+			if sliceVl.Len() == batchSize {
+				flush()
+			}
+		}
+	}
+	flush()
+	db.progress.fn = fn
+	if db.err == nil {
+		trk.end()
+	}
+	return
+}
+
+// InsertBatched inserts the records in slice, like Insert(), but commits a
+// separate transaction for each successive group of up to batchSize records
+// rather than holding one transaction for the whole slice. This bounds the
+// memory and write-ahead log used when very large numbers of records are
+// loaded. The ID fields of the inserted records are assigned in place. The
+// number of records committed is returned; if an error occurs, the records
+// that follow them have not been stored and the load can be resumed from
+// that point. The batches are committed only if no transaction is pending
+// when InsertBatched is called. Progress, if registered, is reported for the
+// operation as a whole.
+func (db *DbType) InsertBatched(slice interface{}, batchSize int) (count int64) {
+	if db.err != nil {
+		return
+	}
+	sliceVl := reflect.ValueOf(slice)
+	if sliceVl.Kind() != reflect.Slice {
+		db.SetErrorf("function InsertBatched requires slice as first argument")
+		return
+	}
+	if batchSize < 1 {
+		db.SetErrorf("batch size must be positive, got %d", batchSize)
+		return
+	}
+	total := sliceVl.Len()
+	trk := db.progressStart("InsertBatched", int64(total))
+	fn := db.progress.fn
+	db.progress.fn = nil // Suppress reports of individual batches
+	for startJ := 0; startJ < total && db.err == nil; startJ += batchSize {
+		endJ := startJ + batchSize
+		if endJ > total {
+			endJ = total
+		}
+		db.Insert(sliceVl.Slice(startJ, endJ).Interface())
+		if db.err == nil {
+			count += int64(endJ - startJ)
+			trk.add(int64(endJ - startJ))
+		}
+	}
+	db.progress.fn = fn
+	if db.err == nil {
+		trk.end()
+	}
+	return
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the insertion of records that are produced by
+// another goroutine.
+func ExampleDbType_InsertFrom() {
+	type lineType struct {
+		ID   int64  `ql_table:"line"`
+		Num  int32  `ql:"*"`
+		Text string `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&lineType{})
+	ch := make(chan lineType)
+	go func() {
+		for j := 1; j <= 250; j++ {
+			ch <- lineType{Num: int32(j), Text: fmt.Sprintf("line %d", j)}
+		}
+		close(ch)
+	}()
+	db.Progress(100, func(pr qlm.ProgressType) {
+		fmt.Printf("%s: %d\n", pr.Op, pr.Done)
+	})
+	count := db.InsertFrom(ch, 40)
+	fmt.Printf("%d records inserted\n", count)
+	var list []lineType
+	db.Retrieve(&list, "WHERE Num == 250")
+	if len(list) == 1 {
+		fmt.Println(list[0].Text)
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// InsertFrom: 120
+	// InsertFrom: 240
+	// InsertFrom: 250
+	// 250 records inserted
+	// line 250
+}
+
+// This example demonstrates the insertion of a large slice in separately
+// committed batches.
+func ExampleDbType_InsertBatched() {
+	type lineType struct {
+		ID   int64  `ql_table:"line"`
+		Num  int32  `ql:"*,unique"`
+		Text string `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&lineType{})
+	list := make([]lineType, 250)
+	for j := range list {
+		list[j] = lineType{Num: int32(j + 1), Text: fmt.Sprintf("line %d", j+1)}
+	}
+	// Introduce a duplicate in the third batch
+	list[210].Num = 1
+	count := db.InsertBatched(list, 100)
+	fmt.Println(count, qlm.IsDuplicate(db.Error()), list[199].ID > 0)
+	db.ClearError()
+	list[210].Num = 211
+	count += db.InsertBatched(list[count:], 100)
+	fmt.Printf("%d records inserted\n", count)
+	var recs []lineType
+	db.Retrieve(&recs, "")
+	fmt.Println(len(recs))
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// 200 true true
+	// 250 records inserted
+	// 250
+}
@@ -0,0 +1,80 @@
+//go:build go1.18
+
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+// TableType provides typed access to the table associated with the record
+// type T. Its methods are thin wrappers around the corresponding DbType
+// methods, so the tags of T are interpreted as they are elsewhere in qlm, but
+// records and slices are checked by the compiler rather than at run time.
+// Each method returns the current qlm error. As with the rest of qlm, this
+// error is retained by the underlying DbType instance until ClearError() is
+// called.
+type TableType[T any] struct {
+	db *DbType
+}
+
+// Table returns a typed wrapper for the table of record type T in db, for
+// example
+//
+//	users := qlm.Table[userType](db)
+//	list, err := users.Retrieve("WHERE Name == ?1", "Ann")
+func Table[T any](db *DbType) *TableType[T] {
+	return &TableType[T]{db: db}
+}
+
+// Db returns the database instance used by tbl.
+func (tbl *TableType[T]) Db() *DbType {
+	return tbl.db
+}
+
+// Create creates the table and its indexes; see DbType.TableCreate().
+func (tbl *TableType[T]) Create() error {
+	var rec T
+	tbl.db.TableCreate(&rec)
+	return tbl.db.Error()
+}
+
+// Insert inserts the records in list and assigns their ID fields; see
+// DbType.Insert().
+func (tbl *TableType[T]) Insert(list []T) error {
+	tbl.db.Insert(list)
+	return tbl.db.Error()
+}
+
+// Retrieve returns the records that satisfy tailStr and its parameters; see
+// DbType.Retrieve().
+func (tbl *TableType[T]) Retrieve(tailStr string, prms ...interface{}) (list []T, err error) {
+	tbl.db.Retrieve(&list, tailStr, prms...)
+	return list, tbl.db.Error()
+}
+
+// Update updates the fields named in fldNames of the record pointed to by
+// rec; see DbType.Update().
+func (tbl *TableType[T]) Update(rec *T, fldNames ...string) error {
+	tbl.db.Update(rec, fldNames...)
+	return tbl.db.Error()
+}
+
+// Delete deletes the records that satisfy tailStr and its parameters; see
+// DbType.Delete().
+func (tbl *TableType[T]) Delete(tailStr string, prms ...interface{}) error {
+	var rec T
+	tbl.db.Delete(&rec, tailStr, prms...)
+	return tbl.db.Error()
+}
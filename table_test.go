@@ -0,0 +1,55 @@
+//go:build go1.18
+
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates typed table access.
+func ExampleTable() {
+	type userType struct {
+		ID   int64  `ql_table:"user"`
+		Name string `ql:"*"`
+		Age  int64  `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	users := qlm.Table[userType](db)
+	users.Create()
+	users.Insert([]userType{{0, "Ann", 31}, {0, "Bob", 42}, {0, "Cy", 27}})
+	list, err := users.Retrieve("WHERE Age > ?1 ORDER BY Name", int64(30))
+	if err == nil {
+		rec := list[0]
+		rec.Age++
+		users.Update(&rec, "Age")
+		users.Delete("WHERE Name == ?1", "Cy")
+		list, err = users.Retrieve("ORDER BY Name")
+	}
+	for _, rec := range list {
+		fmt.Println(rec.Name, rec.Age)
+	}
+	db.Close()
+	if err = db.Error(); err != nil {
+		fmt.Println(err)
+	}
+	// Output:
+	// Ann 32
+	// Bob 42
+}
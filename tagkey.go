@@ -0,0 +1,74 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"reflect"
+	"sync"
+)
+
+// tagKey holds the key of the struct tag that names the column of a field;
+// see SetTagKey().
+var tagKey = struct {
+	sync.RWMutex
+	str string
+}{str: "ql"}
+
+// SetTagKey establishes keyStr as the key of the struct tag that specifies
+// the column name and options of a field, in place of "ql". This allows
+// record types that are already tagged for another library to be used
+// without duplicating their tags. For example, after
+//
+//	qlm.SetTagKey("db")
+//
+// the field declared as
+//
+//	Name string `db:"name"`
+//
+// is stored in the column name. The "ql_table", "ql_index" and other tags
+// are not affected. An empty keyStr restores "ql". The key applies to all
+// qlm instances. SetTagKey should be called when the program starts, before
+// record types are used; descriptors that have already been built by open
+// instances are not rebuilt, and types registered with Register() must be
+// registered again. The types that qlm declares for its own tables, such as
+// Model, continue to use the "ql" key.
+func SetTagKey(keyStr string) {
+	if len(keyStr) == 0 {
+		keyStr = "ql"
+	}
+	tagKey.Lock()
+	tagKey.str = keyStr
+	tagKey.Unlock()
+	registry.Lock()
+	registry.dscMap = make(map[reflect.Type]qlDscType)
+	registry.Unlock()
+}
+
+// qlTag returns the value of the tag that specifies the column name and
+// options of the field sf of the structure type tp. The fields of types that
+// are declared by qlm, including those of an embedded Model, are always
+// described by the "ql" key.
+func qlTag(tp reflect.Type, sf reflect.StructField) string {
+	if tp.PkgPath() == qlmPkgStr ||
+		len(sf.Index) > 1 && modelMap[tp.FieldByIndex(sf.Index[:len(sf.Index)-1]).Type] {
+		return sf.Tag.Get("ql")
+	}
+	tagKey.RLock()
+	keyStr := tagKey.str
+	tagKey.RUnlock()
+	return sf.Tag.Get(keyStr)
+}
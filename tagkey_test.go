@@ -0,0 +1,54 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+	"strings"
+)
+
+// This example demonstrates the use of record types that are tagged for
+// another library.
+func ExampleSetTagKey() {
+	type userType struct {
+		qlm.Model `ql_table:"user"`
+		Name      string `db:"name" json:"name"`
+		Email     string `db:"email_addr,unique" json:"email"`
+		Internal  string `db:"-" ql:"internal"`
+	}
+	qlm.SetTagKey("db")
+	defer qlm.SetTagKey("")
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&userType{})
+	db.Insert([]userType{{Name: "Ann", Email: "ann@example.com", Internal: "x"}})
+	var strList []string
+	for _, col := range db.Columns("user") {
+		strList = append(strList, col.Name)
+	}
+	fmt.Println(strings.Join(strList, " "))
+	var list []userType
+	db.Retrieve(&list, "WHERE email_addr == ?1", "ann@example.com")
+	fmt.Println(len(list), list[0].Name, list[0].CreatedAt.IsZero(), len(list[0].Internal))
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// CreatedAt UpdatedAt name email_addr
+	// 1 Ann false 0
+}
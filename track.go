@@ -0,0 +1,153 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// tracker holds the snapshots of the records passed to Track(), keyed by
+// record pointer. Each snapshot maps the index of a field, formatted as a
+// string, to a comparable representation of its value.
+var tracker = struct {
+	sync.Mutex
+	snapMap map[interface{}]map[string]interface{}
+}{snapMap: make(map[interface{}]map[string]interface{})}
+
+// trackVal returns a representation of the field value fldVl that can be
+// compared with reflect.DeepEqual() and that does not share memory with the
+// field, so that a change made in place, for example to an element of a
+// blob, is detected.
+func trackVal(fldVl reflect.Value) interface{} {
+	if fldVl.CanAddr() && fldVl.Addr().Type().Implements(valuerType) {
+		fldVl = fldVl.Addr()
+	}
+	if fldVl.Type().Implements(valuerType) {
+		val, err := fldVl.Interface().(Valuer).Value()
+		if err != nil {
+			return err.Error()
+		}
+		if val == nil {
+			return nil
+		}
+		fldVl = reflect.ValueOf(val)
+	}
+	switch fldVl.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128, reflect.String:
+		return fldVl.Interface()
+	}
+	if tm, ok := fldVl.Interface().(time.Time); ok {
+		return tm.UTC().Round(0) // Location and monotonic reading are not stored
+	}
+	if fldVl.CanAddr() {
+		fldVl = fldVl.Addr() // Pointer methods such as those of big.Rat are used
+	}
+	buf, err := json.Marshal(fldVl.Interface())
+	if err != nil {
+		return fmt.Sprintf("%#v", fldVl.Interface())
+	}
+	return string(buf)
+}
+
+// trackSnap returns the snapshot of the record recVl.
+func trackSnap(recVl reflect.Value) (snap map[string]interface{}) {
+	snap = make(map[string]interface{})
+	for _, sf := range embedFields(recVl.Type()) {
+		if len(sf.PkgPath) == 0 {
+			snap[fmt.Sprint(sf.Index)] = trackVal(fieldValue(recVl, sf))
+		}
+	}
+	return
+}
+
+// Track records a snapshot of the record pointed to by recPtr, typically one
+// that has just been retrieved, so that UpdateChanged() can later store only
+// the fields that have been modified since. The snapshot is retained until
+// Untrack() is called, so records that are tracked for a short time should be
+// released in that way. Calling Track again replaces the snapshot. For
+// example,
+//
+//	db.RetrieveByID(&rec, id)
+//	qlm.Track(&rec)
+//	defer qlm.Untrack(&rec)
+//	rec.Email = email
+//	db.UpdateChanged(&rec)
+//
+// Track may be called from more than one goroutine.
+func Track(recPtr interface{}) {
+	recVl := reflect.ValueOf(recPtr)
+	if recVl.Kind() != reflect.Ptr || recVl.Elem().Kind() != reflect.Struct {
+		return
+	}
+	snap := trackSnap(recVl.Elem())
+	tracker.Lock()
+	tracker.snapMap[recPtr] = snap
+	tracker.Unlock()
+}
+
+// Untrack discards the snapshot of the record pointed to by recPtr that was
+// recorded by Track().
+func Untrack(recPtr interface{}) {
+	tracker.Lock()
+	delete(tracker.snapMap, recPtr)
+	tracker.Unlock()
+}
+
+// UpdateChanged is like Update() except that only the columns whose fields
+// differ from the snapshot recorded by Track() are stored. The names of these
+// columns are returned. Nothing is stored if no field has changed. Columns
+// with the readonly option are never stored. After a successful update, the
+// snapshot is replaced with the current state of the record, including any
+// fields assigned by qlm. An error is set if the record is not tracked.
+func (db *DbType) UpdateChanged(recPtr interface{}) (fldNames []string) {
+	if db.err != nil {
+		return
+	}
+	dsc := db.dscFromPtr(recPtr)
+	if db.err != nil {
+		return
+	}
+	tracker.Lock()
+	snap, ok := tracker.snapMap[recPtr]
+	tracker.Unlock()
+	if !ok {
+		db.SetErrorf("record of type %v is not tracked; see Track()", dsc.recTp)
+		return
+	}
+	recVl := reflect.ValueOf(recPtr).Elem()
+	for j, sf := range dsc.insert.sfList {
+		nameStr := dsc.insert.nameList[j]
+		if !dsc.readonly(nameStr) &&
+			!reflect.DeepEqual(snap[fmt.Sprint(sf.Index)], trackVal(fieldValue(recVl, sf))) {
+			fldNames = append(fldNames, nameStr)
+		}
+	}
+	if len(fldNames) > 0 {
+		db.Update(recPtr, fldNames...)
+		if db.err != nil {
+			return nil
+		}
+		Track(recPtr)
+	}
+	return
+}
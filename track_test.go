@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the update of only those fields of a record that
+// have changed since it was retrieved.
+func ExampleDbType_UpdateChanged() {
+	type custType struct {
+		ID    int64  `ql_table:"cust"`
+		Name  string `ql:"*"`
+		Email string `ql:"*"`
+		Photo []byte `ql:"*"`
+		Ver   int64  `ql:"*,version"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&custType{})
+	list := []custType{{Name: "Ann", Email: "ann@example.com", Photo: []byte{1, 2, 3}}}
+	db.Insert(list)
+	var rec custType
+	db.RetrieveByID(&rec, list[0].ID)
+	qlm.Track(&rec)
+	defer qlm.Untrack(&rec)
+	fmt.Println(db.UpdateChanged(&rec))
+	rec.Email = "ann@example.org"
+	rec.Photo[1] = 20
+	fmt.Println(db.UpdateChanged(&rec), rec.Ver)
+	fmt.Println(db.UpdateChanged(&rec))
+	db.RetrieveByID(&rec, rec.ID)
+	fmt.Println(rec.Name, rec.Email, rec.Photo, rec.Ver)
+	other := rec
+	db.UpdateChanged(&other)
+	fmt.Println(db.Error())
+	db.ClearError()
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// []
+	// [Email Photo] 1
+	// []
+	// Ann ann@example.org [1 20 3] 1
+	// record of type qlm_test.custType is not tracked; see Track()
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+// Transact runs fn within a transaction. The transaction is committed if fn
+// returns nil and the database has no error; otherwise it is rolled back. If
+// fn panics, the transaction is rolled back and the panic continues. The
+// argument passed to fn is db itself; it is provided so that the body of fn
+// reads naturally, for example
+//
+//	err := db.Transact(func(tx *qlm.DbType) error {
+//		tx.Insert(list)
+//		tx.Update(&acct, "Balance")
+//		return nil
+//	})
+//
+// An error returned by fn is assigned to the database unless it already has
+// one. The error of the database is returned. Calls to Transact may be
+// nested; an inner call that fails rolls back only its own changes, although
+// the database error that results causes the outer call to roll back as well
+// unless it is cleared.
+func (db *DbType) Transact(fn func(tx *DbType) error) error {
+	if db.err != nil {
+		return db.err
+	}
+	db.TransactBegin()
+	if db.err != nil {
+		return db.err
+	}
+	done := false
+	defer func() {
+		if !done {
+			db.transactEnd(false)
+		}
+	}()
+	err := fn(db)
+	if err != nil && db.err == nil {
+		db.err = err
+	}
+	done = true
+	db.transactEnd(db.err == nil)
+	return db.err
+}
+
+// ReadTransact runs fn within a transaction that is always rolled back. The
+// retrievals made by fn observe a consistent snapshot of the database: ql
+// serializes transactions, so changes made through other sessions wait until
+// fn returns. Any change made by fn itself is discarded. The argument passed
+// to fn is db itself. An error returned by fn is assigned to the database
+// unless it already has one. The error of the database is returned.
+func (db *DbType) ReadTransact(fn func(tx *DbType) error) error {
+	if db.err != nil {
+		return db.err
+	}
+	db.TransactBegin()
+	if db.err != nil {
+		return db.err
+	}
+	defer db.transactEnd(false)
+	err := fn(db)
+	if err != nil && db.err == nil {
+		db.err = err
+	}
+	return db.err
+}
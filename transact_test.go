@@ -0,0 +1,125 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+	"time"
+)
+
+// This example demonstrates transactions that are committed or rolled back
+// depending on the outcome of a function.
+func ExampleDbType_Transact() {
+	type acctType struct {
+		ID      int64  `ql_table:"acct"`
+		Owner   string `ql:"*"`
+		Balance int64  `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&acctType{})
+	list := []acctType{{0, "ann", 100}, {0, "bob", 20}}
+	db.Insert(list)
+	transfer := func(from, to acctType, amt int64) error {
+		return db.Transact(func(tx *qlm.DbType) error {
+			from.Balance -= amt
+			to.Balance += amt
+			tx.Update(&from, "Balance")
+			tx.Update(&to, "Balance")
+			if from.Balance < 0 {
+				return fmt.Errorf("insufficient funds in account of %s", from.Owner)
+			}
+			return nil
+		})
+	}
+	show := func() {
+		var accts []acctType
+		db.Retrieve(&accts, "ORDER BY Owner")
+		for _, rec := range accts {
+			fmt.Printf("  %s %d\n", rec.Owner, rec.Balance)
+		}
+	}
+	fmt.Println(transfer(list[0], list[1], 30))
+	show()
+	fmt.Println(transfer(list[1], list[0], 80))
+	db.ClearError()
+	show()
+	func() {
+		defer func() {
+			fmt.Println("recovered:", recover())
+		}()
+		db.Transact(func(tx *qlm.DbType) error {
+			tx.Insert([]acctType{{0, "cy", 5}})
+			panic("unexpected condition")
+		})
+	}()
+	show()
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// <nil>
+	//   ann 70
+	//   bob 50
+	// insufficient funds in account of bob
+	//   ann 70
+	//   bob 50
+	// recovered: unexpected condition
+	//   ann 70
+	//   bob 50
+}
+
+// This example demonstrates a report made of several retrievals that see the
+// same state of the database while another session inserts records.
+func ExampleDbType_ReadTransact() {
+	type recType struct {
+		ID   int64  `ql_table:"rec"`
+		Name string `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&recType{})
+	db.Insert([]recType{{0, "alpha"}, {0, "beta"}})
+	done := make(chan bool)
+	err := db.ReadTransact(func(tx *qlm.DbType) error {
+		var aList, bList []recType
+		tx.Retrieve(&aList, "")
+		go func() {
+			s := db.Session()
+			s.Insert([]recType{{0, "gamma"}})
+			s.Close()
+			done <- true
+		}()
+		time.Sleep(10 * time.Millisecond)
+		tx.Retrieve(&bList, "")
+		fmt.Println(len(aList), len(bList))
+		return nil
+	})
+	<-done
+	fmt.Println(err)
+	var list []recType
+	db.Retrieve(&list, "")
+	fmt.Println(len(list))
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// 2 2
+	// <nil>
+	// 3
+}
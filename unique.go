@@ -0,0 +1,58 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"errors"
+	"strings"
+)
+
+// DuplicateError is the error that is set when an insertion or update would
+// result in two records having the same value in a column with the "unique"
+// option, for example
+//
+//	Email string `ql:"*,unique"`
+//
+// Err is the error reported by ql.
+type DuplicateError struct {
+	Err error
+}
+
+func (e DuplicateError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error reported by ql.
+func (e DuplicateError) Unwrap() error {
+	return e.Err
+}
+
+// IsDuplicate returns true if err is, or wraps, a DuplicateError, that is,
+// if it indicates the violation of a unique index.
+func IsDuplicate(err error) bool {
+	var dup DuplicateError
+	return errors.As(err, &dup)
+}
+
+// duplicateCheck returns err converted to a DuplicateError if it reports the
+// violation of a unique index; otherwise err is returned unchanged.
+func duplicateCheck(err error) error {
+	if err != nil && strings.Contains(err.Error(), "cannot insert into unique index") {
+		err = DuplicateError{err}
+	}
+	return err
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Upsert inserts the record pointed to by recPtr or, if a record with the
+// same values in the columns conflictCols already exists, updates all of the
+// existing record's columns. If no columns are specified, records are matched
+// by identifier, so a record with a zero ID is always inserted. Either way,
+// the ID field of the record is assigned the identifier of the inserted or
+// updated record. The lookup and the write occur in a single transaction.
+// If more than one existing record matches, all are left unchanged and an
+// error is set. If the record type has a version field, an existing record is
+// replaced regardless of the version in recPtr; the field is assigned the
+// stored version plus one. It is recommended that the conflict columns be
+// indexed.
+func (db *DbType) Upsert(recPtr interface{}, conflictCols ...string) {
+	if db.err != nil {
+		return
+	}
+	dsc := db.dscFromPtr(recPtr)
+	if !db.writable(dsc) {
+		return
+	}
+	recVl := reflect.ValueOf(recPtr).Elem()
+	idVl := valueList(recVl, []reflect.StructField{dsc.idSf})[0]
+	var condList []string
+	var prms []interface{}
+	if len(conflictCols) == 0 {
+		strListAppend(&condList, "id() == ?1")
+		prms = append(prms, idVl.Int())
+	}
+	for _, colStr := range conflictCols {
+		sf, ok := dsc.nameMap[colStr]
+		if !ok {
+			db.SetErrorf("field %s not found in table %s", colStr, dsc.tblStr)
+			return
+		}
+		prms = append(prms, valList(recVl, []reflect.StructField{sf})[0])
+		strListAppend(&condList, "%s == ?%d", colStr, len(prms))
+	}
+	tailStr := "WHERE " + strings.Join(condList, " && ")
+	if len(dsc.soft.nameStr) > 0 {
+		tailStr = whereAnd(tailStr, dsc.soft.nameStr+" IS NULL")
+	}
+	db.TransactBegin()
+	idList := db.idList(dsc, tailStr, prms...)
+	if db.err == nil {
+		switch len(idList) {
+		case 0:
+			sliceVl := reflect.Append(reflect.MakeSlice(reflect.SliceOf(dsc.recTp), 0, 1), recVl)
+			db.Insert(sliceVl.Interface())
+			if db.err == nil {
+				recVl.Set(sliceVl.Index(0))
+			}
+		case 1:
+			idVl.SetInt(idList[0])
+			if len(dsc.version.nameStr) > 0 {
+				// Adopt the stored version so that Update() does not report
+				// the record as stale
+				row := db.firstRow(fmt.Sprintf("SELECT %s FROM %s WHERE id() == ?1;",
+					dsc.version.nameStr, dsc.tblStr), idList[0])
+				if db.err == nil && len(row) > 0 && row[0] != nil {
+					valueList(recVl, []reflect.StructField{dsc.version.sf})[0].SetInt(row[0].(int64))
+				}
+			}
+			db.Update(recPtr, "*")
+		default:
+			db.err = fmt.Errorf("%d records of table %s match %s", len(idList), dsc.tblStr, tailStr)
+		}
+	}
+	db.transactEnd(db.err == nil)
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+func strIf(cond bool, aStr, bStr string) string {
+	if cond {
+		return aStr
+	}
+	return bStr
+}
+
+// This example demonstrates the insertion or update of records keyed by a
+// column other than the identifier.
+func ExampleDbType_Upsert() {
+	type priceType struct {
+		ID    int64   `ql_table:"price"`
+		Sku   string  `ql:"*" ql_index:"*"`
+		Price float64 `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&priceType{})
+	idMap := make(map[int64]bool)
+	for _, rec := range []priceType{{0, "A100", 2.5}, {0, "B200", 7}, {0, "A100", 2.75}} {
+		db.Upsert(&rec, "Sku")
+		fmt.Printf("%s %s\n", rec.Sku, strIf(idMap[rec.ID], "updated", "inserted"))
+		idMap[rec.ID] = true
+	}
+	var list []priceType
+	db.Retrieve(&list, "ORDER BY Sku")
+	for _, rec := range list {
+		fmt.Printf("%s %.2f\n", rec.Sku, rec.Price)
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// A100 inserted
+	// B200 inserted
+	// A100 updated
+	// A100 2.75
+	// B200 7.00
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+)
+
+// Valuer is implemented by a field type that is not supported by ql but that
+// can convert itself to a value that is. The value returned by Value() must
+// be nil, to store NULL, or of type int64, float64, bool, []byte, string or
+// time.Time. The method set matches that of driver.Valuer in the standard
+// library, so types written for database/sql, such as many UUID and decimal
+// types, can be used in qlm records without change.
+type Valuer interface {
+	Value() (driver.Value, error)
+}
+
+// Scanner is implemented by a pointer to a field type that is not supported
+// by ql but that can assign itself from a value retrieved from ql. The value
+// passed to Scan() is nil if the column is NULL. The method set matches that
+// of sql.Scanner in the standard library.
+type Scanner interface {
+	Scan(src interface{}) error
+}
+
+var (
+	valuerType  = reflect.TypeOf((*Valuer)(nil)).Elem()
+	scannerType = reflect.TypeOf((*Scanner)(nil)).Elem()
+)
+
+// nullTypeMap associates the null wrapper types of database/sql with the ql
+// types of their columns. Their zero values convert to NULL, so the column
+// type cannot be determined from them. The integer types are stored as int64
+// because that is the type their Value() methods return.
+var nullTypeMap = map[reflect.Type]string{
+	reflect.TypeOf(sql.NullBool{}):    "bool",
+	reflect.TypeOf(sql.NullByte{}):    "int64",
+	reflect.TypeOf(sql.NullFloat64{}): "float64",
+	reflect.TypeOf(sql.NullInt16{}):   "int64",
+	reflect.TypeOf(sql.NullInt32{}):   "int64",
+	reflect.TypeOf(sql.NullInt64{}):   "int64",
+	reflect.TypeOf(sql.NullString{}):  "string",
+	reflect.TypeOf(sql.NullTime{}):    "time",
+}
+
+// valuerSet prepares the descriptor dsc for the field sf if its type
+// implements Valuer and a pointer to it implements Scanner. The ql type of
+// the column is taken from the type option of the tag if present, then from
+// nullTypeMap, and otherwise from the value returned by the zero value of the
+// field type. The column type is returned, or the empty string if sf is not
+// converted.
+func (db *DbType) valuerSet(dsc *qlDscType, sf reflect.StructField, sqlStr string,
+	optMap map[string]string) (typeStr string) {
+	if !sf.Type.Implements(valuerType) || !reflect.PtrTo(sf.Type).Implements(scannerType) ||
+		sf.Type.Kind() == reflect.Ptr || sf.Type.Kind() == reflect.Interface {
+		return
+	}
+	typeStr, ok := optMap["type"]
+	if !ok {
+		typeStr, ok = nullTypeMap[sf.Type]
+	}
+	if !ok {
+		val, err := reflect.Zero(sf.Type).Interface().(Valuer).Value()
+		if err == nil && val != nil {
+			typeStr = qlTypeStr(reflect.TypeOf(val))
+		}
+	}
+	if typeMap[typeStr] {
+		dsc.valuer[sqlStr] = true
+	} else {
+		db.SetErrorf("cannot determine ql type of field %s; specify it with the type option", sf.Name)
+		typeStr = ""
+	}
+	return
+}
+
+// valuerStore returns the value to be passed to ql for the column nameStr
+// given the field value val, which implements Valuer.
+func (db *DbType) valuerStore(nameStr string, val interface{}) (res interface{}) {
+	var err error
+	if res, err = val.(Valuer).Value(); err != nil {
+		db.SetErrorf("cannot convert value of column %s: %s", nameStr, err)
+	}
+	return
+}
+
+// scannerLoad assigns the value val retrieved from ql to fldVl if its address
+// implements Scanner. True is returned if the assignment was made this way.
+func scannerLoad(fldVl reflect.Value, val interface{}) (ok bool, err error) {
+	if fldVl.CanAddr() {
+		var sc Scanner
+		if sc, ok = fldVl.Addr().Interface().(Scanner); ok {
+			err = sc.Scan(val)
+		}
+	}
+	return
+}
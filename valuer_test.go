@@ -0,0 +1,139 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"github.com/jung-kurt/qlm"
+	"net"
+	"time"
+)
+
+type colorType int
+
+const (
+	colorRed colorType = iota
+	colorGreen
+	colorBlue
+)
+
+var colorList = []string{"red", "green", "blue"}
+
+// Value satisfies qlm.Valuer; colors are stored by name.
+func (c colorType) Value() (driver.Value, error) {
+	return colorList[c], nil
+}
+
+// Scan satisfies qlm.Scanner.
+func (c *colorType) Scan(src interface{}) error {
+	for j, str := range colorList {
+		if str == src {
+			*c = colorType(j)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown color %v", src)
+}
+
+type addrType struct {
+	net.IP
+}
+
+// Value satisfies qlm.Valuer; the zero address is stored as NULL.
+func (a addrType) Value() (driver.Value, error) {
+	if a.IP == nil {
+		return nil, nil
+	}
+	return a.IP.String(), nil
+}
+
+// Scan satisfies qlm.Scanner.
+func (a *addrType) Scan(src interface{}) error {
+	a.IP = nil
+	if str, ok := src.(string); ok {
+		a.IP = net.ParseIP(str)
+	}
+	return nil
+}
+
+// This example demonstrates fields of types that convert themselves to and
+// from values that ql supports.
+func ExampleValuer() {
+	type hostType struct {
+		ID    int64     `ql_table:"host"`
+		Name  string    `ql:"*"`
+		Color colorType `ql:"*"`
+		Addr  addrType  `ql:"*,type=string"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&hostType{})
+	db.Insert([]hostType{{0, "alpha", colorBlue, addrType{net.ParseIP("10.0.0.7")}},
+		{0, "beta", colorGreen, addrType{}}})
+	var list []hostType
+	db.Retrieve(&list, "WHERE Color != ?1 ORDER BY Name", "red")
+	for _, host := range list {
+		fmt.Println(host.Name, colorList[host.Color], host.Addr.IP)
+	}
+	for _, colStr := range []string{"Color", "Addr"} {
+		fld, _ := db.Describe(&hostType{}).Column(colStr)
+		fmt.Println(colStr, fld.Type)
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// alpha blue 10.0.0.7
+	// beta green <nil>
+	// Color string
+	// Addr string
+}
+
+// This example demonstrates fields of the null wrapper types of
+// database/sql.
+func ExampleValuer_null() {
+	type personType struct {
+		ID    int64           `ql_table:"person"`
+		Name  string          `ql:"*"`
+		Email sql.NullString  `ql:"*"`
+		Age   sql.NullInt32   `ql:"*"`
+		Score sql.NullFloat64 `ql:"*"`
+		Born  sql.NullTime    `ql:"*"`
+	}
+	born := time.Date(1990, 4, 1, 0, 0, 0, 0, time.UTC)
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&personType{})
+	db.Insert([]personType{
+		{Name: "ann", Email: sql.NullString{String: "ann@example.com", Valid: true},
+			Age: sql.NullInt32{Int32: 34, Valid: true}, Born: sql.NullTime{Time: born, Valid: true}},
+		{Name: "bob", Score: sql.NullFloat64{Float64: 7.5, Valid: true}},
+	})
+	var list []personType
+	db.Retrieve(&list, "WHERE Email IS NULL || Age > ?1 ORDER BY Name", int64(30))
+	for _, p := range list {
+		fmt.Println(p.Name, p.Email, p.Age, p.Score, p.Born.Valid, p.Born.Time.Year())
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// ann {ann@example.com true} {34 true} {0 false} true 1990
+	// bob { false} {0 false} {7.5 true} false 1
+}
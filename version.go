@@ -0,0 +1,54 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"errors"
+	"reflect"
+)
+
+// ErrStaleRecord is the error that is set by Update() when the record to be
+// updated has a version field and the version stored in the database no
+// longer matches the version of the record. It indicates that another writer
+// has updated the record since it was retrieved.
+var ErrStaleRecord = errors.New("record has been modified since it was retrieved")
+
+// versionSet registers the integer field sf, which has the version option in
+// its tag, as the version column of the table described by dsc.
+func (db *DbType) versionSet(dsc *qlDscType, sf reflect.StructField, sqlStr string) {
+	switch {
+	case sf.Type.Kind() != reflect.Int64:
+		db.SetErrorf("version option requires field %s to be of type int64", sf.Name)
+	case len(dsc.version.nameStr) > 0:
+		db.SetErrorf("multiple fields have version option")
+	default:
+		dsc.version.nameStr, dsc.version.sf = sqlStr, sf
+	}
+}
+
+// versionNext increments the version field of the record recVl if its type
+// has one. The returned function restores the previous value.
+func (dsc qlDscType) versionNext(recVl reflect.Value) (restore func()) {
+	restore = func() {}
+	if len(dsc.version.nameStr) > 0 {
+		fldVl := valueList(recVl, []reflect.StructField{dsc.version.sf})[0]
+		ver := fldVl.Int()
+		fldVl.SetInt(ver + 1)
+		restore = func() { fldVl.SetInt(ver) }
+	}
+	return
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the detection of conflicting updates with a
+// version field. Upsert() replaces a record whatever its version, and
+// Increment() advances the version like Update().
+func ExampleErrStaleRecord() {
+	type acctType struct {
+		ID      int64   `ql_table:"acct"`
+		Owner   string  `ql:"*"`
+		Balance float64 `ql:"*"`
+		Logins  int64   `ql:"*"`
+		Ver     int64   `ql:"ver,version"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&acctType{})
+	db.Insert([]acctType{{Owner: "ann", Balance: 100}})
+	var a, b []acctType
+	db.Retrieve(&a, "")
+	db.Retrieve(&b, "")
+	a[0].Balance += 50
+	db.Update(&a[0], "Balance")
+	fmt.Println(db.Error(), a[0].Ver)
+	b[0].Balance -= 30
+	db.Update(&b[0], "Balance")
+	fmt.Println(db.Error() == qlm.ErrStaleRecord, b[0].Ver)
+	db.ClearError()
+	b = nil
+	db.Retrieve(&b, "")
+	b[0].Balance -= 30
+	db.Update(&b[0], "*")
+	fmt.Println(db.Error(), b[0].Ver, b[0].Balance)
+	rec := acctType{ID: b[0].ID, Owner: "ann", Balance: 80}
+	db.Upsert(&rec)
+	fmt.Println(db.Error(), rec.Ver)
+	db.Increment(&acctType{}, "Logins", 1, "WHERE id() == ?1", rec.ID)
+	fmt.Println(db.Error())
+	db.Update(&rec, "Balance")
+	fmt.Println(db.Error() == qlm.ErrStaleRecord)
+	db.ClearError()
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// <nil> 1
+	// true 0
+	// <nil> 2 120
+	// <nil> 3
+	// <nil>
+	// true
+}
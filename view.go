@@ -0,0 +1,76 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// View associates the record type pointed to by recPtr with the ql SELECT
+// statement selStr rather than with a table. The type is declared like any
+// other record type; the name in its "ql_table" tag serves as the alias of
+// the view and its "ql" tags name the columns of the SELECT result. After
+// registration, Retrieve and the other read-only methods treat the view like
+// a table, so a tail clause can filter and order its rows. Methods that would
+// modify the database, such as Insert, Update, Delete and TableCreate, set an
+// error when given a view type. For example,
+//
+//	type totalType struct {
+//		ID    int64   `ql_table:"total"`
+//		Cust  string  `ql:"*"`
+//		Total float64 `ql:"*"`
+//	}
+//	db.View(&totalType{}, "SELECT Cust, sum(Amt) AS Total FROM sale GROUP BY Cust")
+//	db.Retrieve(&list, "WHERE Total > ?1 ORDER BY Cust", 100.0)
+//
+// The ID field of a view record is zero unless the rows of selStr correspond
+// directly to the rows of a table, that is, unless the statement does not
+// group or join its source.
+func (db *DbType) View(recPtr interface{}, selStr string) {
+	if db.err != nil {
+		return
+	}
+	recTp := reflect.TypeOf(recPtr)
+	if recTp == nil || recTp.Kind() != reflect.Ptr {
+		db.SetErrorf("expecting record pointer, got %v", recTp)
+		return
+	}
+	recTp = recTp.Elem()
+	db.viewMap[recTp] = strings.TrimRight(strings.TrimSpace(selStr), "; \t\n")
+	db.dscCache.remove(recTp) // Rebuild descriptor with view
+	db.dscFromType(recTp)
+}
+
+// fromStr returns the source of the records described by dsc in the form
+// required by the FROM clause of a SELECT statement.
+func (dsc qlDscType) fromStr() string {
+	if len(dsc.view.selStr) > 0 {
+		return fmt.Sprintf("(%s) AS %s", dsc.view.selStr, dsc.tblStr)
+	}
+	return dsc.tblStr
+}
+
+// writable returns true if the database is free of errors and the records
+// described by dsc may be modified. An error is set if dsc describes a view.
+func (db *DbType) writable(dsc qlDscType) bool {
+	if db.err == nil && len(dsc.view.selStr) > 0 {
+		db.SetErrorf("%s is a read-only view", dsc.tblStr)
+	}
+	return db.err == nil
+}
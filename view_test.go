@@ -0,0 +1,58 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates a record type that is backed by a SELECT
+// statement rather than a table.
+func ExampleDbType_View() {
+	type saleType struct {
+		ID   int64   `ql_table:"sale"`
+		Cust string  `ql:"*"`
+		Amt  float64 `ql:"*"`
+	}
+	type totalType struct {
+		ID    int64   `ql_table:"total"`
+		Cust  string  `ql:"*"`
+		Count int64   `ql:"*"`
+		Total float64 `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&saleType{})
+	db.Insert([]saleType{{0, "ann", 40}, {0, "bob", 15}, {0, "ann", 85}, {0, "cy", 120}})
+	db.View(&totalType{}, "SELECT Cust, count(*) AS Count, sum(Amt) AS Total FROM sale GROUP BY Cust")
+	var list []totalType
+	db.Retrieve(&list, "WHERE Total > ?1 ORDER BY Cust", 100.0)
+	for _, rec := range list {
+		fmt.Printf("%-3s %d %6.2f\n", rec.Cust, rec.Count, rec.Total)
+	}
+	db.Insert([]totalType{{0, "dee", 1, 10}})
+	fmt.Println(db.Error())
+	db.ClearError()
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// ann 2 125.00
+	// cy  1 120.00
+	// total is a read-only view
+}
@@ -0,0 +1,197 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// UpdateWhere assigns the values in fldValues to the records of the type
+// pointed to by recPtr that satisfy tailStr and its parameters, and returns
+// the number of records updated. The keys of fldValues are the names used in
+// the database, that is, the names identified with the "ql" tag in the
+// structure definition. Each value must be assignable to its field, have the
+// same kind as the field, as a string does for a field of a named string type,
+// or be a number whose value the numeric field can hold exactly; nil stores
+// NULL. For example, 65 is rejected for a string field and 2.5 for an integer
+// field. Between floating-point types, rounding to the precision of the field
+// is accepted. For example,
+//
+//	n := db.UpdateWhere(&orderType{}, map[string]interface{}{"Status": "closed"},
+//		"WHERE Placed < ?1", cutoff)
+//
+// The records are updated with a single statement within a transaction.
+// Columns maintained by qlm, such as UpdatedAt, version fields, checksums
+// and collation keys, are updated as well. Unlike Update, records are not
+// loaded beforehand, so BeforeUpdate hooks are not called. Records that have
+// been marked as deleted are not updated.
+func (db *DbType) UpdateWhere(recPtr interface{}, fldValues map[string]interface{},
+	tailStr string, prms ...interface{}) (count int64) {
+	if db.err != nil {
+		return
+	}
+	dsc := db.dscFromPtr(recPtr)
+	if !db.writable(dsc) {
+		return
+	}
+	if len(fldValues) == 0 {
+		db.SetErrorf("at least one field value expected in function UpdateWhere")
+		return
+	}
+	nameList := make([]string, 0, len(fldValues))
+	for nameStr := range fldValues {
+		nameList = append(nameList, nameStr)
+	}
+	sort.Strings(nameList) // Consistent statement text for the statement cache
+	var eqList []string
+	var args []interface{}
+	for _, nameStr := range nameList {
+		sf, ok := dsc.nameMap[nameStr]
+		switch {
+		case !ok:
+			db.SetErrorf("field %s not found in table %s", nameStr, dsc.tblStr)
+		case dsc.readonly(nameStr):
+			db.SetErrorf("column %s is read-only and cannot be assigned by UpdateWhere", nameStr)
+		case len(dsc.geo.hashStr) > 0 && (nameStr == dsc.geo.latStr || nameStr == dsc.geo.lonStr):
+			db.SetErrorf("column %s determines %s and cannot be assigned by UpdateWhere", nameStr, dsc.geo.hashStr)
+		}
+		if db.err != nil {
+			return
+		}
+		val := fldValues[nameStr]
+		if val != nil {
+			vl, ok := valueConvert(reflect.ValueOf(val), sf.Type)
+			if !ok {
+				db.SetErrorf("value for field %s must be of type %v, got %T", nameStr, sf.Type, val)
+				return
+			}
+			val = db.storeVal(dsc, nameStr, vl.Interface())
+		}
+		args = append(args, val)
+		strListAppend(&eqList, "%s = ?%d", nameStr, len(args))
+	}
+	for _, nameStr := range dsc.auto.updateStr {
+		args = append(args, time.Now())
+		strListAppend(&eqList, "%s = ?%d", nameStr, len(args))
+	}
+	if len(dsc.version.nameStr) > 0 {
+		strListAppend(&eqList, "%s = %s + 1", dsc.version.nameStr, dsc.version.nameStr)
+	}
+	if len(dsc.soft.nameStr) > 0 {
+		tailStr = whereAnd(tailStr, dsc.soft.nameStr+" IS NULL")
+	}
+	db.TransactBegin()
+	idList := db.idList(dsc, tailStr, prms...)
+	if db.err == nil && len(idList) > 0 {
+		condStr, idPrms := inCond("id()", idList, len(args))
+		_, _ = db.Exec(fmt.Sprintf("UPDATE %s %s WHERE %s;", dsc.tblStr,
+			strings.Join(eqList, ", "), condStr), append(args, idPrms...)...)
+		db.derivedRepair(dsc, idList)
+		db.journalIDs(dsc, ChangeUpdate, idList, nameList...)
+		count = int64(len(idList))
+	}
+	db.transactEnd(db.err == nil)
+	if db.err != nil {
+		count = 0
+	}
+	return
+}
+
+// UpdateFields assigns the values in fldValues to the record of the type
+// pointed to by recPtr whose identifier is that of the ID field of recPtr.
+// Only the ID field needs to be populated, which suits handlers of partial
+// updates such as HTTP PATCH requests. For example,
+//
+//	rec := custType{ID: id}
+//	db.UpdateFields(&rec, map[string]interface{}{"last_name": "Smith"})
+//
+// The keys and values of fldValues are interpreted as they are by
+// UpdateWhere(), and the columns maintained by qlm are updated in the same
+// way. If the update succeeds, the values are also assigned to the
+// corresponding fields of the record pointed to by recPtr; its other fields,
+// including those maintained by qlm, are left unchanged. ErrNotFound is set
+// if no such record exists.
+func (db *DbType) UpdateFields(recPtr interface{}, fldValues map[string]interface{}) {
+	dsc := db.dscFromPtr(recPtr)
+	if db.err != nil {
+		return
+	}
+	recVl := reflect.ValueOf(recPtr).Elem()
+	count := db.UpdateWhere(recPtr, fldValues, "WHERE id() == ?1", fieldValue(recVl, dsc.idSf).Int())
+	if db.err == nil && count == 0 {
+		db.err = ErrNotFound
+	}
+	if db.err == nil {
+		for nameStr, val := range fldValues {
+			fldVl := fieldValue(recVl, dsc.nameMap[nameStr])
+			if val == nil {
+				fldVl.Set(reflect.Zero(fldVl.Type()))
+			} else {
+				vl, _ := valueConvert(reflect.ValueOf(val), fldVl.Type())
+				fldVl.Set(vl) // Validated by UpdateWhere()
+			}
+		}
+	}
+}
+
+// numKindMap identifies the kinds between which valueConvert() converts
+// numbers.
+var numKindMap = map[reflect.Kind]bool{
+	reflect.Int: true, reflect.Int8: true, reflect.Int16: true, reflect.Int32: true, reflect.Int64: true,
+	reflect.Uint: true, reflect.Uint8: true, reflect.Uint16: true, reflect.Uint32: true, reflect.Uint64: true,
+	reflect.Float32: true, reflect.Float64: true,
+}
+
+// numNegative reports whether the number vl is less than zero.
+func numNegative(vl reflect.Value) bool {
+	switch vl.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return vl.Int() < 0
+	case reflect.Float32, reflect.Float64:
+		return vl.Float() < 0
+	}
+	return false
+}
+
+// valueConvert returns vl converted to the type tp. ok is false if vl is not
+// assignable to tp and the conversion would alter its value, as converting a
+// number to a string or a fraction to an integer does.
+func valueConvert(vl reflect.Value, tp reflect.Type) (cv reflect.Value, ok bool) {
+	switch {
+	case vl.Type().AssignableTo(tp):
+		return vl, true
+	case !vl.Type().ConvertibleTo(tp):
+		return
+	case vl.Kind() == tp.Kind() && !numKindMap[tp.Kind()]:
+		return vl.Convert(tp), true
+	case !numKindMap[vl.Kind()] || !numKindMap[tp.Kind()]:
+		return
+	}
+	cv = vl.Convert(tp)
+	floatMap := map[reflect.Kind]bool{reflect.Float32: true, reflect.Float64: true}
+	if floatMap[vl.Kind()] && floatMap[tp.Kind()] {
+		return cv, true
+	}
+	// The sign is compared because a negative integer survives the round
+	// trip through an unsigned type
+	ok = numNegative(vl) == numNegative(cv) && cv.Convert(vl.Type()).Interface() == vl.Interface()
+	return
+}
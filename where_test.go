@@ -0,0 +1,107 @@
+/*
+ * Copyright (c) 2015 Kurt Jung (Gmail: kurt.w.jung)
+ *
+ * Permission to use, copy, modify, and distribute this software for any
+ * purpose with or without fee is hereby granted, provided that the above
+ * copyright notice and this permission notice appear in all copies.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES
+ * WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF
+ * MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR
+ * ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES
+ * WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN
+ * ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF
+ * OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+ */
+
+package qlm_test
+
+import (
+	"fmt"
+	"github.com/jung-kurt/qlm"
+)
+
+// This example demonstrates the assignment of values to every record that
+// satisfies a condition.
+func ExampleDbType_UpdateWhere() {
+	type orderType struct {
+		qlm.Model `ql_table:"ord"`
+		Cust      string `ql:"*"`
+		Status    string `ql:"*"`
+		Priority  int32  `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&orderType{})
+	db.Insert([]orderType{{Cust: "ann", Status: "open"}, {Cust: "bob", Status: "open"},
+		{Cust: "ann", Status: "held"}})
+	n := db.UpdateWhere(&orderType{}, map[string]interface{}{"Status": "closed", "Priority": 2},
+		"WHERE Cust == ?1 && Status == ?2", "ann", "open")
+	fmt.Println(n)
+	var list []orderType
+	db.Retrieve(&list, "ORDER BY id()")
+	for _, rec := range list {
+		fmt.Println(rec.Cust, rec.Status, rec.Priority, rec.UpdatedAt.After(rec.CreatedAt))
+	}
+	for _, val := range []interface{}{"", 65, 2.5, int64(1 << 40), -1} {
+		fldStr := "Priority"
+		if val == "" {
+			fldStr = "Note"
+		} else if val == 65 {
+			fldStr = "Status"
+		}
+		db.UpdateWhere(&orderType{}, map[string]interface{}{fldStr: val}, "")
+		fmt.Println(db.Error())
+		db.ClearError()
+	}
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// 1
+	// ann closed 2 true
+	// bob open 0 false
+	// ann held 0 false
+	// field Note not found in table ord
+	// value for field Status must be of type string, got int
+	// value for field Priority must be of type int32, got float64
+	// value for field Priority must be of type int32, got int64
+	// <nil>
+}
+
+// This example demonstrates the partial update of a record identified only
+// by its ID field.
+func ExampleDbType_UpdateFields() {
+	type custType struct {
+		qlm.Model `ql_table:"cust"`
+		FirstName string `ql:"first_name"`
+		LastName  string `ql:"last_name"`
+		Visits    int64  `ql:"*"`
+	}
+	db := qlm.DbCreate("data/example.ql")
+	db.TableCreate(&custType{})
+	list := []custType{{FirstName: "Ann", LastName: "Jones", Visits: 4}}
+	db.Insert(list)
+	rec := custType{}
+	rec.ID = list[0].ID
+	db.UpdateFields(&rec, map[string]interface{}{"last_name": "Smith", "Visits": 5})
+	fmt.Printf("[%s] [%s] %d\n", rec.FirstName, rec.LastName, rec.Visits)
+	db.RetrieveByID(&rec, rec.ID)
+	fmt.Println(rec.FirstName, rec.LastName, rec.Visits, rec.UpdatedAt.After(list[0].UpdatedAt))
+	rec.ID = 1000
+	db.UpdateFields(&rec, map[string]interface{}{"last_name": "Brown"})
+	fmt.Println(db.Error())
+	db.ClearError()
+	db.UpdateFields(&rec, map[string]interface{}{"middle_name": "Q"})
+	fmt.Println(db.Error())
+	db.ClearError()
+	db.Close()
+	if db.Err() {
+		fmt.Println(db.Error())
+	}
+	// Output:
+	// [] [Smith] 5
+	// Ann Smith 5 true
+	// record not found
+	// field middle_name not found in table cust
+}